@@ -129,8 +129,10 @@ func main() {
 	}
 	fmt.Println("✓ Bulk created 2 documents")
 
-	// Small delay to ensure indexing is complete
-	time.Sleep(1 * time.Second)
+	// Make the new documents visible to search
+	if err := client.RefreshIndex(ctx, indexName); err != nil {
+		log.Fatalf("Failed to refresh index: %v", err)
+	}
 
 	// === READ Operations ===
 	fmt.Println("=== READ Operations ===")
@@ -217,6 +219,23 @@ func main() {
 		log.Fatalf("Failed to search with range query: %v", err)
 	}
 	fmt.Printf("✓ Found %d documents with 200-400 views\n", len(rangeResults))
+
+	// Combine clauses with the query builder
+	combinedQuery := opensearch.NewQueryBuilder().
+		Must(opensearch.MatchClause("category", "tutorial")).
+		Filter(opensearch.RangeClause("views", 100, nil)).
+		MustNot(opensearch.TermClause("published", false)).
+		Size(20).
+		Sort("views", "desc").
+		Build()
+	combinedResults, err := client.SearchDocuments(ctx, indexName, combinedQuery)
+	if err != nil {
+		log.Fatalf("Failed to search with combined query: %v", err)
+	}
+	fmt.Printf("✓ Found %d published tutorials with at least 100 views, most viewed first\n", len(combinedResults))
+	for _, result := range combinedResults {
+		fmt.Printf("  - %s (views: %v)\n", result["title"], result["views"])
+	}
 	fmt.Println()
 
 	// === UPDATE Operations ===
@@ -0,0 +1,249 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateHistogramBucket is one bucket of a date_histogram aggregation
+type DateHistogramBucket struct {
+	// Key is the start of the bucket in milliseconds since the epoch
+	Key         int64  `json:"key"`
+	KeyAsString string `json:"key_as_string"`
+	DocCount    int64  `json:"doc_count"`
+}
+
+// DateHistogramAggregation creates a date_histogram aggregation that buckets documents by
+// field on calendar intervals such as "1h", "day" or "month"
+func DateHistogramAggregation(field, interval string) map[string]interface{} {
+	return map[string]interface{}{
+		"date_histogram": map[string]interface{}{
+			"field":             field,
+			"calendar_interval": interval,
+		},
+	}
+}
+
+// TermsAggregation creates a terms aggregation with a bucket for each of the size most
+// frequent values of field, e.g. a keyword field
+func TermsAggregation(field string, size int) map[string]interface{} {
+	return map[string]interface{}{
+		"terms": map[string]interface{}{
+			"field": field,
+			"size":  size,
+		},
+	}
+}
+
+// AggregationBuilder nests sub-aggregations in a bucket aggregation, e.g. the average price
+// of each category of a terms aggregation. Builders can be nested by passing the result of
+// one builder's Build as a sub-aggregation of another.
+type AggregationBuilder struct {
+	agg  map[string]interface{}
+	subs map[string]interface{}
+}
+
+// NewAggregationBuilder creates a builder for agg, such as a TermsAggregation
+func NewAggregationBuilder(agg map[string]interface{}) *AggregationBuilder {
+	return &AggregationBuilder{agg: agg, subs: make(map[string]interface{})}
+}
+
+// SubAggregation adds an aggregation called name that runs within each bucket
+func (b *AggregationBuilder) SubAggregation(name string, agg map[string]interface{}) *AggregationBuilder {
+	b.subs[name] = agg
+	return b
+}
+
+// Build returns the aggregation with its sub-aggregations, for WithAggregation. The
+// aggregation given to NewAggregationBuilder is left unchanged.
+func (b *AggregationBuilder) Build() map[string]interface{} {
+	agg := make(map[string]interface{}, len(b.agg)+1)
+	for k, v := range b.agg {
+		agg[k] = v
+	}
+	if len(b.subs) > 0 {
+		subs := make(map[string]interface{}, len(b.subs))
+		for name, sub := range b.subs {
+			subs[name] = sub
+		}
+		agg["aggs"] = subs
+	}
+	return agg
+}
+
+// SearchWithAggregations performs a search query and returns the matching documents along
+// with the aggregations section of the response, keyed by aggregation name
+func (c *Client) SearchWithAggregations(ctx context.Context, index string, query map[string]interface{}) (_ []map[string]interface{}, _ map[string]interface{}, err error) {
+	defer c.observe("SearchWithAggregations", time.Now(), &err)
+
+	var response SearchResponse
+	if err := c.search(ctx, index, query, &response); err != nil {
+		return nil, nil, err
+	}
+
+	return hitsToDocuments(response.Hits.Hits), response.Aggregations, nil
+}
+
+// AggregateOnly runs the aggregations of query without returning any hits, by searching with
+// size 0, and returns the aggregations section of the response keyed by aggregation name.
+// The query passed in is left unchanged.
+func (c *Client) AggregateOnly(ctx context.Context, index string, query map[string]interface{}) (_ map[string]interface{}, err error) {
+	defer c.observe("AggregateOnly", time.Now(), &err)
+
+	body := make(map[string]interface{}, len(query)+1)
+	for k, v := range query {
+		body[k] = v
+	}
+	body["size"] = 0
+
+	var response SearchResponse
+	if err := c.search(ctx, index, body, &response); err != nil {
+		return nil, err
+	}
+	if response.Aggregations == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	return response.Aggregations, nil
+}
+
+// ParseDateHistogramBuckets returns the buckets of the date_histogram aggregation called name
+func ParseDateHistogramBuckets(aggs map[string]interface{}, name string) ([]DateHistogramBucket, error) {
+	var result struct {
+		Buckets []DateHistogramBucket `json:"buckets"`
+	}
+	if err := decodeAggregation(aggs, name, &result); err != nil {
+		return nil, err
+	}
+	return result.Buckets, nil
+}
+
+// TermsBucket is one bucket of a terms aggregation
+type TermsBucket struct {
+	// Key is the field value of the bucket: a string, or a float64 for numeric fields
+	Key      interface{}
+	DocCount int64
+	// Aggregations holds the results of the sub-aggregations within the bucket, to read
+	// with the Parse functions, e.g. ParseMetricValue(bucket.Aggregations, "avg_price")
+	Aggregations map[string]interface{}
+}
+
+// ParseTermsBuckets returns the buckets of the terms aggregation called name
+func ParseTermsBuckets(aggs map[string]interface{}, name string) ([]TermsBucket, error) {
+	var result struct {
+		Buckets []map[string]interface{} `json:"buckets"`
+	}
+	if err := decodeAggregation(aggs, name, &result); err != nil {
+		return nil, err
+	}
+
+	buckets := make([]TermsBucket, 0, len(result.Buckets))
+	for _, raw := range result.Buckets {
+		bucket := TermsBucket{Key: raw["key"], Aggregations: make(map[string]interface{})}
+		if count, ok := raw["doc_count"].(float64); ok {
+			bucket.DocCount = int64(count)
+		}
+		for k, v := range raw {
+			// Besides its key and count, a bucket holds one entry per sub-aggregation
+			if k != "key" && k != "key_as_string" && k != "doc_count" {
+				bucket.Aggregations[k] = v
+			}
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+// decodeAggregation decodes the result of the aggregation called name into v
+func decodeAggregation(aggs map[string]interface{}, name string, v interface{}) error {
+	agg, ok := aggs[name]
+	if !ok {
+		return fmt.Errorf("aggregation %q not found in response", name)
+	}
+
+	data, err := json.Marshal(agg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal aggregation %q: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse aggregation %q: %w", name, err)
+	}
+	return nil
+}
+
+// AvgAggregation creates an avg aggregation of a numeric field
+func AvgAggregation(field string) map[string]interface{} {
+	return metricAggregation("avg", field)
+}
+
+// SumAggregation creates a sum aggregation of a numeric field
+func SumAggregation(field string) map[string]interface{} {
+	return metricAggregation("sum", field)
+}
+
+// MinAggregation creates a min aggregation of a numeric field
+func MinAggregation(field string) map[string]interface{} {
+	return metricAggregation("min", field)
+}
+
+// MaxAggregation creates a max aggregation of a numeric field
+func MaxAggregation(field string) map[string]interface{} {
+	return metricAggregation("max", field)
+}
+
+// StatsAggregation creates a stats aggregation of a numeric field, returning its count,
+// min, max, avg and sum in one go (see ParseStats)
+func StatsAggregation(field string) map[string]interface{} {
+	return metricAggregation("stats", field)
+}
+
+// CardinalityAggregation creates a cardinality aggregation counting the distinct values of
+// field, e.g. a keyword field. Read the (approximate) count with ParseMetricValue.
+func CardinalityAggregation(field string) map[string]interface{} {
+	return metricAggregation("cardinality", field)
+}
+
+// metricAggregation creates a single-field metric aggregation of the given type
+func metricAggregation(aggType, field string) map[string]interface{} {
+	return map[string]interface{}{
+		aggType: map[string]interface{}{
+			"field": field,
+		},
+	}
+}
+
+// Stats is the result of a stats aggregation. Min, Max and Avg are nil when no document
+// has a value for the field.
+type Stats struct {
+	Count int64    `json:"count"`
+	Min   *float64 `json:"min"`
+	Max   *float64 `json:"max"`
+	Avg   *float64 `json:"avg"`
+	Sum   float64  `json:"sum"`
+}
+
+// ParseMetricValue returns the value of the single-value metric aggregation called name,
+// such as an avg, sum or cardinality. It fails if the value is null, e.g. the avg of no documents.
+func ParseMetricValue(aggs map[string]interface{}, name string) (float64, error) {
+	var result struct {
+		Value *float64 `json:"value"`
+	}
+	if err := decodeAggregation(aggs, name, &result); err != nil {
+		return 0, err
+	}
+	if result.Value == nil {
+		return 0, fmt.Errorf("aggregation %q has no value", name)
+	}
+	return *result.Value, nil
+}
+
+// ParseStats returns the result of the stats aggregation called name
+func ParseStats(aggs map[string]interface{}, name string) (Stats, error) {
+	var stats Stats
+	if err := decodeAggregation(aggs, name, &stats); err != nil {
+		return Stats{}, err
+	}
+	return stats, nil
+}
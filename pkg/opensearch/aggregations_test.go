@@ -0,0 +1,374 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDateHistogramAggregation(t *testing.T) {
+	got := DateHistogramAggregation("timestamp", "day")
+	want := map[string]interface{}{
+		"date_histogram": map[string]interface{}{
+			"field":             "timestamp",
+			"calendar_interval": "day",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DateHistogramAggregation() = %v, want %v", got, want)
+	}
+}
+
+func TestWithAggregation(t *testing.T) {
+	query := WithAggregation(MatchAllQuery(), "per_day", DateHistogramAggregation("timestamp", "day"))
+	query = WithAggregation(query, "per_hour", DateHistogramAggregation("timestamp", "1h"))
+
+	aggs, ok := query["aggs"].(map[string]interface{})
+	if !ok || len(aggs) != 2 || aggs["per_day"] == nil || aggs["per_hour"] == nil {
+		t.Errorf("aggs = %v, want per_day and per_hour", query["aggs"])
+	}
+	if query["query"] == nil {
+		t.Error("WithAggregation() dropped the query")
+	}
+}
+
+func TestParseDateHistogramBuckets(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{
+		"hits":{"hits":[]},
+		"aggregations":{"per_day":{"buckets":[
+			{"key_as_string":"2024-01-01T00:00:00.000Z","key":1704067200000,"doc_count":3},
+			{"key_as_string":"2024-01-02T00:00:00.000Z","key":1704153600000,"doc_count":0}
+		]}}
+	}`}
+	client := newStubClient(t, stub)
+
+	query := WithAggregation(WithSize(MatchAllQuery(), 0), "per_day", DateHistogramAggregation("timestamp", "day"))
+	_, aggs, err := client.SearchWithAggregations(context.Background(), "events", query)
+	if err != nil {
+		t.Fatalf("SearchWithAggregations() error = %v", err)
+	}
+	if !strings.Contains(string(stub.sent), `"calendar_interval":"day"`) {
+		t.Errorf("Request body = %s, want the date_histogram aggregation", stub.sent)
+	}
+
+	buckets, err := ParseDateHistogramBuckets(aggs, "per_day")
+	if err != nil {
+		t.Fatalf("ParseDateHistogramBuckets() error = %v", err)
+	}
+	want := []DateHistogramBucket{
+		{Key: 1704067200000, KeyAsString: "2024-01-01T00:00:00.000Z", DocCount: 3},
+		{Key: 1704153600000, KeyAsString: "2024-01-02T00:00:00.000Z", DocCount: 0},
+	}
+	if !reflect.DeepEqual(buckets, want) {
+		t.Errorf("ParseDateHistogramBuckets() = %+v, want %+v", buckets, want)
+	}
+
+	if _, err := ParseDateHistogramBuckets(aggs, "per_week"); err == nil {
+		t.Error("ParseDateHistogramBuckets() of a missing aggregation expected error but got nil")
+	}
+}
+
+func TestSearchWithAggregations_DateHistogram(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-date-histogram"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	documents := []map[string]interface{}{
+		{"event": "login", "timestamp": "2024-01-01T09:00:00Z"},
+		{"event": "logout", "timestamp": "2024-01-01T17:00:00Z"},
+		{"event": "login", "timestamp": "2024-01-03T09:00:00Z"},
+	}
+	if err := client.BulkCreate(ctx, indexName, documents); err != nil {
+		t.Fatalf("Failed to create test documents: %v", err)
+	}
+
+	query := WithAggregation(WithSize(MatchAllQuery(), 0), "per_day", DateHistogramAggregation("timestamp", "day"))
+	_, aggs, err := client.SearchWithAggregations(ctx, indexName, query)
+	if err != nil {
+		t.Fatalf("SearchWithAggregations() error = %v", err)
+	}
+
+	buckets, err := ParseDateHistogramBuckets(aggs, "per_day")
+	if err != nil {
+		t.Fatalf("ParseDateHistogramBuckets() error = %v", err)
+	}
+	// The empty day in between is returned as a bucket with no documents
+	counts := make([]int64, 0, len(buckets))
+	for _, bucket := range buckets {
+		counts = append(counts, bucket.DocCount)
+	}
+	if !reflect.DeepEqual(counts, []int64{2, 0, 1}) {
+		t.Errorf("Bucket counts = %v, want [2 0 1]", counts)
+	}
+	if len(buckets) > 0 && !strings.HasPrefix(buckets[0].KeyAsString, "2024-01-01") {
+		t.Errorf("First bucket = %s, want 2024-01-01", buckets[0].KeyAsString)
+	}
+}
+
+func TestMetricAggregations(t *testing.T) {
+	tests := []struct {
+		name    string
+		agg     map[string]interface{}
+		aggType string
+	}{
+		{"Avg", AvgAggregation("price"), "avg"},
+		{"Sum", SumAggregation("price"), "sum"},
+		{"Min", MinAggregation("price"), "min"},
+		{"Max", MaxAggregation("price"), "max"},
+		{"Stats", StatsAggregation("price"), "stats"},
+		{"Cardinality", CardinalityAggregation("price"), "cardinality"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := map[string]interface{}{
+				tt.aggType: map[string]interface{}{"field": "price"},
+			}
+			if !reflect.DeepEqual(tt.agg, want) {
+				t.Errorf("%sAggregation() = %v, want %v", tt.name, tt.agg, want)
+			}
+		})
+	}
+}
+
+func TestParseMetricValue(t *testing.T) {
+	var aggs map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"avg_price":{"value":12.5},
+		"avg_missing":{"value":null},
+		"price_stats":{"count":4,"min":5,"max":20,"avg":12.5,"sum":50}
+	}`), &aggs)
+	if err != nil {
+		t.Fatalf("Failed to decode aggregations: %v", err)
+	}
+
+	got, err := ParseMetricValue(aggs, "avg_price")
+	if err != nil || got != 12.5 {
+		t.Errorf("ParseMetricValue() = %v, %v, want 12.5", got, err)
+	}
+	if _, err := ParseMetricValue(aggs, "avg_missing"); err == nil {
+		t.Error("ParseMetricValue() of a null value expected error but got nil")
+	}
+	if _, err := ParseMetricValue(aggs, "sum_price"); err == nil {
+		t.Error("ParseMetricValue() of a missing aggregation expected error but got nil")
+	}
+
+	stats, err := ParseStats(aggs, "price_stats")
+	if err != nil {
+		t.Fatalf("ParseStats() error = %v", err)
+	}
+	if stats.Count != 4 || *stats.Min != 5 || *stats.Max != 20 || *stats.Avg != 12.5 || stats.Sum != 50 {
+		t.Errorf("ParseStats() = %+v, want count 4, min 5, max 20, avg 12.5, sum 50", stats)
+	}
+}
+
+func TestSearchWithAggregations_Cardinality(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-cardinality"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	documents := []map[string]interface{}{
+		{"title": "Go in Action", "category": "programming"},
+		{"title": "The Go Programming Language", "category": "programming"},
+		{"title": "Salt Fat Acid Heat", "category": "cooking"},
+		{"title": "Dune", "category": "fiction"},
+		{"title": "Foundation", "category": "fiction"},
+	}
+	if err := client.BulkCreate(ctx, indexName, documents); err != nil {
+		t.Fatalf("Failed to create test documents: %v", err)
+	}
+
+	query := WithAggregation(WithSize(MatchAllQuery(), 0), "categories", CardinalityAggregation("category.keyword"))
+	_, aggs, err := client.SearchWithAggregations(ctx, indexName, query)
+	if err != nil {
+		t.Fatalf("SearchWithAggregations() error = %v", err)
+	}
+
+	count, err := ParseMetricValue(aggs, "categories")
+	if err != nil {
+		t.Fatalf("ParseMetricValue() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Distinct categories = %v, want 3", count)
+	}
+}
+
+func TestAggregationBuilder(t *testing.T) {
+	terms := TermsAggregation("category", 10)
+	agg := NewAggregationBuilder(terms).
+		SubAggregation("avg_price", AvgAggregation("price")).
+		Build()
+
+	got, err := json.Marshal(WithAggregation(WithSize(MatchAllQuery(), 0), "categories", agg))
+	if err != nil {
+		t.Fatalf("Failed to marshal query: %v", err)
+	}
+	want := `{"aggs":{"categories":{"aggs":{"avg_price":{"avg":{"field":"price"}}},"terms":{"field":"category","size":10}}},"query":{"match_all":{}},"size":0}`
+	if string(got) != want {
+		t.Errorf("Query = %s, want %s", got, want)
+	}
+	if _, nested := terms["aggs"]; nested {
+		t.Error("Build() modified the wrapped aggregation")
+	}
+
+	t.Run("Nested builders", func(t *testing.T) {
+		agg := NewAggregationBuilder(TermsAggregation("category", 5)).
+			SubAggregation("brands", NewAggregationBuilder(TermsAggregation("brand", 3)).
+				SubAggregation("max_price", MaxAggregation("price")).
+				Build()).
+			Build()
+
+		brands := agg["aggs"].(map[string]interface{})["brands"].(map[string]interface{})
+		if _, ok := brands["aggs"].(map[string]interface{})["max_price"]; !ok {
+			t.Errorf("Aggregation = %v, want max_price nested within brands", agg)
+		}
+	})
+
+	t.Run("Without sub-aggregations", func(t *testing.T) {
+		agg := NewAggregationBuilder(TermsAggregation("category", 5)).Build()
+		if _, ok := agg["aggs"]; ok {
+			t.Errorf("Build() = %v, want no aggs entry", agg)
+		}
+	})
+}
+
+func TestParseTermsBuckets(t *testing.T) {
+	var aggs map[string]interface{}
+	err := json.Unmarshal([]byte(`{"categories":{"doc_count_error_upper_bound":0,"sum_other_doc_count":0,"buckets":[
+		{"key":"books","doc_count":3,"avg_price":{"value":12.5}},
+		{"key":"games","doc_count":1,"avg_price":{"value":60}}
+	]}}`), &aggs)
+	if err != nil {
+		t.Fatalf("Failed to decode aggregations: %v", err)
+	}
+
+	buckets, err := ParseTermsBuckets(aggs, "categories")
+	if err != nil {
+		t.Fatalf("ParseTermsBuckets() error = %v", err)
+	}
+	if len(buckets) != 2 || buckets[0].Key != "books" || buckets[0].DocCount != 3 {
+		t.Fatalf("ParseTermsBuckets() = %+v, want books (3) and games (1)", buckets)
+	}
+	avg, err := ParseMetricValue(buckets[1].Aggregations, "avg_price")
+	if err != nil || avg != 60 {
+		t.Errorf("avg_price of games = %v, %v, want 60", avg, err)
+	}
+}
+
+func TestSearchWithAggregations_Nested(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-nested-aggregations"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	documents := []map[string]interface{}{
+		{"category": "books", "price": 10},
+		{"category": "books", "price": 20},
+		{"category": "games", "price": 60},
+	}
+	if err := client.BulkCreate(ctx, indexName, documents); err != nil {
+		t.Fatalf("Failed to create test documents: %v", err)
+	}
+
+	agg := NewAggregationBuilder(TermsAggregation("category.keyword", 10)).
+		SubAggregation("avg_price", AvgAggregation("price")).
+		Build()
+	_, aggs, err := client.SearchWithAggregations(ctx, indexName, WithAggregation(WithSize(MatchAllQuery(), 0), "categories", agg))
+	if err != nil {
+		t.Fatalf("SearchWithAggregations() error = %v", err)
+	}
+
+	buckets, err := ParseTermsBuckets(aggs, "categories")
+	if err != nil {
+		t.Fatalf("ParseTermsBuckets() error = %v", err)
+	}
+	want := map[string]float64{"books": 15, "games": 60}
+	if len(buckets) != len(want) {
+		t.Fatalf("ParseTermsBuckets() = %+v, want a bucket per category", buckets)
+	}
+	for _, bucket := range buckets {
+		avg, err := ParseMetricValue(bucket.Aggregations, "avg_price")
+		if err != nil || avg != want[bucket.Key.(string)] {
+			t.Errorf("avg_price of %v = %v, %v, want %v", bucket.Key, avg, err, want[bucket.Key.(string)])
+		}
+	}
+}
+
+func TestAggregateOnly_RequestBody(t *testing.T) {
+	stub := &stubTransport{
+		status: 200,
+		body:   `{"hits":{"total":{"value":3,"relation":"eq"},"hits":[]},"aggregations":{"avg_price":{"value":30}}}`,
+	}
+	client := newStubClient(t, stub)
+
+	query := WithAggregation(WithSize(MatchAllQuery(), 50), "avg_price", AvgAggregation("price"))
+	aggs, err := client.AggregateOnly(context.Background(), "products", query)
+	if err != nil {
+		t.Fatalf("AggregateOnly() error = %v", err)
+	}
+
+	var sent map[string]interface{}
+	if err := json.Unmarshal(stub.sent, &sent); err != nil {
+		t.Fatalf("request body is not JSON: %v", err)
+	}
+	if sent["size"] != float64(0) {
+		t.Errorf("size = %v, want 0", sent["size"])
+	}
+	if query["size"] != 50 {
+		t.Errorf("query size = %v, want the caller's query left unchanged", query["size"])
+	}
+	if avg, err := ParseMetricValue(aggs, "avg_price"); err != nil || avg != 30 {
+		t.Errorf("avg_price = %v, %v, want 30", avg, err)
+	}
+}
+
+func TestAggregateOnly_NoAggregations(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{"hits":{"total":{"value":0,"relation":"eq"},"hits":[]}}`}
+	client := newStubClient(t, stub)
+
+	aggs, err := client.AggregateOnly(context.Background(), "products", MatchAllQuery())
+	if err != nil {
+		t.Fatalf("AggregateOnly() error = %v", err)
+	}
+	if aggs == nil || len(aggs) != 0 {
+		t.Errorf("AggregateOnly() = %v, want an empty map", aggs)
+	}
+}
+
+func TestAggregateOnly(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-aggregate-only"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	documents := []map[string]interface{}{
+		{"category": "books", "price": 10},
+		{"category": "books", "price": 20},
+		{"category": "games", "price": 60},
+	}
+	if err := client.BulkCreate(ctx, indexName, documents); err != nil {
+		t.Fatalf("Failed to create test documents: %v", err)
+	}
+
+	query := WithAggregation(MatchAllQuery(), "price_stats", StatsAggregation("price"))
+	aggs, err := client.AggregateOnly(ctx, indexName, query)
+	if err != nil {
+		t.Fatalf("AggregateOnly() error = %v", err)
+	}
+
+	stats, err := ParseStats(aggs, "price_stats")
+	if err != nil {
+		t.Fatalf("ParseStats() error = %v", err)
+	}
+	if stats.Count != 3 || stats.Sum != 90 {
+		t.Errorf("ParseStats() = %+v, want count 3 and sum 90", stats)
+	}
+}
@@ -0,0 +1,248 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// AliasesResponse represents the response from a GET _alias request, keyed by index name
+type AliasesResponse map[string]struct {
+	Aliases map[string]interface{} `json:"aliases"`
+}
+
+// AddAlias points an alias at an index
+func (c *Client) AddAlias(ctx context.Context, index, alias string) (err error) {
+	defer c.observe("AddAlias", time.Now(), &err)
+
+	return c.updateAliases(ctx, []map[string]interface{}{
+		{"add": map[string]interface{}{"index": index, "alias": alias}},
+	})
+}
+
+// RemoveAlias removes an alias from an index
+func (c *Client) RemoveAlias(ctx context.Context, index, alias string) (err error) {
+	defer c.observe("RemoveAlias", time.Now(), &err)
+
+	return c.updateAliases(ctx, []map[string]interface{}{
+		{"remove": map[string]interface{}{"index": index, "alias": alias}},
+	})
+}
+
+// SwapAlias atomically moves an alias from one index to another in a single _aliases
+// call, so readers never observe the alias pointing at no index. The remove action must
+// find the alias on fromIndex, so the call fails without changing anything if the alias
+// does not currently point at it.
+func (c *Client) SwapAlias(ctx context.Context, alias, fromIndex, toIndex string) (err error) {
+	defer c.observe("SwapAlias", time.Now(), &err)
+
+	return c.updateAliases(ctx, []map[string]interface{}{
+		{"remove": map[string]interface{}{"index": fromIndex, "alias": alias, "must_exist": true}},
+		{"add": map[string]interface{}{"index": toIndex, "alias": alias}},
+	})
+}
+
+// ForceSwapAlias atomically points an alias at toIndex only, removing it from
+// whichever indices it currently points at (if any)
+func (c *Client) ForceSwapAlias(ctx context.Context, alias, toIndex string) (err error) {
+	defer c.observe("ForceSwapAlias", time.Now(), &err)
+
+	indices, err := c.resolveAlias(ctx, alias)
+	if err != nil {
+		if !aliasNotFound(err) {
+			return fmt.Errorf("failed to resolve alias %s: %w", alias, err)
+		}
+		// A missing alias simply has nothing to remove
+		indices = nil
+	}
+
+	actions := make([]map[string]interface{}, 0, len(indices)+1)
+	for _, index := range indices {
+		if index == toIndex {
+			continue
+		}
+		actions = append(actions, map[string]interface{}{
+			"remove": map[string]interface{}{"index": index, "alias": alias},
+		})
+	}
+	actions = append(actions, map[string]interface{}{
+		"add": map[string]interface{}{"index": toIndex, "alias": alias},
+	})
+
+	return c.updateAliases(ctx, actions)
+}
+
+// GetAliases returns the names of all aliases pointing at an index
+func (c *Client) GetAliases(ctx context.Context, index string) (_ []string, err error) {
+	defer c.observe("GetAliases", time.Now(), &err)
+
+	req := opensearchapi.IndicesGetAliasRequest{
+		Index: []string{index},
+	}
+
+	response, err := c.getAlias(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make([]string, 0)
+	for _, entry := range response {
+		for alias := range entry.Aliases {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+
+	return aliases, nil
+}
+
+// ResolveAlias returns the names of all indices an alias points at
+func (c *Client) ResolveAlias(ctx context.Context, alias string) (_ []string, err error) {
+	defer c.observe("ResolveAlias", time.Now(), &err)
+
+	return c.resolveAlias(ctx, alias)
+}
+
+// resolveAlias returns the sorted names of the indices an alias points at
+func (c *Client) resolveAlias(ctx context.Context, alias string) ([]string, error) {
+	req := opensearchapi.IndicesGetAliasRequest{
+		Name: []string{alias},
+	}
+
+	response, err := c.getAlias(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]string, 0, len(response))
+	for index := range response {
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+
+	return indices, nil
+}
+
+// Rollover rolls the write alias over to a new index when any of the conditions is met,
+// e.g. {"max_docs": 1000000, "max_age": "7d"}, or unconditionally when there are none. It
+// returns whether a rollover happened and the name of the index the alias writes to afterwards.
+func (c *Client) Rollover(ctx context.Context, alias string, conditions map[string]interface{}) (_ bool, _ string, err error) {
+	defer c.observe("Rollover", time.Now(), &err)
+
+	var body []byte
+	if len(conditions) > 0 {
+		var err error
+		body, err = json.Marshal(map[string]interface{}{
+			"conditions": conditions,
+		})
+		if err != nil {
+			return false, "", fmt.Errorf("failed to marshal rollover conditions: %w", err)
+		}
+	}
+
+	req := opensearchapi.IndicesRolloverRequest{
+		Alias: alias,
+	}
+	if body != nil {
+		req.Body = bytes.NewReader(body)
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to roll over alias: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return false, "", responseError(res, "alias not found", nil)
+		}
+		return false, "", requestError("rollover", res)
+	}
+
+	var response struct {
+		OldIndex   string `json:"old_index"`
+		NewIndex   string `json:"new_index"`
+		RolledOver bool   `json:"rolled_over"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return false, "", err
+	}
+
+	if !response.RolledOver {
+		return false, response.OldIndex, nil
+	}
+	return true, response.NewIndex, nil
+}
+
+// getAlias executes a GET _alias request and parses the response
+func (c *Client) getAlias(ctx context.Context, req opensearchapi.IndicesGetAliasRequest) (AliasesResponse, error) {
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aliases: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			// A missing index is reported by type; a missing alias has a plain error string
+			osErr := parseOpenSearchError(res)
+			if osErr.cause == ErrIndexNotFound {
+				osErr.message = fmt.Sprintf("index %s not found", strings.Join(req.Index, ","))
+			} else {
+				osErr.message = "alias not found"
+			}
+			return nil, osErr
+		}
+		return nil, requestError("get alias", res)
+	}
+
+	var response AliasesResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// aliasNotFound reports whether resolving an alias failed because it does not exist
+func aliasNotFound(err error) bool {
+	var osErr *OpenSearchError
+	return errors.Is(err, ErrIndexNotFound) || (errors.As(err, &osErr) && osErr.StatusCode == 404)
+}
+
+// updateAliases applies a list of alias actions atomically via the _aliases endpoint
+func (c *Client) updateAliases(ctx context.Context, actions []map[string]interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"actions": actions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alias actions: %w", err)
+	}
+
+	req := opensearchapi.IndicesUpdateAliasesRequest{
+		Body: bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to update aliases: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, "alias or index not found", nil)
+		}
+		return requestError("update aliases", res)
+	}
+
+	return nil
+}
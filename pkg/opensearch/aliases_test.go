@@ -0,0 +1,301 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAliases(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-aliases"
+	aliasName := "test-aliases-read"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	t.Run("Add alias", func(t *testing.T) {
+		if err := client.AddAlias(ctx, indexName, aliasName); err != nil {
+			t.Fatalf("AddAlias() error = %v", err)
+		}
+	})
+
+	t.Run("Get aliases of index", func(t *testing.T) {
+		aliases, err := client.GetAliases(ctx, indexName)
+		if err != nil {
+			t.Fatalf("GetAliases() error = %v", err)
+		}
+		if !reflect.DeepEqual(aliases, []string{aliasName}) {
+			t.Errorf("GetAliases() = %v, want [%s]", aliases, aliasName)
+		}
+	})
+
+	t.Run("Resolve alias to index", func(t *testing.T) {
+		indices, err := client.ResolveAlias(ctx, aliasName)
+		if err != nil {
+			t.Fatalf("ResolveAlias() error = %v", err)
+		}
+		if !reflect.DeepEqual(indices, []string{indexName}) {
+			t.Errorf("ResolveAlias() = %v, want [%s]", indices, indexName)
+		}
+	})
+
+	t.Run("IndexExists returns true for an alias", func(t *testing.T) {
+		exists, err := client.IndexExists(ctx, aliasName)
+		if err != nil {
+			t.Fatalf("IndexExists() error = %v", err)
+		}
+		if !exists {
+			t.Error("IndexExists() = false for alias, want true")
+		}
+	})
+
+	t.Run("Write and search through alias", func(t *testing.T) {
+		err := client.CreateDocument(ctx, aliasName, "doc-1", map[string]interface{}{"title": "Via Alias"})
+		if err != nil {
+			t.Fatalf("CreateDocument() through alias error = %v", err)
+		}
+
+		results, err := client.SearchAll(ctx, aliasName)
+		if err != nil {
+			t.Fatalf("SearchAll() through alias error = %v", err)
+		}
+		if len(results) != 1 {
+			t.Errorf("Expected 1 document through alias, got %d", len(results))
+		}
+	})
+
+	t.Run("Remove alias", func(t *testing.T) {
+		if err := client.RemoveAlias(ctx, indexName, aliasName); err != nil {
+			t.Fatalf("RemoveAlias() error = %v", err)
+		}
+
+		if _, err := client.ResolveAlias(ctx, aliasName); err == nil {
+			t.Error("ResolveAlias() expected error for removed alias but got nil")
+		}
+	})
+}
+
+func TestSwapAlias(t *testing.T) {
+	client := setupTestClient(t)
+	oldIndex := "test-swap-alias-v1"
+	newIndex := "test-swap-alias-v2"
+	aliasName := "test-swap-alias"
+	cleanupOld := setupTestIndex(t, client, oldIndex)
+	defer cleanupOld()
+	cleanupNew := setupTestIndex(t, client, newIndex)
+	defer cleanupNew()
+
+	ctx := context.Background()
+
+	if err := client.CreateDocument(ctx, oldIndex, "doc-1", map[string]interface{}{"generation": "old"}); err != nil {
+		t.Fatalf("Failed to seed old index: %v", err)
+	}
+	if err := client.CreateDocument(ctx, newIndex, "doc-1", map[string]interface{}{"generation": "new"}); err != nil {
+		t.Fatalf("Failed to seed new index: %v", err)
+	}
+	if err := client.AddAlias(ctx, oldIndex, aliasName); err != nil {
+		t.Fatalf("AddAlias() error = %v", err)
+	}
+
+	t.Run("Swap fails when alias is not on source index", func(t *testing.T) {
+		err := client.SwapAlias(ctx, aliasName, newIndex, oldIndex)
+		if err == nil {
+			t.Fatal("SwapAlias() expected error but got nil")
+		}
+
+		indices, err := client.ResolveAlias(ctx, aliasName)
+		if err != nil {
+			t.Fatalf("ResolveAlias() error = %v", err)
+		}
+		if !reflect.DeepEqual(indices, []string{oldIndex}) {
+			t.Errorf("Alias should be unchanged after failed swap, got %v", indices)
+		}
+	})
+
+	t.Run("Searches flip from old to new without gaps", func(t *testing.T) {
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		var failures []string
+
+		// Continuously search through the alias while the swap happens; failures is only
+		// read once done is closed
+		go func() {
+			defer close(done)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				results, err := client.SearchAll(ctx, aliasName)
+				if err != nil {
+					failures = append(failures, err.Error())
+					continue
+				}
+				if len(results) != 1 {
+					failures = append(failures, "search through alias returned no single document")
+				}
+			}
+		}()
+
+		err := client.SwapAlias(ctx, aliasName, oldIndex, newIndex)
+		close(stop)
+		<-done
+		if err != nil {
+			t.Fatalf("SwapAlias() error = %v", err)
+		}
+		for _, failure := range failures {
+			t.Errorf("Intermediate failure during swap: %s", failure)
+		}
+
+		results, err := client.SearchAll(ctx, aliasName)
+		if err != nil {
+			t.Fatalf("SearchAll() after swap error = %v", err)
+		}
+		if len(results) != 1 || results[0]["generation"] != "new" {
+			t.Errorf("Expected new generation after swap, got %v", results)
+		}
+	})
+
+	t.Run("Force swap points alias at target only", func(t *testing.T) {
+		if err := client.ForceSwapAlias(ctx, aliasName, oldIndex); err != nil {
+			t.Fatalf("ForceSwapAlias() error = %v", err)
+		}
+
+		indices, err := client.ResolveAlias(ctx, aliasName)
+		if err != nil {
+			t.Fatalf("ResolveAlias() error = %v", err)
+		}
+		if !reflect.DeepEqual(indices, []string{oldIndex}) {
+			t.Errorf("ResolveAlias() after force swap = %v, want [%s]", indices, oldIndex)
+		}
+	})
+}
+
+func TestRollover(t *testing.T) {
+	client := setupTestClient(t)
+	alias := "test-rollover"
+	firstIndex := "test-rollover-000001"
+	secondIndex := "test-rollover-000002"
+	ctx := context.Background()
+
+	_ = client.DeleteIndex(ctx, firstIndex)
+	_ = client.DeleteIndex(ctx, secondIndex)
+	defer func() {
+		_ = client.DeleteIndex(ctx, firstIndex)
+		_ = client.DeleteIndex(ctx, secondIndex)
+	}()
+
+	err := client.CreateIndex(ctx, firstIndex, map[string]interface{}{
+		"aliases": map[string]interface{}{
+			alias: map[string]interface{}{"is_write_index": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create write index: %v", err)
+	}
+
+	conditions := map[string]interface{}{"max_docs": 3}
+
+	rolled, index, err := client.Rollover(ctx, alias, conditions)
+	if err != nil {
+		t.Fatalf("Rollover() error = %v", err)
+	}
+	if rolled || index != firstIndex {
+		t.Errorf("Rollover() before max_docs = %t, %s, want false, %s", rolled, index, firstIndex)
+	}
+
+	seedDocuments(t, client, alias, 3)
+
+	rolled, index, err = client.Rollover(ctx, alias, conditions)
+	if err != nil {
+		t.Fatalf("Rollover() error = %v", err)
+	}
+	if !rolled || index != secondIndex {
+		t.Errorf("Rollover() after max_docs = %t, %s, want true, %s", rolled, index, secondIndex)
+	}
+}
+
+func TestSwapAlias_RemoveMustExist(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{"acknowledged":true}`}
+	client := newStubClient(t, stub)
+
+	if err := client.SwapAlias(context.Background(), "books", "books-v1", "books-v2"); err != nil {
+		t.Fatalf("SwapAlias() error = %v", err)
+	}
+	if stub.calls != 1 {
+		t.Errorf("SwapAlias() sent %d requests, want the _aliases update only", stub.calls)
+	}
+	want := `{"actions":[{"remove":{"alias":"books","index":"books-v1","must_exist":true}},{"add":{"alias":"books","index":"books-v2"}}]}`
+	if got := string(stub.sent); got != want {
+		t.Errorf("SwapAlias() sent %s, want %s", got, want)
+	}
+}
+
+func TestForceSwapAlias_ResolveError(t *testing.T) {
+	stub := &stubTransport{status: 500, body: `{"error":{"type":"exception","reason":"boom"},"status":500}`}
+	client := newStubClient(t, stub)
+
+	err := client.ForceSwapAlias(context.Background(), "books", "books-v2")
+	if err == nil || !strings.Contains(err.Error(), "failed to resolve alias books") {
+		t.Fatalf("ForceSwapAlias() error = %v, want the resolve error", err)
+	}
+	if stub.calls != 1 {
+		t.Errorf("ForceSwapAlias() sent %d requests, want no alias update after the failed resolve", stub.calls)
+	}
+}
+
+func TestForceSwapAlias_MissingAlias(t *testing.T) {
+	stub := &stubTransport{
+		statuses: []int{404},
+		status:   200,
+		body:     `{"error":"alias [books] missing","status":404}`,
+	}
+	client := newStubClient(t, stub)
+
+	if err := client.ForceSwapAlias(context.Background(), "books", "books-v2"); err != nil {
+		t.Fatalf("ForceSwapAlias() error = %v", err)
+	}
+	if stub.path != "/_aliases" || !strings.Contains(string(stub.sent), `"add":{"alias":"books","index":"books-v2"}`) {
+		t.Errorf("ForceSwapAlias() sent %s to %s, want only the add action", stub.sent, stub.path)
+	}
+}
+
+func TestGetAliases_NotFound(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantIndex bool
+		wantErr   string
+	}{
+		{
+			name:      "missing index",
+			body:      `{"error":{"type":"index_not_found_exception","reason":"no such index [books]","index":"books"},"status":404}`,
+			wantIndex: true,
+			wantErr:   "index books not found",
+		},
+		{
+			name:    "missing alias",
+			body:    `{"error":"alias [books-read] missing","status":404}`,
+			wantErr: "alias not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStubClient(t, &stubTransport{status: 404, body: tt.body})
+
+			_, err := client.GetAliases(context.Background(), "books")
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("GetAliases() error = %v, want %q", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrIndexNotFound) != tt.wantIndex {
+				t.Errorf("errors.Is(%v, ErrIndexNotFound) = %v, want %v", err, !tt.wantIndex, tt.wantIndex)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/opensearch-project/opensearch-go/v2/signer"
+	"github.com/opensearch-project/opensearch-go/v2/signer/awsv2"
+)
+
+// defaultAWSService is the signing name of Amazon OpenSearch Service domains
+const defaultAWSService = "es"
+
+// AWSConfig configures AWS Signature Version 4 request signing for Amazon OpenSearch Service
+type AWSConfig struct {
+	// Region of the domain or collection, e.g. "eu-west-1"
+	Region string
+	// Service is "es" for managed domains (the default) or "aoss" for OpenSearch Serverless
+	Service string
+	// Credentials is asked for credentials on every request, so rotating credentials are
+	// picked up without re-creating the client. When nil, the AWS SDK default credentials
+	// chain is used: environment variables, shared config and credentials files, and the
+	// container or instance role.
+	Credentials aws.CredentialsProvider
+}
+
+// awsSigningTransport signs each request attempt with AWS Signature Version 4
+type awsSigningTransport struct {
+	next   http.RoundTripper
+	signer signer.Signer
+}
+
+// newAWSSigningTransport wraps next so that requests are signed as configured by config
+func newAWSSigningTransport(next http.RoundTripper, config AWSConfig) (*awsSigningTransport, error) {
+	if config.Region == "" {
+		return nil, fmt.Errorf("AWS region is required for request signing")
+	}
+
+	credentials := config.Credentials
+	if credentials == nil {
+		defaults, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(config.Region))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS credentials: %w", err)
+		}
+		credentials = defaults.Credentials
+	}
+
+	service := config.Service
+	if service == "" {
+		service = defaultAWSService
+	}
+
+	s, err := awsv2.NewSignerWithService(aws.Config{Region: config.Region, Credentials: credentials}, service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS request signer: %w", err)
+	}
+
+	return &awsSigningTransport{next: next, signer: s}, nil
+}
+
+// RoundTrip signs a copy of the request and sends it
+func (t *awsSigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed := req.Clone(req.Context())
+	if err := t.signer.SignRequest(signed); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+	return t.next.RoundTrip(signed)
+}
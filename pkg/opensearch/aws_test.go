@@ -0,0 +1,139 @@
+package opensearch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+func TestAWSSigning(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{"took":1,"hits":{"total":{"value":0},"hits":[]}}`}
+	client, err := newClient(Config{
+		Addresses:    []string{"https://search-test.eu-west-1.es.amazonaws.com"},
+		DisableRetry: true,
+		AWS: &AWSConfig{
+			Region:      "eu-west-1",
+			Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		},
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	if _, err := client.SearchDocuments(context.Background(), "my-index", MatchAllQuery()); err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+
+	date := time.Now().UTC().Format("20060102")
+	wantPrefix := fmt.Sprintf("AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/%s/eu-west-1/es/aws4_request, SignedHeaders=", date)
+	auth := stub.header.Get("Authorization")
+	if !strings.HasPrefix(auth, wantPrefix) || !strings.Contains(auth, "x-amz-content-sha256") {
+		t.Errorf("Authorization = %q, want prefix %q with a signed payload hash", auth, wantPrefix)
+	}
+	sum := sha256.Sum256(stub.sent)
+	if got, want := stub.header.Get("X-Amz-Content-Sha256"), hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("X-Amz-Content-Sha256 = %q, want hash of the sent body %q", got, want)
+	}
+}
+
+func TestAWSSigning_RefreshedCredentials(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{}`}
+	calls := 0
+	provider := aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		calls++
+		return aws.Credentials{
+			AccessKeyID:     fmt.Sprintf("ASIA%d", calls),
+			SecretAccessKey: "secret",
+			SessionToken:    fmt.Sprintf("token-%d", calls),
+		}, nil
+	})
+
+	client, err := newClient(Config{
+		Addresses:    []string{"https://collection.eu-west-1.aoss.amazonaws.com"},
+		DisableRetry: true,
+		AWS:          &AWSConfig{Region: "eu-west-1", Service: "aoss", Credentials: provider},
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		if err := client.Ping(context.Background()); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+
+		auth := stub.header.Get("Authorization")
+		if want := fmt.Sprintf("Credential=ASIA%d/", i); !strings.Contains(auth, want) {
+			t.Errorf("Request %d: Authorization = %q, want credentials %s", i, auth, want)
+		}
+		if !strings.Contains(auth, "/eu-west-1/aoss/aws4_request") || !strings.Contains(auth, "x-amz-security-token") {
+			t.Errorf("Request %d: Authorization = %q, want aoss scope with a signed session token", i, auth)
+		}
+		if got, want := stub.header.Get("X-Amz-Security-Token"), fmt.Sprintf("token-%d", i); got != want {
+			t.Errorf("Request %d: X-Amz-Security-Token = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestAWSSigning_DefaultCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDFROMENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	stub := &stubTransport{status: 200, body: `{}`}
+	client, err := newClient(Config{
+		Addresses:    []string{"https://search-test.eu-west-1.es.amazonaws.com"},
+		DisableRetry: true,
+		AWS:          &AWSConfig{Region: "eu-west-1"},
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if auth := stub.header.Get("Authorization"); !strings.Contains(auth, "Credential=AKIDFROMENV/") {
+		t.Errorf("Authorization = %q, want the credentials from the environment", auth)
+	}
+}
+
+func TestAWSConfig_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name:   "Missing region",
+			config: Config{AWS: &AWSConfig{Credentials: credentials.NewStaticCredentialsProvider("id", "secret", "")}},
+		},
+		{
+			name: "Combined with basic auth",
+			config: Config{
+				Username: "admin",
+				Password: "admin",
+				AWS:      &AWSConfig{Region: "eu-west-1", Credentials: credentials.NewStaticCredentialsProvider("id", "secret", "")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Addresses = []string{"https://search-test.eu-west-1.es.amazonaws.com"}
+			if _, err := NewClient(tt.config); err == nil {
+				t.Error("NewClient() error = nil, want a configuration error")
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package opensearch
+
+// BoolBuilder assembles a bool query clause by clause. Builders can be nested by
+// passing the result of one builder's Build as a clause of another.
+type BoolBuilder struct {
+	must               []map[string]interface{}
+	should             []map[string]interface{}
+	mustNot            []map[string]interface{}
+	filter             []map[string]interface{}
+	minimumShouldMatch interface{}
+}
+
+// NewBoolBuilder creates an empty bool query builder
+func NewBoolBuilder() *BoolBuilder {
+	return &BoolBuilder{}
+}
+
+// Must adds clauses that documents must match
+func (b *BoolBuilder) Must(clauses ...map[string]interface{}) *BoolBuilder {
+	b.must = appendClauses(b.must, clauses)
+	return b
+}
+
+// Should adds clauses that documents should match
+func (b *BoolBuilder) Should(clauses ...map[string]interface{}) *BoolBuilder {
+	b.should = appendClauses(b.should, clauses)
+	return b
+}
+
+// MustNot adds clauses that documents must not match
+func (b *BoolBuilder) MustNot(clauses ...map[string]interface{}) *BoolBuilder {
+	b.mustNot = appendClauses(b.mustNot, clauses)
+	return b
+}
+
+// Filter adds clauses that documents must match without contributing to the score
+func (b *BoolBuilder) Filter(clauses ...map[string]interface{}) *BoolBuilder {
+	b.filter = appendClauses(b.filter, clauses)
+	return b
+}
+
+// MinimumShouldMatch sets how many should clauses must match (e.g. 1 or "75%")
+func (b *BoolBuilder) MinimumShouldMatch(value interface{}) *BoolBuilder {
+	b.minimumShouldMatch = value
+	return b
+}
+
+// Build returns the bool clause, suitable for nesting inside another builder
+func (b *BoolBuilder) Build() map[string]interface{} {
+	boolQuery := make(map[string]interface{})
+
+	if len(b.must) > 0 {
+		boolQuery["must"] = b.must
+	}
+	if len(b.should) > 0 {
+		boolQuery["should"] = b.should
+	}
+	if len(b.mustNot) > 0 {
+		boolQuery["must_not"] = b.mustNot
+	}
+	if len(b.filter) > 0 {
+		boolQuery["filter"] = b.filter
+	}
+	if b.minimumShouldMatch != nil {
+		boolQuery["minimum_should_match"] = b.minimumShouldMatch
+	}
+
+	return map[string]interface{}{
+		"bool": boolQuery,
+	}
+}
+
+// Query returns the bool clause wrapped in a complete search body
+func (b *BoolBuilder) Query() map[string]interface{} {
+	return map[string]interface{}{
+		"query": b.Build(),
+	}
+}
+
+// appendClauses appends clauses, unwrapping complete search bodies produced by
+// the top-level query builders (e.g. MatchQuery) into their inner clause
+func appendClauses(dst, clauses []map[string]interface{}) []map[string]interface{} {
+	for _, clause := range clauses {
+		dst = append(dst, unwrapQuery(clause))
+	}
+	return dst
+}
+
+// unwrapQuery returns the inner clause of a {"query": ...} body, or the input unchanged
+func unwrapQuery(query map[string]interface{}) map[string]interface{} {
+	if len(query) != 1 {
+		return query
+	}
+	if inner, ok := query["query"].(map[string]interface{}); ok {
+		return inner
+	}
+	return query
+}
+
+// QueryBuilder assembles a complete search body: a bool query built clause by clause, plus
+// size, from and sort. Clauses can come from the clause constructors (e.g. MatchClause),
+// from the top-level builders (e.g. MatchQuery), whose search body is unwrapped, or from a
+// BoolBuilder for nested bool queries:
+//
+//	query := NewQueryBuilder().
+//		Must(MatchClause("title", "go")).
+//		Filter(RangeClause("views", 100, nil)).
+//		MustNot(TermClause("hidden", true)).
+//		Size(20).
+//		Sort("views", "desc").
+//		Build()
+type QueryBuilder struct {
+	clauses *BoolBuilder
+	size    *int
+	from    *int
+	sort    []map[string]interface{}
+}
+
+// NewQueryBuilder creates an empty query builder, which matches all documents
+func NewQueryBuilder() *QueryBuilder {
+	return &QueryBuilder{clauses: NewBoolBuilder()}
+}
+
+// Must adds clauses that documents must match
+func (q *QueryBuilder) Must(clauses ...map[string]interface{}) *QueryBuilder {
+	q.clauses.Must(clauses...)
+	return q
+}
+
+// Should adds clauses that documents should match
+func (q *QueryBuilder) Should(clauses ...map[string]interface{}) *QueryBuilder {
+	q.clauses.Should(clauses...)
+	return q
+}
+
+// MustNot adds clauses that documents must not match
+func (q *QueryBuilder) MustNot(clauses ...map[string]interface{}) *QueryBuilder {
+	q.clauses.MustNot(clauses...)
+	return q
+}
+
+// Filter adds clauses that documents must match without contributing to the score
+func (q *QueryBuilder) Filter(clauses ...map[string]interface{}) *QueryBuilder {
+	q.clauses.Filter(clauses...)
+	return q
+}
+
+// MinimumShouldMatch sets how many should clauses must match (e.g. 1 or "75%")
+func (q *QueryBuilder) MinimumShouldMatch(value interface{}) *QueryBuilder {
+	q.clauses.MinimumShouldMatch(value)
+	return q
+}
+
+// Size sets the maximum number of hits to return
+func (q *QueryBuilder) Size(size int) *QueryBuilder {
+	q.size = &size
+	return q
+}
+
+// From sets the offset of the first hit to return, for pagination
+func (q *QueryBuilder) From(from int) *QueryBuilder {
+	q.from = &from
+	return q
+}
+
+// Sort adds a sort on field in order ("asc" or "desc"). Each call adds a further sort,
+// used to break ties of the previous ones.
+func (q *QueryBuilder) Sort(field, order string) *QueryBuilder {
+	q.sort = append(q.sort, map[string]interface{}{
+		field: map[string]interface{}{
+			"order": order,
+		},
+	})
+	return q
+}
+
+// Build returns the complete search body. Without any clauses the query is match_all.
+func (q *QueryBuilder) Build() map[string]interface{} {
+	query := q.clauses.Build()
+	if len(query["bool"].(map[string]interface{})) == 0 {
+		query = map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+
+	body := map[string]interface{}{
+		"query": query,
+	}
+	if q.size != nil {
+		body["size"] = *q.size
+	}
+	if q.from != nil {
+		body["from"] = *q.from
+	}
+	if len(q.sort) > 0 {
+		body["sort"] = q.sort
+	}
+	return body
+}
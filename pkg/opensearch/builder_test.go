@@ -0,0 +1,246 @@
+package opensearch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestBoolBuilder tests single-level bool queries built with BoolBuilder
+func TestBoolBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *BoolBuilder
+		want    map[string]interface{}
+	}{
+		{
+			name:    "empty builder",
+			builder: NewBoolBuilder(),
+			want: map[string]interface{}{
+				"bool": map[string]interface{}{},
+			},
+		},
+		{
+			name: "all clause types",
+			builder: NewBoolBuilder().
+				Must(map[string]interface{}{"match": map[string]interface{}{"title": "go"}}).
+				Should(map[string]interface{}{"term": map[string]interface{}{"tag": "a"}}).
+				MustNot(map[string]interface{}{"term": map[string]interface{}{"hidden": true}}).
+				Filter(map[string]interface{}{"range": map[string]interface{}{"views": map[string]interface{}{"gte": 100}}}).
+				MinimumShouldMatch(1),
+			want: map[string]interface{}{
+				"bool": map[string]interface{}{
+					"must": []map[string]interface{}{
+						{"match": map[string]interface{}{"title": "go"}},
+					},
+					"should": []map[string]interface{}{
+						{"term": map[string]interface{}{"tag": "a"}},
+					},
+					"must_not": []map[string]interface{}{
+						{"term": map[string]interface{}{"hidden": true}},
+					},
+					"filter": []map[string]interface{}{
+						{"range": map[string]interface{}{"views": map[string]interface{}{"gte": 100}}},
+					},
+					"minimum_should_match": 1,
+				},
+			},
+		},
+		{
+			name:    "top-level builders are unwrapped",
+			builder: NewBoolBuilder().Must(MatchQuery("title", "go"), TermQuery("status", "active")),
+			want: map[string]interface{}{
+				"bool": map[string]interface{}{
+					"must": []map[string]interface{}{
+						{"match": map[string]interface{}{"title": "go"}},
+						{"term": map[string]interface{}{"status": "active"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Build()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() = %v, want %v", prettyPrint(got), prettyPrint(tt.want))
+			}
+		})
+	}
+}
+
+// TestBoolBuilder_Nested tests a two-level nested bool query
+func TestBoolBuilder_Nested(t *testing.T) {
+	authors := NewBoolBuilder().
+		Should(MatchQuery("author", "alice"), MatchQuery("author", "bob")).
+		MinimumShouldMatch(1)
+
+	query := NewBoolBuilder().
+		Must(MatchQuery("category", "tutorial")).
+		Should(authors.Build()).
+		Query()
+
+	want := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{"match": map[string]interface{}{"category": "tutorial"}},
+				},
+				"should": []map[string]interface{}{
+					{
+						"bool": map[string]interface{}{
+							"should": []map[string]interface{}{
+								{"match": map[string]interface{}{"author": "alice"}},
+								{"match": map[string]interface{}{"author": "bob"}},
+							},
+							"minimum_should_match": 1,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("nested Query() = %v, want %v", prettyPrint(query), prettyPrint(want))
+	}
+
+	if _, err := json.Marshal(query); err != nil {
+		t.Errorf("Failed to marshal nested query: %v", err)
+	}
+}
+
+// TestQueryBuilder tests the search bodies assembled by QueryBuilder
+func TestQueryBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *QueryBuilder
+		want    map[string]interface{}
+	}{
+		{
+			name:    "empty builder matches all",
+			builder: NewQueryBuilder(),
+			want: map[string]interface{}{
+				"query": map[string]interface{}{"match_all": map[string]interface{}{}},
+			},
+		},
+		{
+			name: "must, filter, must_not, size and sort",
+			builder: NewQueryBuilder().
+				Must(MatchClause("title", "go")).
+				Filter(RangeClause("views", 100, nil)).
+				MustNot(TermClause("hidden", true)).
+				Size(20).
+				Sort("views", "desc"),
+			want: map[string]interface{}{
+				"query": map[string]interface{}{
+					"bool": map[string]interface{}{
+						"must": []map[string]interface{}{
+							{"match": map[string]interface{}{"title": "go"}},
+						},
+						"filter": []map[string]interface{}{
+							{"range": map[string]interface{}{"views": map[string]interface{}{"gte": 100}}},
+						},
+						"must_not": []map[string]interface{}{
+							{"term": map[string]interface{}{"hidden": true}},
+						},
+					},
+				},
+				"size": 20,
+				"sort": []map[string]interface{}{
+					{"views": map[string]interface{}{"order": "desc"}},
+				},
+			},
+		},
+		{
+			name: "should with minimum, from and tie-breaking sorts",
+			builder: NewQueryBuilder().
+				Should(TermClause("tag", "a"), TermClause("tag", "b")).
+				MinimumShouldMatch(1).
+				From(40).
+				Size(20).
+				Sort("views", "desc").
+				Sort("_id", "asc"),
+			want: map[string]interface{}{
+				"query": map[string]interface{}{
+					"bool": map[string]interface{}{
+						"should": []map[string]interface{}{
+							{"term": map[string]interface{}{"tag": "a"}},
+							{"term": map[string]interface{}{"tag": "b"}},
+						},
+						"minimum_should_match": 1,
+					},
+				},
+				"from": 40,
+				"size": 20,
+				"sort": []map[string]interface{}{
+					{"views": map[string]interface{}{"order": "desc"}},
+					{"_id": map[string]interface{}{"order": "asc"}},
+				},
+			},
+		},
+		{
+			name: "top-level builders and nested bool builders",
+			builder: NewQueryBuilder().
+				Must(MatchQuery("category", "tutorial")).
+				Filter(NewBoolBuilder().Should(TermClause("author", "alice"), TermClause("author", "bob")).Build()),
+			want: map[string]interface{}{
+				"query": map[string]interface{}{
+					"bool": map[string]interface{}{
+						"must": []map[string]interface{}{
+							{"match": map[string]interface{}{"category": "tutorial"}},
+						},
+						"filter": []map[string]interface{}{
+							{"bool": map[string]interface{}{
+								"should": []map[string]interface{}{
+									{"term": map[string]interface{}{"author": "alice"}},
+									{"term": map[string]interface{}{"author": "bob"}},
+								},
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "size zero is kept",
+			builder: NewQueryBuilder().Size(0),
+			want: map[string]interface{}{
+				"query": map[string]interface{}{"match_all": map[string]interface{}{}},
+				"size":  0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.builder.Build()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() = %v, want %v", prettyPrint(got), prettyPrint(tt.want))
+			}
+		})
+	}
+}
+
+// TestClauseConstructors tests that the top-level builders wrap the clause constructors
+func TestClauseConstructors(t *testing.T) {
+	tests := []struct {
+		name   string
+		clause map[string]interface{}
+		query  map[string]interface{}
+	}{
+		{"Match", MatchClause("title", "go"), MatchQuery("title", "go")},
+		{"Term", TermClause("status", "active"), TermQuery("status", "active")},
+		{"Range", RangeClause("views", 10, 20), RangeQuery("views", 10, 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := map[string]interface{}{"query": tt.clause}
+			if !reflect.DeepEqual(tt.query, want) {
+				t.Errorf("%sQuery() = %v, want the clause %v wrapped in query", tt.name, tt.query, tt.clause)
+			}
+		})
+	}
+}
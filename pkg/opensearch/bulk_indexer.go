@@ -0,0 +1,334 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+const (
+	// defaultBulkFlushDocs, defaultBulkFlushBytes and defaultBulkFlushInterval bound a BulkIndexer batch
+	defaultBulkFlushDocs     = 1000
+	defaultBulkFlushBytes    = 5 << 20
+	defaultBulkFlushInterval = 5 * time.Second
+	// defaultBulkWorkers is the number of batches a BulkIndexer sends concurrently
+	defaultBulkWorkers = 2
+)
+
+// BulkIndexerOptions configures a BulkIndexer. Zero values use the defaults.
+type BulkIndexerOptions struct {
+	// FlushDocs sends a batch once it holds this many documents (default 1000)
+	FlushDocs int
+	// FlushBytes sends a batch once its request body reaches this many bytes (default 5MB)
+	FlushBytes int
+	// FlushInterval sends a non-empty batch at least this often (default 5s)
+	FlushInterval time.Duration
+	// Workers is how many batches are sent concurrently (default 2)
+	Workers int
+	// Refresh is the refresh parameter of each bulk request, e.g. "wait_for" (default none)
+	Refresh string
+	// OnError, when set, is called from a worker with the error of each failed batch
+	OnError func(err error)
+}
+
+// BulkIndexerStats counts the documents handled by a BulkIndexer
+type BulkIndexerStats struct {
+	Added    int64
+	Indexed  int64
+	Failed   int64
+	Requests int64
+}
+
+// BulkIndexer indexes documents in background batches. Documents are added with Add and
+// sent in bulk requests once a batch is full or FlushInterval has passed; Close sends the
+// last batch and waits for all requests to finish. It is safe for concurrent use.
+type BulkIndexer struct {
+	client *Client
+	index  string
+	opts   BulkIndexerOptions
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	docs   int
+	closed bool
+
+	batches   chan bulkBatch
+	done      chan struct{}
+	producers sync.WaitGroup
+	workers   sync.WaitGroup
+	// ctx is used for the bulk requests and is cancelled when Close gives up waiting
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	added    atomic.Int64
+	indexed  atomic.Int64
+	failed   atomic.Int64
+	requests atomic.Int64
+
+	errMu sync.Mutex
+	err   error
+}
+
+// bulkBatch is a bulk request body and the number of documents in it
+type bulkBatch struct {
+	body []byte
+	docs int
+}
+
+// NewBulkIndexer creates a BulkIndexer for index and starts its workers
+func NewBulkIndexer(client *Client, index string, opts BulkIndexerOptions) *BulkIndexer {
+	if opts.FlushDocs <= 0 {
+		opts.FlushDocs = defaultBulkFlushDocs
+	}
+	if opts.FlushBytes <= 0 {
+		opts.FlushBytes = defaultBulkFlushBytes
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultBulkFlushInterval
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = defaultBulkWorkers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &BulkIndexer{
+		client:  client,
+		index:   index,
+		opts:    opts,
+		batches: make(chan bulkBatch, opts.Workers),
+		done:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	b.workers.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go b.worker()
+	}
+	b.producers.Add(1)
+	go b.flushLoop()
+
+	return b
+}
+
+// Add queues a document for indexing. A "_id" key is used as the document ID, as in BulkCreate.
+// When the document completes a batch, Add waits until a worker can take the batch or ctx is done.
+func (b *BulkIndexer) Add(ctx context.Context, doc map[string]interface{}) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return fmt.Errorf("bulk indexer is closed")
+	}
+	if err := writeBulkIndexAction(&b.buf, b.index, doc); err != nil {
+		b.mu.Unlock()
+		return err
+	}
+	b.docs++
+	b.added.Add(1)
+
+	if b.docs < b.opts.FlushDocs && b.buf.Len() < b.opts.FlushBytes {
+		b.mu.Unlock()
+		return nil
+	}
+	batch := b.takeBatch()
+	b.producers.Add(1)
+	b.mu.Unlock()
+
+	defer b.producers.Done()
+	return b.send(ctx, batch)
+}
+
+// Close sends the remaining documents and waits for all bulk requests to finish. If ctx is
+// done first, requests still in flight are cancelled. It reports the documents that failed.
+func (b *BulkIndexer) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return fmt.Errorf("bulk indexer is already closed")
+	}
+	b.closed = true
+	close(b.done)
+	var sendErr error
+	if b.docs > 0 {
+		batch := b.takeBatch()
+		b.mu.Unlock()
+		sendErr = b.send(ctx, batch)
+	} else {
+		b.mu.Unlock()
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		b.producers.Wait()
+		close(b.batches)
+		b.workers.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		b.cancel()
+	case <-ctx.Done():
+		b.cancel()
+		<-finished
+		return fmt.Errorf("failed to close bulk indexer: %w", ctx.Err())
+	}
+
+	if sendErr != nil {
+		return sendErr
+	}
+	if err := b.firstError(); err != nil {
+		return fmt.Errorf("%d of %d documents failed to index: %w", b.failed.Load(), b.added.Load(), err)
+	}
+	return nil
+}
+
+// Stats returns the document and request counts so far
+func (b *BulkIndexer) Stats() BulkIndexerStats {
+	return BulkIndexerStats{
+		Added:    b.added.Load(),
+		Indexed:  b.indexed.Load(),
+		Failed:   b.failed.Load(),
+		Requests: b.requests.Load(),
+	}
+}
+
+// takeBatch returns the buffered documents as a batch and resets the buffer. b.mu must be held.
+func (b *BulkIndexer) takeBatch() bulkBatch {
+	batch := bulkBatch{
+		body: append([]byte(nil), b.buf.Bytes()...),
+		docs: b.docs,
+	}
+	b.buf.Reset()
+	b.docs = 0
+	return batch
+}
+
+// send hands a batch to the workers, giving up when ctx is done or the indexer is cancelled
+func (b *BulkIndexer) send(ctx context.Context, batch bulkBatch) error {
+	select {
+	case b.batches <- batch:
+		return nil
+	case <-ctx.Done():
+		err := fmt.Errorf("failed to queue bulk batch: %w", ctx.Err())
+		b.recordFailure(batch.docs, err)
+		return err
+	case <-b.ctx.Done():
+		err := fmt.Errorf("failed to queue bulk batch: %w", b.ctx.Err())
+		b.recordFailure(batch.docs, err)
+		return err
+	}
+}
+
+// flushLoop sends the buffered documents every FlushInterval until Close is called
+func (b *BulkIndexer) flushLoop() {
+	defer b.producers.Done()
+
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			if b.closed || b.docs == 0 {
+				b.mu.Unlock()
+				continue
+			}
+			batch := b.takeBatch()
+			b.mu.Unlock()
+
+			_ = b.send(b.ctx, batch)
+		}
+	}
+}
+
+// worker sends batches until the batch channel is closed
+func (b *BulkIndexer) worker() {
+	defer b.workers.Done()
+
+	for batch := range b.batches {
+		b.flush(batch)
+	}
+}
+
+// flush sends one batch as a bulk request and records the outcome of its documents
+func (b *BulkIndexer) flush(batch bulkBatch) {
+	if err := b.ctx.Err(); err != nil {
+		b.recordFailure(batch.docs, fmt.Errorf("failed to perform bulk operation: %w", err))
+		return
+	}
+
+	b.requests.Add(1)
+	req := opensearchapi.BulkRequest{
+		Index:   b.index,
+		Body:    bytes.NewReader(batch.body),
+		Refresh: b.opts.Refresh,
+	}
+
+	res, err := req.Do(b.ctx, b.client.client)
+	if err != nil {
+		b.recordFailure(batch.docs, fmt.Errorf("failed to perform bulk operation: %w", err))
+		return
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		b.recordFailure(batch.docs, requestError("bulk", res))
+		return
+	}
+
+	var response BulkResponse
+	if err := b.client.parseResponse(res.Body, &response); err != nil {
+		b.recordFailure(batch.docs, err)
+		return
+	}
+
+	failed := 0
+	var firstErr error
+	for _, item := range response.Items {
+		for _, op := range item {
+			if op.Error.Type == "" {
+				continue
+			}
+			failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("document %s: %s: %s", op.ID, op.Error.Type, op.Error.Reason)
+			}
+		}
+	}
+
+	b.indexed.Add(int64(batch.docs - failed))
+	if failed > 0 {
+		b.recordFailure(failed, fmt.Errorf("bulk operation had %d failed documents, first %w", failed, firstErr))
+	}
+}
+
+// recordFailure counts docs failed documents and keeps the first error
+func (b *BulkIndexer) recordFailure(docs int, err error) {
+	b.failed.Add(int64(docs))
+
+	b.errMu.Lock()
+	if b.err == nil {
+		b.err = err
+	}
+	b.errMu.Unlock()
+
+	if b.opts.OnError != nil {
+		b.opts.OnError(err)
+	}
+}
+
+// firstError returns the first error recorded by the indexer
+func (b *BulkIndexer) firstError() error {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	return b.err
+}
@@ -0,0 +1,233 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bulkStub answers bulk requests, counting the requests and documents it receives.
+// Documents whose ID is in reject are reported as failed items.
+type bulkStub struct {
+	reject map[string]bool
+
+	mu       sync.Mutex
+	requests int
+	docs     int
+}
+
+func (s *bulkStub) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+
+	var items []string
+	hasErrors := false
+	for i := 0; i < len(lines); i += 2 {
+		id := ""
+		if start := bytes.Index(lines[i], []byte(`"_id":"`)); start >= 0 {
+			rest := lines[i][start+len(`"_id":"`):]
+			id = string(rest[:bytes.IndexByte(rest, '"')])
+		}
+		if s.reject[id] {
+			hasErrors = true
+			items = append(items, fmt.Sprintf(`{"index":{"_id":%q,"status":400,"error":{"type":"mapper_parsing_exception","reason":"bad doc"}}}`, id))
+		} else {
+			items = append(items, fmt.Sprintf(`{"index":{"_id":%q,"status":201,"result":"created"}}`, id))
+		}
+	}
+
+	s.mu.Lock()
+	s.requests++
+	s.docs += len(items)
+	s.mu.Unlock()
+
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"errors":%t,"items":[%s]}`, hasErrors, strings.Join(items, ",")))),
+		Request:    req,
+	}, nil
+}
+
+func (s *bulkStub) counts() (requests, docs int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests, s.docs
+}
+
+// newBulkStubClient creates a client whose requests are answered by the bulk stub
+func newBulkStubClient(t *testing.T, stub *bulkStub) *Client {
+	t.Helper()
+
+	client, err := newClient(Config{Addresses: []string{"http://stub:9200"}, DisableRetry: true}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+	return client
+}
+
+func TestBulkIndexer_Batching(t *testing.T) {
+	stub := &bulkStub{}
+	indexer := NewBulkIndexer(newBulkStubClient(t, stub), "bulk-index", BulkIndexerOptions{
+		FlushDocs: 100,
+		Workers:   4,
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 1000; i++ {
+		if err := indexer.Add(ctx, map[string]interface{}{"_id": fmt.Sprintf("doc-%d", i), "seq": i}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := indexer.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	requests, docs := stub.counts()
+	if requests != 10 || docs != 1000 {
+		t.Errorf("Sent %d documents in %d requests, want 1000 in 10", docs, requests)
+	}
+	want := BulkIndexerStats{Added: 1000, Indexed: 1000, Requests: 10}
+	if stats := indexer.Stats(); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+}
+
+func TestBulkIndexer_FlushBytes(t *testing.T) {
+	stub := &bulkStub{}
+	indexer := NewBulkIndexer(newBulkStubClient(t, stub), "bulk-index", BulkIndexerOptions{
+		FlushBytes: 200,
+		Workers:    1,
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		doc := map[string]interface{}{"_id": fmt.Sprintf("doc-%d", i), "text": strings.Repeat("x", 100)}
+		if err := indexer.Add(ctx, doc); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := indexer.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Each document's lines are over 100 bytes, so every second document fills a batch
+	if requests, docs := stub.counts(); requests != 5 || docs != 10 {
+		t.Errorf("Sent %d documents in %d requests, want 10 in 5", docs, requests)
+	}
+}
+
+func TestBulkIndexer_FlushInterval(t *testing.T) {
+	stub := &bulkStub{}
+	indexer := NewBulkIndexer(newBulkStubClient(t, stub), "bulk-index", BulkIndexerOptions{
+		FlushInterval: 20 * time.Millisecond,
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := indexer.Add(ctx, map[string]interface{}{"seq": i}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, docs := stub.counts(); docs == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Documents were not flushed by the interval before Close")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := indexer.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if requests, _ := stub.counts(); requests != 1 {
+		t.Errorf("Sent %d requests, want 1", requests)
+	}
+}
+
+func TestBulkIndexer_ItemErrors(t *testing.T) {
+	stub := &bulkStub{reject: map[string]bool{"doc-3": true, "doc-7": true}}
+	var reported []error
+	var mu sync.Mutex
+	indexer := NewBulkIndexer(newBulkStubClient(t, stub), "bulk-index", BulkIndexerOptions{
+		FlushDocs: 5,
+		OnError: func(err error) {
+			mu.Lock()
+			reported = append(reported, err)
+			mu.Unlock()
+		},
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		if err := indexer.Add(ctx, map[string]interface{}{"_id": fmt.Sprintf("doc-%d", i)}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	err := indexer.Close(ctx)
+	if err == nil || !strings.Contains(err.Error(), "2 of 10 documents failed") {
+		t.Errorf("Close() error = %v, want 2 of 10 documents failed", err)
+	}
+	want := BulkIndexerStats{Added: 10, Indexed: 8, Failed: 2, Requests: 2}
+	if stats := indexer.Stats(); stats != want {
+		t.Errorf("Stats() = %+v, want %+v", stats, want)
+	}
+	if len(reported) != 2 {
+		t.Errorf("OnError called %d times, want once per failed batch (2)", len(reported))
+	}
+}
+
+func TestBulkIndexer_AddAfterClose(t *testing.T) {
+	indexer := NewBulkIndexer(newBulkStubClient(t, &bulkStub{}), "bulk-index", BulkIndexerOptions{})
+
+	ctx := context.Background()
+	if err := indexer.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := indexer.Add(ctx, map[string]interface{}{"seq": 1}); err == nil {
+		t.Error("Add() after Close error = nil, want an error")
+	}
+	if err := indexer.Close(ctx); err == nil {
+		t.Error("Second Close() error = nil, want an error")
+	}
+}
+
+func TestBulkIndexer(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-bulk-indexer"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	indexer := NewBulkIndexer(client, indexName, BulkIndexerOptions{FlushDocs: 150, Workers: 3})
+	for i := 0; i < 1000; i++ {
+		if err := indexer.Add(ctx, map[string]interface{}{"_id": fmt.Sprintf("doc-%d", i), "seq": i}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if err := indexer.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := client.RefreshIndex(ctx, indexName); err != nil {
+		t.Fatalf("RefreshIndex() error = %v", err)
+	}
+	stats, err := client.IndexStats(ctx, indexName)
+	if err != nil {
+		t.Fatalf("IndexStats() error = %v", err)
+	}
+	if got := stats[indexName].DocsCount; got != 1000 {
+		t.Errorf("DocsCount = %d, want 1000", got)
+	}
+}
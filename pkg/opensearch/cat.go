@@ -0,0 +1,242 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// ShardInfo describes a shard copy as listed by the cat shards API
+type ShardInfo struct {
+	Index  string
+	Shard  int
+	PriRep string // "p" for a primary, "r" for a replica
+	State  string
+	Docs   int64
+	Store  int64 // bytes
+	IP     string
+	Node   string
+}
+
+// AllocationInfo describes a node's shard count and disk usage as listed by the cat allocation API.
+// Unassigned shards are reported on a row whose Node is "UNASSIGNED".
+type AllocationInfo struct {
+	Node        string
+	Host        string
+	IP          string
+	Shards      int
+	DiskIndices int64 // bytes
+	DiskUsed    int64 // bytes
+	DiskAvail   int64 // bytes
+	DiskTotal   int64 // bytes
+	DiskPercent int
+}
+
+// CatNodeInfo describes a node's load as listed by the cat nodes API
+type CatNodeInfo struct {
+	Name           string
+	IP             string
+	HeapPercent    int
+	RAMPercent     int
+	CPU            int
+	Load1m         float64
+	NodeRole       string
+	ClusterManager bool
+}
+
+// CatIndices returns every index in the cluster with its health, doc count and size, sorted by name
+func (c *Client) CatIndices(ctx context.Context) (_ []IndexSummary, err error) {
+	defer c.observe("CatIndices", time.Now(), &err)
+
+	return c.catIndices(ctx, nil)
+}
+
+// CatShards returns the shard copies of the indices matching index (all indices when empty)
+func (c *Client) CatShards(ctx context.Context, index string) (_ []ShardInfo, err error) {
+	defer c.observe("CatShards", time.Now(), &err)
+
+	return c.catShards(ctx, index)
+}
+
+// catShards lists the shard copies of the indices matching index (all indices when empty)
+func (c *Client) catShards(ctx context.Context, index string) ([]ShardInfo, error) {
+	req := opensearchapi.CatShardsRequest{
+		Format: "json",
+		Bytes:  "b",
+		H:      []string{"index", "shard", "prirep", "state", "docs", "store", "ip", "node"},
+		S:      []string{"index", "shard", "prirep"},
+	}
+	if index != "" {
+		req.Index = []string{index}
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shards: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return nil, requestError("cat shards", res)
+	}
+
+	var rows []struct {
+		Index  string  `json:"index"`
+		Shard  *string `json:"shard"`
+		PriRep string  `json:"prirep"`
+		State  string  `json:"state"`
+		Docs   *string `json:"docs"`
+		Store  *string `json:"store"`
+		IP     string  `json:"ip"`
+		Node   string  `json:"node"`
+	}
+	if err := c.parseResponse(res.Body, &rows); err != nil {
+		return nil, err
+	}
+
+	shards := make([]ShardInfo, 0, len(rows))
+	for _, row := range rows {
+		shards = append(shards, ShardInfo{
+			Index:  row.Index,
+			Shard:  int(catInt(row.Shard)),
+			PriRep: row.PriRep,
+			State:  row.State,
+			Docs:   catInt(row.Docs),
+			Store:  catInt(row.Store),
+			IP:     row.IP,
+			Node:   row.Node,
+		})
+	}
+
+	return shards, nil
+}
+
+// CatAllocation returns the number of shards and disk usage of every data node
+func (c *Client) CatAllocation(ctx context.Context) (_ []AllocationInfo, err error) {
+	defer c.observe("CatAllocation", time.Now(), &err)
+
+	req := opensearchapi.CatAllocationRequest{
+		Format: "json",
+		Bytes:  "b",
+		H:      []string{"node", "host", "ip", "shards", "disk.indices", "disk.used", "disk.avail", "disk.total", "disk.percent"},
+		S:      []string{"node"},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allocation: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("cat allocation", res)
+	}
+
+	var rows []struct {
+		Node        string  `json:"node"`
+		Host        string  `json:"host"`
+		IP          string  `json:"ip"`
+		Shards      *string `json:"shards"`
+		DiskIndices *string `json:"disk.indices"`
+		DiskUsed    *string `json:"disk.used"`
+		DiskAvail   *string `json:"disk.avail"`
+		DiskTotal   *string `json:"disk.total"`
+		DiskPercent *string `json:"disk.percent"`
+	}
+	if err := c.parseResponse(res.Body, &rows); err != nil {
+		return nil, err
+	}
+
+	allocation := make([]AllocationInfo, 0, len(rows))
+	for _, row := range rows {
+		allocation = append(allocation, AllocationInfo{
+			Node:        row.Node,
+			Host:        row.Host,
+			IP:          row.IP,
+			Shards:      int(catInt(row.Shards)),
+			DiskIndices: catInt(row.DiskIndices),
+			DiskUsed:    catInt(row.DiskUsed),
+			DiskAvail:   catInt(row.DiskAvail),
+			DiskTotal:   catInt(row.DiskTotal),
+			DiskPercent: int(catInt(row.DiskPercent)),
+		})
+	}
+
+	return allocation, nil
+}
+
+// CatNodes returns the load, memory usage and roles of every node
+func (c *Client) CatNodes(ctx context.Context) (_ []CatNodeInfo, err error) {
+	defer c.observe("CatNodes", time.Now(), &err)
+
+	req := opensearchapi.CatNodesRequest{
+		Format: "json",
+		H:      []string{"name", "ip", "heap.percent", "ram.percent", "cpu", "load_1m", "node.role", "cluster_manager"},
+		S:      []string{"name"},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("cat nodes", res)
+	}
+
+	var rows []struct {
+		Name           string  `json:"name"`
+		IP             string  `json:"ip"`
+		HeapPercent    *string `json:"heap.percent"`
+		RAMPercent     *string `json:"ram.percent"`
+		CPU            *string `json:"cpu"`
+		Load1m         *string `json:"load_1m"`
+		NodeRole       string  `json:"node.role"`
+		ClusterManager string  `json:"cluster_manager"`
+	}
+	if err := c.parseResponse(res.Body, &rows); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]CatNodeInfo, 0, len(rows))
+	for _, row := range rows {
+		nodes = append(nodes, CatNodeInfo{
+			Name:           row.Name,
+			IP:             row.IP,
+			HeapPercent:    int(catInt(row.HeapPercent)),
+			RAMPercent:     int(catInt(row.RAMPercent)),
+			CPU:            int(catInt(row.CPU)),
+			Load1m:         catFloat(row.Load1m),
+			NodeRole:       row.NodeRole,
+			ClusterManager: row.ClusterManager == "*",
+		})
+	}
+
+	return nodes, nil
+}
+
+// catInt parses a numeric cat API column, which is returned as a string and is null
+// (or empty) when not applicable, e.g. for unassigned shards. Missing values parse as 0.
+func catInt(v *string) int64 {
+	if v == nil {
+		return 0
+	}
+	n, _ := strconv.ParseInt(*v, 10, 64)
+	return n
+}
+
+// catFloat parses a decimal cat API column, see catInt
+func catFloat(v *string) float64 {
+	if v == nil {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(*v, 64)
+	return f
+}
@@ -0,0 +1,132 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCatInt(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		in   *string
+		want int64
+	}{
+		{name: "Number", in: str("208"), want: 208},
+		{name: "Null", in: nil, want: 0},
+		{name: "Empty", in: str(""), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := catInt(tt.in); got != tt.want {
+				t.Errorf("catInt() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := catFloat(str("1.25")); got != 1.25 {
+		t.Errorf("catFloat() = %v, want 1.25", got)
+	}
+}
+
+func TestCatIndices(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-cat-indices"
+	cleanup := setupShardedIndex(t, client, indexName, 1)
+	defer cleanup()
+
+	if err := client.waitForIndexHealth(context.Background(), indexName, "green", false); err != nil {
+		t.Fatalf("Index did not go green: %v", err)
+	}
+	seedDocuments(t, client, indexName, 3)
+
+	indices, err := client.CatIndices(context.Background())
+	if err != nil {
+		t.Fatalf("CatIndices() error = %v", err)
+	}
+
+	var found *IndexSummary
+	for i := range indices {
+		if indices[i].Index == indexName {
+			found = &indices[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("CatIndices() did not list %s: %+v", indexName, indices)
+	}
+	if found.Health != "green" || found.DocsCount != 3 || found.StoreSize <= 0 {
+		t.Errorf("CatIndices() = %+v, want green with 3 docs and a non-zero size", *found)
+	}
+}
+
+func TestCatShards(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-cat-shards"
+	cleanup := setupShardedIndex(t, client, indexName, 1)
+	defer cleanup()
+
+	if err := client.waitForIndexHealth(context.Background(), indexName, "green", false); err != nil {
+		t.Fatalf("Index did not go green: %v", err)
+	}
+	seedDocuments(t, client, indexName, 5)
+
+	shards, err := client.CatShards(context.Background(), indexName)
+	if err != nil {
+		t.Fatalf("CatShards() error = %v", err)
+	}
+
+	if len(shards) != 1 {
+		t.Fatalf("CatShards() returned %d shards, want 1: %+v", len(shards), shards)
+	}
+	shard := shards[0]
+	if shard.Index != indexName || shard.Shard != 0 || shard.PriRep != "p" || shard.State != "STARTED" {
+		t.Errorf("CatShards() = %+v, want one STARTED primary shard 0 of %s", shard, indexName)
+	}
+	if shard.Docs != 5 {
+		t.Errorf("Docs = %d, want 5", shard.Docs)
+	}
+	if shard.Node == "" {
+		t.Error("Node is empty for a started shard")
+	}
+}
+
+func TestCatAllocationAndNodes(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	allocation, err := client.CatAllocation(ctx)
+	if err != nil {
+		t.Fatalf("CatAllocation() error = %v", err)
+	}
+	if len(allocation) == 0 {
+		t.Fatal("CatAllocation() returned no rows")
+	}
+	for _, row := range allocation {
+		if row.Node != "UNASSIGNED" && row.DiskTotal <= 0 {
+			t.Errorf("Node %s DiskTotal = %d, want > 0", row.Node, row.DiskTotal)
+		}
+	}
+
+	nodes, err := client.CatNodes(ctx)
+	if err != nil {
+		t.Fatalf("CatNodes() error = %v", err)
+	}
+	if len(nodes) == 0 {
+		t.Fatal("CatNodes() returned no rows")
+	}
+
+	managers := 0
+	for _, node := range nodes {
+		if node.Name == "" || node.IP == "" {
+			t.Errorf("Node row missing name or IP: %+v", node)
+		}
+		if node.ClusterManager {
+			managers++
+		}
+	}
+	if managers != 1 {
+		t.Errorf("Expected exactly one elected cluster manager, got %d", managers)
+	}
+}
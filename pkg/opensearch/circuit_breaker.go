@@ -0,0 +1,165 @@
+package opensearch
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultBreakerThreshold is the number of consecutive failures that opens a breaker
+	// when CircuitBreakerConfig.FailureThreshold is 0
+	defaultBreakerThreshold = 5
+	// defaultBreakerOpenDuration is how long a breaker stays open when
+	// CircuitBreakerConfig.OpenDuration is 0
+	defaultBreakerOpenDuration = 30 * time.Second
+)
+
+// CircuitBreakerConfig configures the client's circuit breaker. Each node address has its
+// own breaker, which opens after FailureThreshold consecutive failed requests to it. While
+// a breaker is open, requests to that address fail straight away with ErrCircuitOpen. Once
+// OpenDuration has passed a single probe request is let through: if it succeeds the breaker
+// closes, otherwise it opens again.
+//
+// A request fails when no response is received or when the response status is one of
+// Config.RetryOnStatus (by default 502, 503 and 504).
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker (default 5)
+	FailureThreshold int
+	// OpenDuration is how long requests fail fast before a probe is sent (default 30s)
+	OpenDuration time.Duration
+}
+
+// breakerState is the state of the circuit breaker of one address
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	// breakerHalfOpen means a probe request is in flight
+	breakerHalfOpen
+)
+
+// breaker tracks the failures of one address
+type breaker struct {
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// circuitBreakerTransport fails requests fast while the breaker of their address is open
+type circuitBreakerTransport struct {
+	next         http.RoundTripper
+	threshold    int
+	openDuration time.Duration
+	failOnStatus map[int]bool
+	now          func() time.Time
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// newCircuitBreakerTransport wraps next with a circuit breaker per address. Responses with
+// a status in failOnStatus count as failures.
+func newCircuitBreakerTransport(next http.RoundTripper, config CircuitBreakerConfig, failOnStatus []int) *circuitBreakerTransport {
+	threshold := config.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	openDuration := config.OpenDuration
+	if openDuration <= 0 {
+		openDuration = defaultBreakerOpenDuration
+	}
+	statuses := make(map[int]bool, len(failOnStatus))
+	for _, status := range failOnStatus {
+		statuses[status] = true
+	}
+
+	return &circuitBreakerTransport{
+		next:         next,
+		threshold:    threshold,
+		openDuration: openDuration,
+		failOnStatus: statuses,
+		now:          time.Now,
+		breakers:     make(map[string]*breaker),
+	}
+}
+
+// RoundTrip sends the request unless the breaker of its address is open
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	address := req.URL.Host
+	if err := t.allow(address); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	res, err := t.next.RoundTrip(req)
+
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// The caller's context ended, which says nothing about the node
+		t.release(address)
+	case err != nil:
+		t.record(address, true)
+	default:
+		t.record(address, t.failOnStatus[res.StatusCode])
+	}
+
+	return res, err
+}
+
+// allow returns ErrCircuitOpen when the breaker of address is open, moving it to half-open
+// for a probe once the open duration has passed
+func (t *circuitBreakerTransport) allow(address string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.breakers[address]
+	if b == nil || b.state == breakerClosed {
+		return nil
+	}
+	if b.state == breakerOpen && t.now().Sub(b.openedAt) >= t.openDuration {
+		b.state = breakerHalfOpen
+		return nil
+	}
+
+	return fmt.Errorf("%w for %s", ErrCircuitOpen, address)
+}
+
+// record counts the outcome of a request to address, opening its breaker after threshold
+// consecutive failures or a failed probe
+func (t *circuitBreakerTransport) record(address string, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !failed {
+		delete(t.breakers, address)
+		return
+	}
+
+	b := t.breakers[address]
+	if b == nil {
+		b = &breaker{}
+		t.breakers[address] = b
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= t.threshold {
+		b.state = breakerOpen
+		b.openedAt = t.now()
+	}
+}
+
+// release returns a half-open breaker to open without counting a failure, so the next
+// request is let through as the probe
+func (t *circuitBreakerTransport) release(address string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if b := t.breakers[address]; b != nil && b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
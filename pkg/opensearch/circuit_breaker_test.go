@@ -0,0 +1,170 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// roundTripperFunc adapts a function to an http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTestBreaker creates a circuit breaker around stub with a clock that only moves when
+// the returned advance is called
+func newTestBreaker(stub *stubTransport, config CircuitBreakerConfig) (*circuitBreakerTransport, func(time.Duration)) {
+	breaker := newCircuitBreakerTransport(stub, config, []int{502, 503, 504})
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+	return breaker, func(d time.Duration) { now = now.Add(d) }
+}
+
+// sendTo sends a request to host through the transport
+func sendTo(t *testing.T, transport http.RoundTripper, ctx context.Context, host string) error {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+host+"/", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	res, err := transport.RoundTrip(req)
+	if err == nil {
+		res.Body.Close()
+	}
+	return err
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	stub := &stubTransport{status: 503, body: `{}`}
+	breaker, _ := newTestBreaker(stub, CircuitBreakerConfig{FailureThreshold: 3, OpenDuration: time.Minute})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := sendTo(t, breaker, ctx, "a:9200"); err != nil {
+			t.Fatalf("Request %d error = %v, want the 503 response", i+1, err)
+		}
+	}
+
+	err := sendTo(t, breaker, ctx, "a:9200")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Request after 3 failures error = %v, want ErrCircuitOpen", err)
+	}
+	if stub.calls != 3 {
+		t.Errorf("Transport was called %d times, want 3", stub.calls)
+	}
+
+	// Breakers are kept per address
+	if err := sendTo(t, breaker, ctx, "b:9200"); err != nil {
+		t.Errorf("Request to another address error = %v, want it sent", err)
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	stub := &stubTransport{statuses: []int{503, 503, 200, 503, 503}, status: 200, body: `{}`}
+	breaker, _ := newTestBreaker(stub, CircuitBreakerConfig{FailureThreshold: 3, OpenDuration: time.Minute})
+
+	for i := 0; i < 6; i++ {
+		if err := sendTo(t, breaker, context.Background(), "a:9200"); err != nil {
+			t.Fatalf("Request %d error = %v, want no breaker after non-consecutive failures", i+1, err)
+		}
+	}
+}
+
+func TestCircuitBreaker_Recovery(t *testing.T) {
+	stub := &stubTransport{status: 503, body: `{}`}
+	breaker, advance := newTestBreaker(stub, CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+	ctx := context.Background()
+
+	_ = sendTo(t, breaker, ctx, "a:9200")
+	if err := sendTo(t, breaker, ctx, "a:9200"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Request error = %v, want ErrCircuitOpen", err)
+	}
+
+	// A failed probe opens the breaker again
+	advance(time.Minute)
+	if err := sendTo(t, breaker, ctx, "a:9200"); err != nil {
+		t.Fatalf("Probe error = %v, want the 503 response", err)
+	}
+	if err := sendTo(t, breaker, ctx, "a:9200"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Request after failed probe error = %v, want ErrCircuitOpen", err)
+	}
+
+	// Only the probe is sent while it is in flight, and its success closes the breaker
+	advance(time.Minute)
+	stub.status = 200
+	var concurrent error
+	stub.onRequest = func() {
+		concurrent = sendTo(t, breaker, ctx, "a:9200")
+	}
+	if err := sendTo(t, breaker, ctx, "a:9200"); err != nil {
+		t.Fatalf("Probe error = %v", err)
+	}
+	if !errors.Is(concurrent, ErrCircuitOpen) {
+		t.Errorf("Request during probe error = %v, want ErrCircuitOpen", concurrent)
+	}
+
+	stub.onRequest = nil
+	if err := sendTo(t, breaker, ctx, "a:9200"); err != nil {
+		t.Errorf("Request after successful probe error = %v, want it sent", err)
+	}
+}
+
+func TestCircuitBreaker_CanceledProbe(t *testing.T) {
+	stub := &stubTransport{status: 503, body: `{}`}
+	breaker, advance := newTestBreaker(stub, CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+
+	_ = sendTo(t, breaker, context.Background(), "a:9200")
+	advance(time.Minute)
+
+	// A probe abandoned by its caller does not count, so the next request probes again
+	ctx, cancel := context.WithCancel(context.Background())
+	breaker.next = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		cancel()
+		return nil, req.Context().Err()
+	})
+	if err := sendTo(t, breaker, ctx, "a:9200"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Canceled probe error = %v, want context.Canceled", err)
+	}
+
+	breaker.next = stub
+	stub.status = 200
+	if err := sendTo(t, breaker, context.Background(), "a:9200"); err != nil {
+		t.Errorf("Request after canceled probe error = %v, want a new probe", err)
+	}
+}
+
+func TestCircuitBreaker_Client(t *testing.T) {
+	stub := &stubTransport{status: 503, body: `{}`}
+	client, err := newClient(Config{
+		Addresses:      []string{"http://stub:9200"},
+		DisableRetry:   true,
+		CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Hour},
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetDocument(ctx, "my-index", "1"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("GetDocument() error = %v, want the 503", err)
+		}
+	}
+
+	start := time.Now()
+	_, err = client.GetDocument(ctx, "my-index", "1")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("GetDocument() error = %v, want ErrCircuitOpen", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("GetDocument() took %s with the breaker open, want it to fail fast", elapsed)
+	}
+	if stub.calls != 2 {
+		t.Errorf("Transport was called %d times, want 2", stub.calls)
+	}
+}
@@ -0,0 +1,188 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// ClusterHealth represents the response of the cluster health API
+type ClusterHealth struct {
+	ClusterName                 string  `json:"cluster_name"`
+	Status                      string  `json:"status"`
+	TimedOut                    bool    `json:"timed_out"`
+	NumberOfNodes               int     `json:"number_of_nodes"`
+	NumberOfDataNodes           int     `json:"number_of_data_nodes"`
+	ActivePrimaryShards         int     `json:"active_primary_shards"`
+	ActiveShards                int     `json:"active_shards"`
+	RelocatingShards            int     `json:"relocating_shards"`
+	InitializingShards          int     `json:"initializing_shards"`
+	UnassignedShards            int     `json:"unassigned_shards"`
+	NumberOfPendingTasks        int     `json:"number_of_pending_tasks"`
+	ActiveShardsPercentAsNumber float64 `json:"active_shards_percent_as_number"`
+}
+
+// ClusterSettings holds cluster-level settings keyed by their flat dotted name,
+// e.g. "cluster.routing.allocation.enable"
+type ClusterSettings struct {
+	Persistent map[string]interface{}
+	Transient  map[string]interface{}
+	// Defaults is only populated when requested
+	Defaults map[string]interface{}
+}
+
+// ClusterHealth returns the health of the whole cluster
+func (c *Client) ClusterHealth(ctx context.Context) (_ *ClusterHealth, err error) {
+	defer c.observe("ClusterHealth", time.Now(), &err)
+
+	return c.clusterHealth(ctx, opensearchapi.ClusterHealthRequest{})
+}
+
+// ClusterHealthForIndex returns the health of the cluster restricted to the given indices
+func (c *Client) ClusterHealthForIndex(ctx context.Context, indices ...string) (_ *ClusterHealth, err error) {
+	defer c.observe("ClusterHealthForIndex", time.Now(), &err)
+
+	return c.clusterHealth(ctx, opensearchapi.ClusterHealthRequest{
+		Index: indices,
+	})
+}
+
+// maxHealthWaitMargin caps the time WaitForClusterStatus leaves between the end of the
+// server-side wait and the context deadline
+const maxHealthWaitMargin = time.Second
+
+// WaitForClusterStatus blocks until the cluster reaches at least the given status
+// ("green", "yellow" or "red"). The server-side wait is bounded by timeout, or by
+// the context deadline when that is sooner; it then ends shortly before the deadline,
+// so a timeout is reported with the status the cluster is in.
+func (c *Client) WaitForClusterStatus(ctx context.Context, status string, timeout time.Duration) (err error) {
+	defer c.observe("WaitForClusterStatus", time.Now(), &err)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline)
+		remaining -= min(remaining/10, maxHealthWaitMargin)
+		if timeout <= 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+
+	health, err := c.clusterHealth(ctx, opensearchapi.ClusterHealthRequest{
+		WaitForStatus: status,
+		Timeout:       timeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	if health.TimedOut {
+		return fmt.Errorf("timed out after %s waiting for cluster status %s, cluster is %s", timeout, status, health.Status)
+	}
+
+	return nil
+}
+
+// clusterHealth executes a cluster health request. A request that timed out waiting
+// for a condition is not an error; it is reported through ClusterHealth.TimedOut.
+func (c *Client) clusterHealth(ctx context.Context, req opensearchapi.ClusterHealthRequest) (*ClusterHealth, error) {
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster health: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != 408 {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return nil, requestError("cluster health", res)
+	}
+
+	var health ClusterHealth
+	if err := c.parseResponse(res.Body, &health); err != nil {
+		return nil, err
+	}
+
+	return &health, nil
+}
+
+// GetClusterSettings returns the persistent and transient cluster settings, and the
+// default value of every other setting when includeDefaults is set. Keys are flat
+// dotted names regardless of how the settings were written.
+func (c *Client) GetClusterSettings(ctx context.Context, includeDefaults bool) (_ *ClusterSettings, err error) {
+	defer c.observe("GetClusterSettings", time.Now(), &err)
+
+	flatSettings := true
+	req := opensearchapi.ClusterGetSettingsRequest{
+		FlatSettings:    &flatSettings,
+		IncludeDefaults: &includeDefaults,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster settings: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("get cluster settings", res)
+	}
+
+	var response struct {
+		Persistent map[string]interface{} `json:"persistent"`
+		Transient  map[string]interface{} `json:"transient"`
+		Defaults   map[string]interface{} `json:"defaults"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	return &ClusterSettings{
+		Persistent: response.Persistent,
+		Transient:  response.Transient,
+		Defaults:   response.Defaults,
+	}, nil
+}
+
+// PutClusterSettings updates persistent and/or transient cluster settings. Keys may be
+// flat dotted names or nested maps; a nil value resets a setting to its default.
+func (c *Client) PutClusterSettings(ctx context.Context, persistent, transient map[string]interface{}) (err error) {
+	defer c.observe("PutClusterSettings", time.Now(), &err)
+
+	body := map[string]interface{}{}
+	if persistent != nil {
+		body["persistent"] = persistent
+	}
+	if transient != nil {
+		body["transient"] = transient
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster settings: %w", err)
+	}
+
+	req := opensearchapi.ClusterPutSettingsRequest{
+		Body: bytes.NewReader(data),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to update cluster settings: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return requestError("update cluster settings", res)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClusterHealth(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	health, err := client.ClusterHealth(ctx)
+	if err != nil {
+		t.Fatalf("ClusterHealth() error = %v", err)
+	}
+
+	if health.ClusterName == "" {
+		t.Error("ClusterName is empty")
+	}
+	switch health.Status {
+	case "green", "yellow", "red":
+	default:
+		t.Errorf("Status = %q, want green, yellow or red", health.Status)
+	}
+	if health.NumberOfNodes < 1 || health.NumberOfDataNodes < 1 {
+		t.Errorf("Node counts = %d nodes, %d data nodes, want at least 1", health.NumberOfNodes, health.NumberOfDataNodes)
+	}
+	if health.ActiveShards < health.ActivePrimaryShards {
+		t.Errorf("ActiveShards = %d, less than ActivePrimaryShards = %d", health.ActiveShards, health.ActivePrimaryShards)
+	}
+}
+
+func TestClusterHealthForIndex(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-cluster-health-index"
+	cleanup := setupShardedIndex(t, client, indexName, 2)
+	defer cleanup()
+
+	health, err := client.ClusterHealthForIndex(context.Background(), indexName)
+	if err != nil {
+		t.Fatalf("ClusterHealthForIndex() error = %v", err)
+	}
+	if health.ActivePrimaryShards != 2 {
+		t.Errorf("ActivePrimaryShards = %d, want 2", health.ActivePrimaryShards)
+	}
+}
+
+func TestWaitForClusterStatus(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	// A single-node cluster with replicas configured stays yellow, so wait for that
+	start := time.Now()
+	if err := client.WaitForClusterStatus(ctx, "yellow", 10*time.Second); err != nil {
+		t.Fatalf("WaitForClusterStatus() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("WaitForClusterStatus() took %s on a healthy cluster, want it to return promptly", elapsed)
+	}
+
+	t.Run("Context deadline bounds the wait", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		start := time.Now()
+		_ = client.WaitForClusterStatus(ctx, "green", time.Minute)
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("WaitForClusterStatus() took %s, want it bounded by the 2s context deadline", elapsed)
+		}
+	})
+}
+
+func TestWaitForClusterStatus_Deadline(t *testing.T) {
+	t.Run("server wait ends before the deadline", func(t *testing.T) {
+		stub := &stubTransport{status: 408, body: `{"status":"yellow","timed_out":true}`}
+		client := newStubClient(t, stub)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := client.WaitForClusterStatus(ctx, "green", time.Minute)
+		if err == nil || !strings.Contains(err.Error(), "cluster is yellow") {
+			t.Errorf("WaitForClusterStatus() error = %v, want a timeout with the cluster status", err)
+		}
+		query, _ := url.ParseQuery(stub.query)
+		timeout, parseErr := time.ParseDuration(query.Get("timeout"))
+		if parseErr != nil || timeout < 8*time.Second || timeout > 9*time.Second {
+			t.Errorf("timeout = %q, want about a second less than the 10s deadline", query.Get("timeout"))
+		}
+	})
+
+	t.Run("context already done", func(t *testing.T) {
+		stub := &stubTransport{status: 200, body: `{"status":"green"}`}
+		client := newStubClient(t, stub)
+		ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+		defer cancel()
+
+		err := client.WaitForClusterStatus(ctx, "green", time.Minute)
+		if !errors.Is(err, context.DeadlineExceeded) || stub.calls != 0 {
+			t.Errorf("WaitForClusterStatus() error = %v after %d requests, want context.DeadlineExceeded and none", err, stub.calls)
+		}
+	})
+}
+
+func TestClusterSettings(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	const setting = "cluster.routing.allocation.enable"
+	defer func() {
+		_ = client.PutClusterSettings(ctx, map[string]interface{}{setting: nil}, nil)
+	}()
+
+	if err := client.PutClusterSettings(ctx, map[string]interface{}{setting: "primaries"}, nil); err != nil {
+		t.Fatalf("PutClusterSettings() error = %v", err)
+	}
+
+	settings, err := client.GetClusterSettings(ctx, false)
+	if err != nil {
+		t.Fatalf("GetClusterSettings() error = %v", err)
+	}
+	if settings.Persistent[setting] != "primaries" {
+		t.Errorf("Persistent[%s] = %v, want primaries", setting, settings.Persistent[setting])
+	}
+	if len(settings.Defaults) != 0 {
+		t.Error("Defaults should be empty when not requested")
+	}
+
+	t.Run("Nested keys are read back flat", func(t *testing.T) {
+		nested := map[string]interface{}{
+			"cluster": map[string]interface{}{
+				"routing": map[string]interface{}{
+					"allocation": map[string]interface{}{"enable": "all"},
+				},
+			},
+		}
+		if err := client.PutClusterSettings(ctx, nested, nil); err != nil {
+			t.Fatalf("PutClusterSettings() error = %v", err)
+		}
+
+		settings, err := client.GetClusterSettings(ctx, false)
+		if err != nil {
+			t.Fatalf("GetClusterSettings() error = %v", err)
+		}
+		if settings.Persistent[setting] != "all" {
+			t.Errorf("Persistent[%s] = %v, want all", setting, settings.Persistent[setting])
+		}
+	})
+
+	t.Run("Reset to default", func(t *testing.T) {
+		if err := client.PutClusterSettings(ctx, map[string]interface{}{setting: nil}, nil); err != nil {
+			t.Fatalf("PutClusterSettings() reset error = %v", err)
+		}
+
+		settings, err := client.GetClusterSettings(ctx, true)
+		if err != nil {
+			t.Fatalf("GetClusterSettings() error = %v", err)
+		}
+		if _, ok := settings.Persistent[setting]; ok {
+			t.Errorf("Persistent[%s] should be unset after reset", setting)
+		}
+		if settings.Defaults[setting] != "all" {
+			t.Errorf("Defaults[%s] = %v, want all", setting, settings.Defaults[setting])
+		}
+	})
+
+	t.Run("Unknown setting surfaces server reason", func(t *testing.T) {
+		err := client.PutClusterSettings(ctx, map[string]interface{}{"cluster.no_such_setting": true}, nil)
+		if err == nil {
+			t.Fatal("PutClusterSettings() with unknown setting expected error but got nil")
+		}
+		if !strings.Contains(err.Error(), "cluster.no_such_setting") {
+			t.Errorf("PutClusterSettings() error = %v, want the server's reason", err)
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"gopkg.in/yaml.v3"
+)
+
+// envVarPattern matches a ${NAME} reference in a config file value
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// fileConfig is the layout of a config file read by LoadConfig. Keys are the snake_case
+// names of the Config fields they set.
+type fileConfig struct {
+	Addresses          []string          `yaml:"addresses" json:"addresses"`
+	Username           string            `yaml:"username" json:"username"`
+	Password           string            `yaml:"password" json:"password"`
+	InsecureSkipVerify bool              `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	CACertPath         string            `yaml:"ca_cert_path" json:"ca_cert_path"`
+	ProxyURL           string            `yaml:"proxy_url" json:"proxy_url"`
+	BearerToken        string            `yaml:"bearer_token" json:"bearer_token"`
+	Headers            map[string]string `yaml:"headers" json:"headers"`
+	AWS                *struct {
+		Region          string `yaml:"region" json:"region"`
+		Service         string `yaml:"service" json:"service"`
+		AccessKeyID     string `yaml:"access_key_id" json:"access_key_id"`
+		SecretAccessKey string `yaml:"secret_access_key" json:"secret_access_key"`
+		SessionToken    string `yaml:"session_token" json:"session_token"`
+	} `yaml:"aws" json:"aws"`
+
+	MaxIdleConns            int      `yaml:"max_idle_conns" json:"max_idle_conns"`
+	MaxIdleConnsPerHost     int      `yaml:"max_idle_conns_per_host" json:"max_idle_conns_per_host"`
+	MaxConnsPerHost         int      `yaml:"max_conns_per_host" json:"max_conns_per_host"`
+	IdleConnTimeout         duration `yaml:"idle_conn_timeout" json:"idle_conn_timeout"`
+	RequestTimeout          duration `yaml:"request_timeout" json:"request_timeout"`
+	DefaultOperationTimeout duration `yaml:"default_operation_timeout" json:"default_operation_timeout"`
+
+	DiscoverNodesOnStart  bool     `yaml:"discover_nodes_on_start" json:"discover_nodes_on_start"`
+	DiscoverNodesInterval duration `yaml:"discover_nodes_interval" json:"discover_nodes_interval"`
+
+	MaxRetries     int      `yaml:"max_retries" json:"max_retries"`
+	DisableRetry   bool     `yaml:"disable_retry" json:"disable_retry"`
+	RetryOnStatus  []int    `yaml:"retry_on_status" json:"retry_on_status"`
+	MaxRetryAfter  duration `yaml:"max_retry_after" json:"max_retry_after"`
+	CircuitBreaker *struct {
+		FailureThreshold int      `yaml:"failure_threshold" json:"failure_threshold"`
+		OpenDuration     duration `yaml:"open_duration" json:"open_duration"`
+	} `yaml:"circuit_breaker" json:"circuit_breaker"`
+
+	UseJSONNumber bool `yaml:"use_json_number" json:"use_json_number"`
+}
+
+// duration is a time.Duration written as a Go duration string, e.g. "30s"
+type duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	return d.parse(s)
+}
+
+// UnmarshalYAML parses a duration string
+func (d *duration) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	return d.parse(s)
+}
+
+// parse sets d from a duration string
+func (d *duration) parse(s string) error {
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+// LoadConfig reads a Config from a YAML (.yaml, .yml) or JSON (.json) file. Keys are the
+// snake_case names of the Config fields, with durations written as strings such as "30s",
+// and unknown keys are an error so that typos are caught. A ${NAME} reference in a string
+// value is replaced with the environment variable NAME, e.g. for passwords.
+//
+// Fields that take Go values, such as TokenProvider, Metrics or Tracer, cannot be set
+// from a file; set them on the returned Config.
+func LoadConfig(path string) (Config, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" && ext != ".json" {
+		return Config{}, fmt.Errorf("unsupported config file extension %q: use .yaml, .yml or .json", ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file fileConfig
+	if ext == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&file)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := file.expandEnv(); err != nil {
+		return Config{}, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return file.config(), nil
+}
+
+// NewClientFromFile creates a client from a config file read by LoadConfig
+func NewClientFromFile(path string) (*Client, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(config)
+}
+
+// expandEnv replaces ${NAME} references in the string values with environment variables
+func (f *fileConfig) expandEnv() error {
+	values := make([]*string, 0, len(f.Addresses)+4)
+	for i := range f.Addresses {
+		values = append(values, &f.Addresses[i])
+	}
+	values = append(values, &f.Username, &f.Password, &f.CACertPath, &f.ProxyURL, &f.BearerToken)
+	if f.AWS != nil {
+		values = append(values, &f.AWS.Region, &f.AWS.Service, &f.AWS.AccessKeyID, &f.AWS.SecretAccessKey, &f.AWS.SessionToken)
+	}
+
+	for _, value := range values {
+		expanded, err := expandEnvValue(*value)
+		if err != nil {
+			return err
+		}
+		*value = expanded
+	}
+	for key, value := range f.Headers {
+		expanded, err := expandEnvValue(value)
+		if err != nil {
+			return err
+		}
+		f.Headers[key] = expanded
+	}
+
+	return nil
+}
+
+// expandEnvValue replaces ${NAME} references in value with environment variables
+func expandEnvValue(value string) (string, error) {
+	var missing string
+	expanded := envVarPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		name := envVarPattern.FindStringSubmatch(ref)[1]
+		env, ok := os.LookupEnv(name)
+		if !ok && missing == "" {
+			missing = name
+		}
+		return env
+	})
+	if missing != "" {
+		return "", fmt.Errorf("environment variable %s is not set", missing)
+	}
+	return expanded, nil
+}
+
+// config converts the file's settings into a Config
+func (f *fileConfig) config() Config {
+	config := Config{
+		Addresses:               f.Addresses,
+		Username:                f.Username,
+		Password:                f.Password,
+		InsecureSkipVerify:      f.InsecureSkipVerify,
+		CACertPath:              f.CACertPath,
+		ProxyURL:                f.ProxyURL,
+		BearerToken:             f.BearerToken,
+		Headers:                 f.Headers,
+		MaxIdleConns:            f.MaxIdleConns,
+		MaxIdleConnsPerHost:     f.MaxIdleConnsPerHost,
+		MaxConnsPerHost:         f.MaxConnsPerHost,
+		IdleConnTimeout:         time.Duration(f.IdleConnTimeout),
+		RequestTimeout:          time.Duration(f.RequestTimeout),
+		DefaultOperationTimeout: time.Duration(f.DefaultOperationTimeout),
+		DiscoverNodesOnStart:    f.DiscoverNodesOnStart,
+		DiscoverNodesInterval:   time.Duration(f.DiscoverNodesInterval),
+		MaxRetries:              f.MaxRetries,
+		DisableRetry:            f.DisableRetry,
+		RetryOnStatus:           f.RetryOnStatus,
+		MaxRetryAfter:           time.Duration(f.MaxRetryAfter),
+		UseJSONNumber:           f.UseJSONNumber,
+	}
+
+	if f.AWS != nil {
+		config.AWS = &AWSConfig{
+			Region:  f.AWS.Region,
+			Service: f.AWS.Service,
+		}
+		if f.AWS.AccessKeyID != "" {
+			config.AWS.Credentials = credentials.NewStaticCredentialsProvider(f.AWS.AccessKeyID, f.AWS.SecretAccessKey, f.AWS.SessionToken)
+		}
+	}
+	if f.CircuitBreaker != nil {
+		config.CircuitBreaker = &CircuitBreakerConfig{
+			FailureThreshold: f.CircuitBreaker.FailureThreshold,
+			OpenDuration:     time.Duration(f.CircuitBreaker.OpenDuration),
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,113 @@
+package opensearch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	want := Config{
+		Addresses:             []string{"https://node-1:9200", "https://node-2:9200"},
+		Username:              "admin",
+		Password:              "admin",
+		CACertPath:            "/etc/opensearch/ca.pem",
+		RequestTimeout:        10 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConnsPerHost:   10,
+		MaxConnsPerHost:       20,
+		DiscoverNodesInterval: 5 * time.Minute,
+		MaxRetries:            5,
+		RetryOnStatus:         []int{502, 503, 504, 429},
+		CircuitBreaker:        &CircuitBreakerConfig{FailureThreshold: 3, OpenDuration: 30 * time.Second},
+		UseJSONNumber:         true,
+	}
+
+	for _, path := range []string{"testdata/config_valid.yaml", "testdata/config_valid.json"} {
+		t.Run(path, func(t *testing.T) {
+			config, err := LoadConfig(path)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(config, want) {
+				t.Errorf("LoadConfig() = %+v, want %+v", config, want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_EnvExpansion(t *testing.T) {
+	t.Setenv("OPENSEARCH_TEST_HOST", "search.internal")
+	t.Setenv("OPENSEARCH_TEST_USER", "svc-reader")
+	t.Setenv("OPENSEARCH_TEST_PASSWORD", "p@ss$word")
+
+	config, err := LoadConfig("testdata/config_env.yaml")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	want := Config{
+		Addresses:          []string{"https://search.internal:9200"},
+		Username:           "svc-reader",
+		Password:           "p@ss$word",
+		InsecureSkipVerify: true,
+		Headers:            map[string]string{"X-Tenant": "svc-reader"},
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("LoadConfig() = %+v, want %+v", config, want)
+	}
+}
+
+func TestLoadConfig_AWS(t *testing.T) {
+	config, err := LoadConfig("testdata/config_aws.yaml")
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	// Without access_key_id the credentials are left to the default chain
+	want := &AWSConfig{Region: "eu-west-1", Service: "es"}
+	if !reflect.DeepEqual(config.AWS, want) {
+		t.Errorf("LoadConfig() AWS = %+v, want %+v", config.AWS, want)
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "Unknown YAML key", path: "testdata/config_unknown_key.yaml", wantErr: "request_timout"},
+		{name: "Unknown JSON key", path: "testdata/config_unknown_key.json", wantErr: "max_retry"},
+		{name: "Duration without unit", path: "testdata/config_bad_duration.yaml", wantErr: "duration"},
+		{name: "Malformed JSON", path: "testdata/config_invalid.json", wantErr: "failed to parse config file"},
+		{name: "Unset environment variable", path: "testdata/config_env.yaml", wantErr: "environment variable OPENSEARCH_TEST_HOST is not set"},
+		{name: "Unsupported extension", path: "testdata/config.toml", wantErr: "unsupported config file extension"},
+		{name: "Missing file", path: "testdata/missing.yaml", wantErr: "failed to read config file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfig() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClientFromFile(t *testing.T) {
+	client, err := NewClientFromFile("testdata/config_valid.yaml")
+	if err == nil || client != nil {
+		// The CA file in the fixture does not exist, so the client cannot be built
+		t.Fatalf("NewClientFromFile() = %v, %v, want the CA file error", client, err)
+	}
+
+	t.Setenv("OPENSEARCH_TEST_HOST", "localhost")
+	t.Setenv("OPENSEARCH_TEST_USER", "admin")
+	t.Setenv("OPENSEARCH_TEST_PASSWORD", "admin")
+	if _, err := NewClientFromFile("testdata/config_env.yaml"); err != nil {
+		t.Errorf("NewClientFromFile() error = %v", err)
+	}
+}
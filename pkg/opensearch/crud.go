@@ -4,86 +4,295 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
 )
 
 // CreateDocument indexes a new document or updates an existing one
-func (c *Client) CreateDocument(ctx context.Context, index, id string, document interface{}) error {
-	body, err := json.Marshal(document)
-	if err != nil {
-		return fmt.Errorf("failed to marshal document: %w", err)
+func (c *Client) CreateDocument(ctx context.Context, index, id string, document interface{}, opts ...DocumentOption) (err error) {
+	defer c.observe("CreateDocument", time.Now(), &err)
+
+	return c.createDocument(ctx, index, id, document, opts...)
+}
+
+// createDocument indexes a document under id with the given options
+func (c *Client) createDocument(ctx context.Context, index, id string, document interface{}, opts ...DocumentOption) error {
+	options := applyDocumentOptions(opts)
+	req := opensearchapi.IndexRequest{
+		Index:      index,
+		DocumentID: id,
+		Refresh:    "true",
+		Routing:    options.routing,
 	}
 
+	return c.indexDocument(ctx, req, document)
+}
+
+// CreateDocumentStrict indexes a new document and fails with ErrVersionConflict
+// if a document with the same ID already exists
+func (c *Client) CreateDocumentStrict(ctx context.Context, index, id string, document interface{}, opts ...DocumentOption) (err error) {
+	defer c.observe("CreateDocumentStrict", time.Now(), &err)
+
+	options := applyDocumentOptions(opts)
 	req := opensearchapi.IndexRequest{
 		Index:      index,
 		DocumentID: id,
-		Body:       bytes.NewReader(body),
+		OpType:     "create",
 		Refresh:    "true",
+		Routing:    options.routing,
+	}
+
+	return c.indexDocument(ctx, req, document)
+}
+
+// CreateDocumentVersioned indexes a document with a caller-managed version. With
+// versionType "external" or "external_gte", a write carrying an older version than
+// the stored document is rejected with ErrVersionConflict.
+func (c *Client) CreateDocumentVersioned(ctx context.Context, index, id string, document interface{}, version int64, versionType string, opts ...DocumentOption) (err error) {
+	defer c.observe("CreateDocumentVersioned", time.Now(), &err)
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	// opensearchapi.IndexRequest takes the version as an int, so the request is built here
+	// to send it as given on every platform
+	options := applyDocumentOptions(opts)
+	params := url.Values{}
+	params.Set("refresh", "true")
+	params.Set("version", strconv.FormatInt(version, 10))
+	if versionType != "" {
+		params.Set("version_type", versionType)
+	}
+	if options.routing != "" {
+		params.Set("routing", options.routing)
 	}
 
+	path := "/" + url.PathEscape(index) + "/_doc/" + url.PathEscape(id)
+	res, err := c.perform(ctx, http.MethodPut, path, params, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to index document: %w", err)
+	}
+	defer res.Body.Close()
+
+	return indexResponseError(res, id)
+}
+
+// indexDocument marshals the document into the request body and executes the index request
+func (c *Client) indexDocument(ctx context.Context, req opensearchapi.IndexRequest, document interface{}) error {
+	body, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document: %w", err)
+	}
+	req.Body = bytes.NewReader(body)
+
 	res, err := req.Do(ctx, c.client)
 	if err != nil {
 		return fmt.Errorf("failed to index document: %w", err)
 	}
 	defer res.Body.Close()
 
+	return indexResponseError(res, req.DocumentID)
+}
+
+// indexResponseError returns the error of a failed index request for document id, or nil
+func indexResponseError(res *opensearchapi.Response, id string) error {
 	if res.IsError() {
-		return fmt.Errorf("index request failed with status: %s", res.Status())
+		if res.StatusCode == 409 {
+			return responseError(res, fmt.Sprintf("index of document %s rejected: %v", id, ErrVersionConflict), ErrVersionConflict)
+		}
+		return requestError("index", res)
 	}
 
 	return nil
 }
 
-// GetDocument retrieves a document by its ID
-func (c *Client) GetDocument(ctx context.Context, index, id string) (map[string]interface{}, error) {
-	req := opensearchapi.GetRequest{
+// GetDocument retrieves a document by its ID. It fails with ErrDocumentNotFound when the
+// document does not exist and ErrIndexNotFound when the index does not.
+func (c *Client) GetDocument(ctx context.Context, index, id string, opts ...DocumentOption) (_ map[string]interface{}, err error) {
+	defer c.observe("GetDocument", time.Now(), &err)
+
+	response, err := c.getDocument(ctx, index, id, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Source, nil
+}
+
+// GetDocumentWithMeta retrieves a document by its ID along with the metadata
+// needed for optimistic concurrency control
+func (c *Client) GetDocumentWithMeta(ctx context.Context, index, id string, opts ...DocumentOption) (_ map[string]interface{}, _ DocumentMeta, err error) {
+	defer c.observe("GetDocumentWithMeta", time.Now(), &err)
+
+	response, err := c.getDocument(ctx, index, id, opts)
+	if err != nil {
+		return nil, DocumentMeta{}, err
+	}
+
+	meta := DocumentMeta{
+		Version:     response.Version,
+		SeqNo:       response.SeqNo,
+		PrimaryTerm: response.PrimaryTerm,
+	}
+
+	return response.Source, meta, nil
+}
+
+// GetDocumentRaw retrieves a document by its ID and returns the full parsed response,
+// including _version, _seq_no, _primary_term and found
+func (c *Client) GetDocumentRaw(ctx context.Context, index, id string, opts ...DocumentOption) (_ *GetResponse, err error) {
+	defer c.observe("GetDocumentRaw", time.Now(), &err)
+
+	return c.getDocument(ctx, index, id, opts)
+}
+
+// GetDocumentAs retrieves a document by its ID and decodes its source into out, which
+// must be a pointer such as *Book. It fails like GetDocument when the document or index
+// does not exist.
+func (c *Client) GetDocumentAs(ctx context.Context, index, id string, out interface{}, opts ...DocumentOption) (err error) {
+	defer c.observe("GetDocumentAs", time.Now(), &err)
+
+	var response struct {
+		Source json.RawMessage `json:"_source"`
+	}
+	if err := c.getDocumentInto(ctx, index, id, opts, &response); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(response.Source, out); err != nil {
+		return fmt.Errorf("failed to decode document %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// GetSourceRaw retrieves the source of a document as the raw JSON bytes stored in the
+// index, so it can be forwarded without the key reordering and loss of number precision of
+// decoding it into a map. It fails like GetDocument when the document or index does not
+// exist.
+func (c *Client) GetSourceRaw(ctx context.Context, index, id string, opts ...DocumentOption) (_ []byte, err error) {
+	defer c.observe("GetSourceRaw", time.Now(), &err)
+
+	options := applyDocumentOptions(opts)
+	req := opensearchapi.GetSourceRequest{
 		Index:      index,
 		DocumentID: id,
+		Routing:    options.routing,
 	}
 
 	res, err := req.Do(ctx, c.client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get document: %w", err)
+		return nil, fmt.Errorf("failed to get document source: %w", err)
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			return nil, fmt.Errorf("document not found")
+			return nil, notFoundError(res, index, id)
 		}
-		return nil, fmt.Errorf("get request failed with status: %s", res.Status())
+		return nil, requestError("get source", res)
+	}
+
+	source, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document source: %w", err)
 	}
 
+	return source, nil
+}
+
+// getDocument performs a GET request and returns the full parsed response
+func (c *Client) getDocument(ctx context.Context, index, id string, opts []DocumentOption) (*GetResponse, error) {
 	var response GetResponse
-	if err := parseResponse(res.Body, &response); err != nil {
+	if err := c.getDocumentInto(ctx, index, id, opts, &response); err != nil {
 		return nil, err
 	}
 
-	return response.Source, nil
+	return &response, nil
 }
 
-// UpdateDocument updates an existing document with partial updates
-func (c *Client) UpdateDocument(ctx context.Context, index, id string, updates interface{}) error {
-	updateDoc := map[string]interface{}{
-		"doc": updates,
+// getDocumentInto performs a GET request and parses the response into v
+func (c *Client) getDocumentInto(ctx context.Context, index, id string, opts []DocumentOption, v interface{}) error {
+	options := applyDocumentOptions(opts)
+	req := opensearchapi.GetRequest{
+		Index:      index,
+		DocumentID: id,
+		Routing:    options.routing,
 	}
 
-	body, err := json.Marshal(updateDoc)
+	res, err := req.Do(ctx, c.client)
 	if err != nil {
-		return fmt.Errorf("failed to marshal updates: %w", err)
+		return fmt.Errorf("failed to get document: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return notFoundError(res, index, id)
+		}
+		return requestError("get", res)
 	}
 
+	return c.parseResponse(res.Body, v)
+}
+
+// UpdateDocument updates an existing document with partial updates, failing with
+// ErrDocumentNotFound when it does not exist
+func (c *Client) UpdateDocument(ctx context.Context, index, id string, updates interface{}, opts ...DocumentOption) (err error) {
+	defer c.observe("UpdateDocument", time.Now(), &err)
+
+	options := applyDocumentOptions(opts)
 	req := opensearchapi.UpdateRequest{
 		Index:      index,
 		DocumentID: id,
-		Body:       bytes.NewReader(body),
 		Refresh:    "true",
+		Routing:    options.routing,
 	}
 
+	return c.updateDocument(ctx, req, updates)
+}
+
+// UpdateDocumentIfMatch updates a document only if its sequence number and primary
+// term still match the given values. A mismatch returns ErrVersionConflict.
+func (c *Client) UpdateDocumentIfMatch(ctx context.Context, index, id string, updates interface{}, seqNo, primaryTerm int, opts ...DocumentOption) (err error) {
+	defer c.observe("UpdateDocumentIfMatch", time.Now(), &err)
+
+	options := applyDocumentOptions(opts)
+	req := opensearchapi.UpdateRequest{
+		Index:         index,
+		DocumentID:    id,
+		Refresh:       "true",
+		Routing:       options.routing,
+		IfSeqNo:       &seqNo,
+		IfPrimaryTerm: &primaryTerm,
+	}
+
+	return c.updateDocument(ctx, req, updates)
+}
+
+// updateDocument wraps the partial updates in a "doc" body and executes the update request
+func (c *Client) updateDocument(ctx context.Context, req opensearchapi.UpdateRequest, updates interface{}) error {
+	updateDoc := map[string]interface{}{
+		"doc": updates,
+	}
+
+	body, err := json.Marshal(updateDoc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal updates: %w", err)
+	}
+	req.Body = bytes.NewReader(body)
+
 	res, err := req.Do(ctx, c.client)
 	if err != nil {
 		return fmt.Errorf("failed to update document: %w", err)
@@ -91,21 +300,29 @@ func (c *Client) UpdateDocument(ctx context.Context, index, id string, updates i
 	defer res.Body.Close()
 
 	if res.IsError() {
-		if res.StatusCode == 404 {
-			return fmt.Errorf("document not found")
+		switch res.StatusCode {
+		case 404:
+			return notFoundError(res, req.Index, req.DocumentID)
+		case 409:
+			return responseError(res, fmt.Sprintf("update of document %s rejected: %v", req.DocumentID, ErrVersionConflict), ErrVersionConflict)
 		}
-		return fmt.Errorf("update request failed with status: %s", res.Status())
+		return requestError("update", res)
 	}
 
 	return nil
 }
 
-// DeleteDocument deletes a document by its ID
-func (c *Client) DeleteDocument(ctx context.Context, index, id string) error {
+// DeleteDocument deletes a document by its ID, failing with ErrDocumentNotFound when it
+// does not exist
+func (c *Client) DeleteDocument(ctx context.Context, index, id string, opts ...DocumentOption) (err error) {
+	defer c.observe("DeleteDocument", time.Now(), &err)
+
+	options := applyDocumentOptions(opts)
 	req := opensearchapi.DeleteRequest{
 		Index:      index,
 		DocumentID: id,
 		Refresh:    "true",
+		Routing:    options.routing,
 	}
 
 	res, err := req.Do(ctx, c.client)
@@ -116,64 +333,443 @@ func (c *Client) DeleteDocument(ctx context.Context, index, id string) error {
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			return fmt.Errorf("document not found")
+			return notFoundError(res, index, id)
 		}
-		return fmt.Errorf("delete request failed with status: %s", res.Status())
+		return requestError("delete", res)
 	}
 
 	return nil
 }
 
-// SearchDocuments performs a search query on an index
-func (c *Client) SearchDocuments(ctx context.Context, index string, query map[string]interface{}) ([]map[string]interface{}, error) {
+// DeleteByQuery deletes every document in the index matching the query body
+// (e.g. MatchQuery(...)) and returns how many documents were deleted
+func (c *Client) DeleteByQuery(ctx context.Context, index string, query map[string]interface{}) (_ int64, err error) {
+	defer c.observe("DeleteByQuery", time.Now(), &err)
+
+	res, err := c.deleteByQuery(ctx, index, query, true)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	var response struct {
+		Deleted  int64                    `json:"deleted"`
+		Failures []map[string]interface{} `json:"failures"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return 0, err
+	}
+
+	if len(response.Failures) > 0 {
+		return response.Deleted, fmt.Errorf("delete by query on %s: %d failures, first: %v", index, len(response.Failures), response.Failures[0])
+	}
+
+	return response.Deleted, nil
+}
+
+// DeleteByQueryAsync starts deleting the documents matching the query body without
+// waiting and returns the ID of the task running it. Follow it with GetTask.
+func (c *Client) DeleteByQueryAsync(ctx context.Context, index string, query map[string]interface{}) (_ string, err error) {
+	defer c.observe("DeleteByQueryAsync", time.Now(), &err)
+
+	res, err := c.deleteByQuery(ctx, index, query, false)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var response TaskResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return "", err
+	}
+
+	return response.Task, nil
+}
+
+// deleteByQuery executes a delete by query request. The caller closes the response body.
+func (c *Client) deleteByQuery(ctx context.Context, index string, query map[string]interface{}, waitForCompletion bool) (*opensearchapi.Response, error) {
 	body, err := json.Marshal(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	req := opensearchapi.SearchRequest{
-		Index: []string{index},
-		Body:  bytes.NewReader(body),
+	refresh := true
+	req := opensearchapi.DeleteByQueryRequest{
+		Index:             []string{index},
+		Body:              bytes.NewReader(body),
+		Refresh:           &refresh,
+		WaitForCompletion: &waitForCompletion,
 	}
 
 	res, err := req.Do(ctx, c.client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search documents: %w", err)
+		return nil, fmt.Errorf("failed to delete by query: %w", err)
 	}
-	defer res.Body.Close()
 
 	if res.IsError() {
-		return nil, fmt.Errorf("search request failed with status: %s", res.Status())
+		defer res.Body.Close()
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return nil, requestError("delete by query", res)
 	}
 
+	return res, nil
+}
+
+// SearchDocuments performs a search query on an index
+func (c *Client) SearchDocuments(ctx context.Context, index string, query map[string]interface{}) (_ []map[string]interface{}, err error) {
+	defer c.observe("SearchDocuments", time.Now(), &err)
+
+	return c.searchDocuments(ctx, index, query)
+}
+
+// searchDocuments runs a search and returns the hit sources annotated with _id and _score
+func (c *Client) searchDocuments(ctx context.Context, index string, query map[string]interface{}) ([]map[string]interface{}, error) {
 	var response SearchResponse
-	if err := parseResponse(res.Body, &response); err != nil {
+	if err := c.search(ctx, index, query, &response); err != nil {
 		return nil, err
 	}
 
-	results := make([]map[string]interface{}, 0, len(response.Hits.Hits))
-	for _, hit := range response.Hits.Hits {
+	return hitsToDocuments(response.Hits.Hits), nil
+}
+
+// SearchDocumentsAs performs a search query and decodes the source of each hit into a new
+// element of the slice out points to, e.g. a *[]Book or *[]*Book, replacing its contents
+func (c *Client) SearchDocumentsAs(ctx context.Context, index string, query map[string]interface{}, out interface{}) (err error) {
+	defer c.observe("SearchDocumentsAs", time.Now(), &err)
+
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.IsNil() || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("out must be a non-nil pointer to a slice, got %T", out)
+	}
+	slice = slice.Elem()
+
+	var response RawSearchResponse
+	if err := c.search(ctx, index, query, &response); err != nil {
+		return err
+	}
+
+	hits := response.Hits.Hits
+	docs := reflect.MakeSlice(slice.Type(), len(hits), len(hits))
+	for i, hit := range hits {
+		doc := reflect.New(slice.Type().Elem())
+		if err := json.Unmarshal(hit.Source, doc.Interface()); err != nil {
+			return fmt.Errorf("failed to decode document %s: %w", hit.ID, err)
+		}
+		docs.Index(i).Set(doc.Elem())
+	}
+	slice.Set(docs)
+
+	return nil
+}
+
+// hitsToDocuments flattens hits into their sources annotated with _id and _score
+func hitsToDocuments(hits []Hit) []map[string]interface{} {
+	results := make([]map[string]interface{}, 0, len(hits))
+	for _, hit := range hits {
 		doc := hit.Source
+		if doc == nil {
+			doc = make(map[string]interface{})
+		}
 		doc["_id"] = hit.ID
 		doc["_score"] = hit.Score
 		results = append(results, doc)
 	}
+	return results
+}
+
+// SearchRawHits performs a search query and returns hits with their _source left as raw JSON,
+// so callers can decode only the hits they need into their own types
+func (c *Client) SearchRawHits(ctx context.Context, index string, query map[string]interface{}) (_ []RawHit, err error) {
+	defer c.observe("SearchRawHits", time.Now(), &err)
+
+	var response RawSearchResponse
+	if err := c.search(ctx, index, query, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Hits.Hits, nil
+}
+
+// SearchHits performs a search query and returns the hits with their metadata, including
+// the matched_queries of clauses named with NamedQuery
+func (c *Client) SearchHits(ctx context.Context, index string, query map[string]interface{}) (_ []Hit, err error) {
+	defer c.observe("SearchHits", time.Now(), &err)
+
+	var response SearchResponse
+	if err := c.search(ctx, index, query, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Hits.Hits, nil
+}
+
+// Search performs a search query and returns the hits along with the total number of
+// matches, the highest score and how long the search took
+func (c *Client) Search(ctx context.Context, index string, query map[string]interface{}) (_ *SearchResult, err error) {
+	defer c.observe("Search", time.Now(), &err)
+
+	var response SearchResponse
+	if err := c.search(ctx, index, query, &response); err != nil {
+		return nil, err
+	}
+
+	return &SearchResult{
+		Hits:          response.Hits.Hits,
+		Total:         response.Hits.Total.Value,
+		TotalRelation: response.Hits.Total.Relation,
+		MaxScore:      response.Hits.MaxScore,
+		TookMs:        response.Took,
+	}, nil
+}
+
+// SearchWithProfile performs a search query with profiling enabled and returns the matching
+// documents along with the per-shard timings, for query performance tuning. The query
+// passed in is left unchanged.
+func (c *Client) SearchWithProfile(ctx context.Context, index string, query map[string]interface{}) (_ []map[string]interface{}, _ *SearchProfile, err error) {
+	defer c.observe("SearchWithProfile", time.Now(), &err)
+
+	body := make(map[string]interface{}, len(query)+1)
+	for k, v := range query {
+		body[k] = v
+	}
+
+	var response SearchResponse
+	if err := c.search(ctx, index, WithProfile(body), &response); err != nil {
+		return nil, nil, err
+	}
+	if response.Profile == nil {
+		return nil, nil, fmt.Errorf("search response has no profile section")
+	}
+
+	return hitsToDocuments(response.Hits.Hits), response.Profile, nil
+}
+
+// MultiSearch runs several searches in a single msearch request and returns the matching
+// documents of each search, in the order of searches. If any search fails, the error is an
+// *OpenSearchError naming its position in searches, so a missing index matches
+// ErrIndexNotFound.
+func (c *Client) MultiSearch(ctx context.Context, searches []SearchSpec) (_ [][]map[string]interface{}, err error) {
+	defer c.observe("MultiSearch", time.Now(), &err)
+
+	if len(searches) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	for i, search := range searches {
+		if search.Index == "" {
+			return nil, fmt.Errorf("search %d has no index", i)
+		}
+		header, err := json.Marshal(map[string]interface{}{"index": search.Index})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal search header: %w", err)
+		}
+		query := search.Query
+		if query == nil {
+			query = MatchAllQuery()
+		}
+		body, err := json.Marshal(query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal query: %w", err)
+		}
+
+		buf.Write(header)
+		buf.WriteByte('\n')
+		buf.Write(body)
+		buf.WriteByte('\n')
+	}
+
+	req := opensearchapi.MsearchRequest{
+		Body: &buf,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform multi search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("multi search", res)
+	}
+
+	var response MultiSearchResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Responses) != len(searches) {
+		return nil, fmt.Errorf("multi search returned %d responses for %d searches", len(response.Responses), len(searches))
+	}
+
+	results := make([][]map[string]interface{}, len(searches))
+	for i, r := range response.Responses {
+		if r.Error != nil {
+			osErr := newOpenSearchError(r.Status, r.Error.Type, r.Error.Reason, r.Error.Index, r.Error.RootCause)
+			osErr.message = fmt.Sprintf("search %d on %s failed with status %d: %s: %s", i, searches[i].Index, r.Status, r.Error.Type, r.Error.Reason)
+			return nil, osErr
+		}
+		results[i] = hitsToDocuments(r.Hits.Hits)
+	}
 
 	return results, nil
 }
 
+// search executes a search request and parses the response into v
+func (c *Client) search(ctx context.Context, index string, query map[string]interface{}, v interface{}) error {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	req := opensearchapi.SearchRequest{
+		Body: bytes.NewReader(body),
+	}
+	// Point in time searches name the index in the PIT instead of the path
+	if index != "" {
+		req.Index = []string{index}
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to search documents: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return requestError("search", res)
+	}
+
+	return c.parseResponse(res.Body, v)
+}
+
+// ExplainDocument explains how a specific document scores against a query,
+// returning the parsed explanation tree including the "matched" flag
+func (c *Client) ExplainDocument(ctx context.Context, index, id string, query map[string]interface{}) (_ map[string]interface{}, err error) {
+	defer c.observe("ExplainDocument", time.Now(), &err)
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	req := opensearchapi.ExplainRequest{
+		Index:      index,
+		DocumentID: id,
+		Body:       bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain document: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, notFoundError(res, index, id)
+		}
+		return nil, requestError("explain", res)
+	}
+
+	var response map[string]interface{}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ValidateQuery checks whether a query is valid without executing it. The query can be
+// given on its own or as a full search body, in which case only its "query" part is
+// validated. The explanations list, per index, the rewritten query when it is valid or
+// the error message when it is not.
+func (c *Client) ValidateQuery(ctx context.Context, index string, query map[string]interface{}) (_ bool, _ []string, err error) {
+	defer c.observe("ValidateQuery", time.Now(), &err)
+
+	if inner, ok := query["query"].(map[string]interface{}); ok {
+		query = inner
+	}
+	body, err := json.Marshal(map[string]interface{}{"query": query})
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	explain := true
+	req := opensearchapi.IndicesValidateQueryRequest{
+		Index:   []string{index},
+		Body:    bytes.NewReader(body),
+		Explain: &explain,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to validate query: %w", err)
+	}
+	defer res.Body.Close()
+
+	// Queries that fail to parse are rejected outright with the reason in the error body
+	if res.StatusCode == 400 {
+		var response ErrorResponse
+		if err := c.parseResponse(res.Body, &response); err != nil {
+			return false, nil, err
+		}
+		return false, []string{response.Error.Reason}, nil
+	}
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return false, nil, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return false, nil, requestError("validate query", res)
+	}
+
+	var response ValidateQueryResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return false, nil, err
+	}
+
+	explanations := make([]string, 0, len(response.Explanations))
+	for _, e := range response.Explanations {
+		text := e.Explanation
+		if !e.Valid {
+			text = e.Error
+		}
+		if e.Index != "" {
+			text = e.Index + ": " + text
+		}
+		explanations = append(explanations, text)
+	}
+	if len(explanations) == 0 && response.Error != "" {
+		explanations = append(explanations, response.Error)
+	}
+
+	return response.Valid, explanations, nil
+}
+
 // SearchAll retrieves all documents from an index using match_all query
-func (c *Client) SearchAll(ctx context.Context, index string) ([]map[string]interface{}, error) {
+func (c *Client) SearchAll(ctx context.Context, index string) (_ []map[string]interface{}, err error) {
+	defer c.observe("SearchAll", time.Now(), &err)
+
 	query := map[string]interface{}{
 		"query": map[string]interface{}{
 			"match_all": map[string]interface{}{},
 		},
 	}
-	return c.SearchDocuments(ctx, index, query)
+	return c.searchDocuments(ctx, index, query)
 }
 
-// CreateIndex creates a new index with optional settings and mappings
-func (c *Client) CreateIndex(ctx context.Context, index string, body map[string]interface{}) error {
+// CreateIndex creates a new index with optional settings and mappings, failing with
+// ErrIndexAlreadyExists when the index exists
+func (c *Client) CreateIndex(ctx context.Context, index string, body map[string]interface{}) (err error) {
+	defer c.observe("CreateIndex", time.Now(), &err)
+
+	return c.createIndex(ctx, index, body)
+}
+
+// createIndex creates an index with the given settings and mappings
+func (c *Client) createIndex(ctx context.Context, index string, body map[string]interface{}) error {
 	var bodyReader io.Reader
 	if body != nil {
 		bodyBytes, err := json.Marshal(body)
@@ -195,14 +791,45 @@ func (c *Client) CreateIndex(ctx context.Context, index string, body map[string]
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return fmt.Errorf("create index request failed with status: %s", res.Status())
+		return requestError("create index", res)
 	}
 
 	return nil
 }
 
-// DeleteIndex deletes an index
-func (c *Client) DeleteIndex(ctx context.Context, index string) error {
+// EnsureIndex creates the index with the given settings and mappings unless it already
+// exists, and reports whether it was created. An index created concurrently by another
+// caller counts as existing.
+func (c *Client) EnsureIndex(ctx context.Context, index string, body map[string]interface{}) (_ bool, err error) {
+	defer c.observe("EnsureIndex", time.Now(), &err)
+
+	exists, err := c.indexExists(ctx, index)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	if err := c.createIndex(ctx, index, body); err != nil {
+		if errors.Is(err, ErrIndexAlreadyExists) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteIndex deletes an index, failing with ErrIndexNotFound when it does not exist
+func (c *Client) DeleteIndex(ctx context.Context, index string) (err error) {
+	defer c.observe("DeleteIndex", time.Now(), &err)
+
+	return c.deleteIndex(ctx, index)
+}
+
+// deleteIndex deletes a single index
+func (c *Client) deleteIndex(ctx context.Context, index string) error {
 	req := opensearchapi.IndicesDeleteRequest{
 		Index: []string{index},
 	}
@@ -215,16 +842,38 @@ func (c *Client) DeleteIndex(ctx context.Context, index string) error {
 
 	if res.IsError() {
 		if res.StatusCode == 404 {
-			return fmt.Errorf("index not found")
+			return responseError(res, fmt.Sprintf("index %s not found", index), ErrIndexNotFound)
 		}
-		return fmt.Errorf("delete index request failed with status: %s", res.Status())
+		return requestError("delete index", res)
 	}
 
 	return nil
 }
 
+// DeleteIndexIfExists deletes an index and reports whether it existed, so that deleting a
+// missing index is not an error
+func (c *Client) DeleteIndexIfExists(ctx context.Context, index string) (_ bool, err error) {
+	defer c.observe("DeleteIndexIfExists", time.Now(), &err)
+
+	if err := c.deleteIndex(ctx, index); err != nil {
+		if errors.Is(err, ErrIndexNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
 // IndexExists checks if an index exists
-func (c *Client) IndexExists(ctx context.Context, index string) (bool, error) {
+func (c *Client) IndexExists(ctx context.Context, index string) (_ bool, err error) {
+	defer c.observe("IndexExists", time.Now(), &err)
+
+	return c.indexExists(ctx, index)
+}
+
+// indexExists reports whether an index exists
+func (c *Client) indexExists(ctx context.Context, index string) (bool, error) {
 	req := opensearchapi.IndicesExistsRequest{
 		Index: []string{index},
 	}
@@ -240,50 +889,94 @@ func (c *Client) IndexExists(ctx context.Context, index string) (bool, error) {
 	}
 
 	if res.IsError() {
-		return false, fmt.Errorf("index exists request failed with status: %s", res.Status())
+		return false, requestError("index exists", res)
 	}
 
 	return true, nil
 }
 
-// BulkCreate performs bulk indexing of multiple documents
-func (c *Client) BulkCreate(ctx context.Context, index string, documents []map[string]interface{}) error {
+// IndicesExist checks several indices in a single request and reports, per name,
+// whether it exists. Like IndexExists, a name that is an alias counts as existing.
+func (c *Client) IndicesExist(ctx context.Context, indices []string) (_ map[string]bool, err error) {
+	defer c.observe("IndicesExist", time.Now(), &err)
+
+	exists := make(map[string]bool, len(indices))
+	if len(indices) == 0 {
+		return exists, nil
+	}
+
+	ignoreUnavailable := true
+	req := opensearchapi.IndicesGetRequest{
+		Index:             indices,
+		IgnoreUnavailable: &ignoreUnavailable,
+		FilterPath:        []string{"*.settings.index.provided_name", "*.aliases"},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check index existence: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("get indices", res)
+	}
+
+	var response map[string]struct {
+		Aliases map[string]interface{} `json:"aliases"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	for index, entry := range response {
+		found[index] = true
+		for alias := range entry.Aliases {
+			found[alias] = true
+		}
+	}
+	for _, index := range indices {
+		exists[index] = found[index]
+	}
+
+	return exists, nil
+}
+
+// BulkCreate performs bulk indexing of multiple documents, refreshing the index so they
+// are searchable when it returns
+func (c *Client) BulkCreate(ctx context.Context, index string, documents []map[string]interface{}) (err error) {
+	defer c.observe("BulkCreate", time.Now(), &err)
+
+	return c.bulkCreate(ctx, index, documents, "true")
+}
+
+// BulkCreateWithRefresh performs bulk indexing of multiple documents with the given refresh
+// parameter: "true" refreshes the affected shards, "wait_for" waits for the next scheduled
+// refresh and "false" does not refresh. For large loads, index with "false" and call
+// RefreshIndex once at the end.
+func (c *Client) BulkCreateWithRefresh(ctx context.Context, index string, documents []map[string]interface{}, refresh string) (err error) {
+	defer c.observe("BulkCreateWithRefresh", time.Now(), &err)
+
+	return c.bulkCreate(ctx, index, documents, refresh)
+}
+
+// bulkCreate indexes documents in a single bulk request with the given refresh parameter
+func (c *Client) bulkCreate(ctx context.Context, index string, documents []map[string]interface{}, refresh string) error {
 	if len(documents) == 0 {
 		return nil
 	}
 
 	var buf bytes.Buffer
 	for _, doc := range documents {
-		// Action line
-		action := map[string]interface{}{
-			"index": map[string]interface{}{
-				"_index": index,
-			},
-		}
-		if id, ok := doc["_id"]; ok {
-			action["index"].(map[string]interface{})["_id"] = id
-			delete(doc, "_id")
-		}
-
-		actionBytes, err := json.Marshal(action)
-		if err != nil {
-			return fmt.Errorf("failed to marshal bulk action: %w", err)
-		}
-		buf.Write(actionBytes)
-		buf.WriteByte('\n')
-
-		// Document line
-		docBytes, err := json.Marshal(doc)
-		if err != nil {
-			return fmt.Errorf("failed to marshal document: %w", err)
+		if err := writeBulkIndexAction(&buf, index, doc); err != nil {
+			return err
 		}
-		buf.Write(docBytes)
-		buf.WriteByte('\n')
 	}
 
 	req := opensearchapi.BulkRequest{
 		Body:    &buf,
-		Refresh: "true",
+		Refresh: refresh,
 	}
 
 	res, err := req.Do(ctx, c.client)
@@ -293,11 +986,11 @@ func (c *Client) BulkCreate(ctx context.Context, index string, documents []map[s
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return fmt.Errorf("bulk request failed with status: %s", res.Status())
+		return requestError("bulk", res)
 	}
 
 	var response BulkResponse
-	if err := parseResponse(res.Body, &response); err != nil {
+	if err := c.parseResponse(res.Body, &response); err != nil {
 		return err
 	}
 
@@ -316,4 +1009,64 @@ func (c *Client) BulkCreate(ctx context.Context, index string, documents []map[s
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// writeBulkIndexAction appends the action and document lines that index doc to buf.
+// A "_id" key in doc is used as the document ID and removed from the document.
+func writeBulkIndexAction(buf *bytes.Buffer, index string, doc map[string]interface{}) error {
+	// Action line
+	action := map[string]interface{}{
+		"index": map[string]interface{}{
+			"_index": index,
+		},
+	}
+	if id, ok := doc["_id"]; ok {
+		action["index"].(map[string]interface{})["_id"] = id
+		delete(doc, "_id")
+	}
+
+	actionBytes, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bulk action: %w", err)
+	}
+
+	// Document line
+	docBytes, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	buf.Write(actionBytes)
+	buf.WriteByte('\n')
+	buf.Write(docBytes)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// BulkCreateChunked indexes documents in bulk requests of at most chunkSize documents.
+// The context is checked before each chunk, so a cancelled or expired context stops the
+// run between chunks. It returns the number of chunks that were fully indexed.
+func (c *Client) BulkCreateChunked(ctx context.Context, index string, documents []map[string]interface{}, chunkSize int) (_ int, err error) {
+	defer c.observe("BulkCreateChunked", time.Now(), &err)
+
+	if chunkSize <= 0 {
+		return 0, fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+
+	chunks := (len(documents) + chunkSize - 1) / chunkSize
+	for i := 0; i < chunks; i++ {
+		if err := ctx.Err(); err != nil {
+			return i, fmt.Errorf("bulk aborted after %d of %d chunks: %w", i, chunks, err)
+		}
+
+		end := (i + 1) * chunkSize
+		if end > len(documents) {
+			end = len(documents)
+		}
+		if err := c.bulkCreate(ctx, index, documents[i*chunkSize:end], "true"); err != nil {
+			return i, fmt.Errorf("bulk chunk %d of %d: %w", i+1, chunks, err)
+		}
+	}
+
+	return chunks, nil
+}
@@ -1,28 +1,71 @@
 package opensearch
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
-	"time"
+
+	"github.com/yenonn/go-opensearch/pkg/opensearch/internal/fakeos"
 )
 
-// TestClient is a helper to create a client for integration tests
-func setupTestClient(t *testing.T) *Client {
+// integration enables the tests that need a live cluster, like OPENSEARCH_INTEGRATION=1
+var integration = flag.Bool("integration", false, "run the tests that need the OpenSearch cluster at OPENSEARCH_URL")
+
+// integrationURL returns the address of the cluster for integration tests, skipping the
+// test unless they are enabled with -integration or OPENSEARCH_INTEGRATION=1
+func integrationURL(t *testing.T) string {
 	t.Helper()
 
+	if !*integration && os.Getenv("OPENSEARCH_INTEGRATION") == "" {
+		t.Skip("integration test: set OPENSEARCH_INTEGRATION=1 or pass -integration to run against a cluster")
+	}
+
 	url := os.Getenv("OPENSEARCH_URL")
 	if url == "" {
 		url = "http://localhost:9200"
 	}
+	return url
+}
+
+// newFakeClient returns a client for a fakeos server, which answers the document, search,
+// bulk and index requests of the client without a cluster
+func newFakeClient(t *testing.T) (*Client, *fakeos.Server) {
+	t.Helper()
+
+	server := fakeos.New(t)
+	client, err := NewClient(Config{
+		Addresses:    []string{server.URL},
+		DisableRetry: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	return client, server
+}
+
+// TestClient is a helper to create a client for integration tests
+func setupTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	url := integrationURL(t)
 
 	config := Config{
 		Addresses:          []string{url},
 		Username:           "admin",
 		Password:           "admin",
 		InsecureSkipVerify: true,
+		// Fail fast when no cluster is running instead of backing off between retries
+		DisableRetry: true,
 	}
 
 	client, err := NewClient(config)
@@ -57,13 +100,24 @@ func setupTestIndex(t *testing.T, client *Client, indexName string) func() {
 	}
 
 	// Wait for index to be ready
-	time.Sleep(100 * time.Millisecond)
+	if err := client.waitForIndexHealth(ctx, indexName, "yellow", false); err != nil {
+		t.Fatalf("Test index did not become ready: %v", err)
+	}
 
 	return func() {
 		_ = client.DeleteIndex(ctx, indexName)
 	}
 }
 
+// refreshTestIndex makes all pending writes on the index visible to search
+func refreshTestIndex(t *testing.T, client *Client, indexName string) {
+	t.Helper()
+
+	if err := client.RefreshIndex(context.Background(), indexName); err != nil {
+		t.Fatalf("Failed to refresh test index: %v", err)
+	}
+}
+
 func TestCreateDocument(t *testing.T) {
 	client := setupTestClient(t)
 	indexName := "test-create-doc"
@@ -128,6 +182,92 @@ func TestCreateDocument(t *testing.T) {
 	}
 }
 
+func TestCreateDocumentStrict(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-create-strict"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := client.CreateDocumentStrict(ctx, indexName, "doc-1", map[string]interface{}{"title": "First"})
+	if err != nil {
+		t.Fatalf("CreateDocumentStrict() first call error = %v", err)
+	}
+
+	err = client.CreateDocumentStrict(ctx, indexName, "doc-1", map[string]interface{}{"title": "Second"})
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("CreateDocumentStrict() duplicate ID error = %v, want ErrVersionConflict", err)
+	}
+
+	doc, err := client.GetDocument(ctx, indexName, "doc-1")
+	if err != nil {
+		t.Fatalf("Failed to get document: %v", err)
+	}
+	if doc["title"] != "First" {
+		t.Errorf("Expected original title to be kept, got %v", doc["title"])
+	}
+}
+
+func TestCreateDocumentVersioned(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-create-doc-versioned"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := client.CreateDocumentVersioned(ctx, indexName, "doc-1", map[string]interface{}{
+		"title": "Version 2",
+	}, 2, "external")
+	if err != nil {
+		t.Fatalf("CreateDocumentVersioned() version 2 error = %v", err)
+	}
+
+	err = client.CreateDocumentVersioned(ctx, indexName, "doc-1", map[string]interface{}{
+		"title": "Version 1",
+	}, 1, "external")
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("CreateDocumentVersioned() older version error = %v, want ErrVersionConflict", err)
+	}
+
+	doc, meta, err := client.GetDocumentWithMeta(ctx, indexName, "doc-1")
+	if err != nil {
+		t.Fatalf("Failed to get document: %v", err)
+	}
+	if doc["title"] != "Version 2" || meta.Version != 2 {
+		t.Errorf("Expected title 'Version 2' at version 2, got %v at version %d", doc["title"], meta.Version)
+	}
+}
+
+func TestCreateDocumentVersioned_Request(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sends the version as given", func(t *testing.T) {
+		stub := &stubTransport{status: 201, body: `{"_index":"books","_id":"1","_version":1700000000000,"result":"created"}`}
+		client := newStubClient(t, stub)
+
+		err := client.CreateDocumentVersioned(ctx, "books", "1", map[string]interface{}{"title": "Dune"}, 1700000000000, "external", WithRouting("user-1"))
+		if err != nil {
+			t.Fatalf("CreateDocumentVersioned() error = %v", err)
+		}
+		wantQuery := "refresh=true&routing=user-1&version=1700000000000&version_type=external"
+		if stub.method != http.MethodPut || stub.path != "/books/_doc/1" || stub.query != wantQuery {
+			t.Errorf("request = %s %s?%s, want PUT /books/_doc/1?%s", stub.method, stub.path, stub.query, wantQuery)
+		}
+	})
+
+	t.Run("older version", func(t *testing.T) {
+		stub := &stubTransport{status: 409, body: `{"error":{"type":"version_conflict_engine_exception","reason":"[1]: version conflict, current version [2] is higher or equal to the one provided [1]"},"status":409}`}
+		client := newStubClient(t, stub)
+
+		err := client.CreateDocumentVersioned(ctx, "books", "1", map[string]interface{}{"title": "Dune"}, 1, "external")
+		if !errors.Is(err, ErrVersionConflict) {
+			t.Errorf("CreateDocumentVersioned() error = %v, want ErrVersionConflict", err)
+		}
+	})
+}
+
 func TestGetDocument(t *testing.T) {
 	client := setupTestClient(t)
 	indexName := "test-get-doc"
@@ -151,6 +291,7 @@ func TestGetDocument(t *testing.T) {
 		name      string
 		docID     string
 		wantError bool
+		wantIs    error
 		validate  func(t *testing.T, doc map[string]interface{})
 	}{
 		{
@@ -170,6 +311,7 @@ func TestGetDocument(t *testing.T) {
 			name:      "Get non-existent document",
 			docID:     "non-existent",
 			wantError: true,
+			wantIs:    ErrDocumentNotFound,
 			validate:  nil,
 		},
 	}
@@ -181,6 +323,9 @@ func TestGetDocument(t *testing.T) {
 				t.Errorf("GetDocument() error = %v, wantError %v", err, tt.wantError)
 				return
 			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("GetDocument() error = %v, want %v", err, tt.wantIs)
+			}
 			if !tt.wantError && tt.validate != nil {
 				tt.validate(t, doc)
 			}
@@ -188,6 +333,52 @@ func TestGetDocument(t *testing.T) {
 	}
 }
 
+func TestDocumentRouting(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-doc-routing"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	routing := WithRouting("tenant-42")
+
+	err := client.CreateDocument(ctx, indexName, "doc-1", map[string]interface{}{
+		"title": "Routed Document",
+		"value": 1,
+	}, routing)
+	if err != nil {
+		t.Fatalf("CreateDocument() with routing error = %v", err)
+	}
+
+	doc, err := client.GetDocument(ctx, indexName, "doc-1", routing)
+	if err != nil {
+		t.Fatalf("GetDocument() with routing error = %v", err)
+	}
+	if doc["title"] != "Routed Document" {
+		t.Errorf("Expected title 'Routed Document', got %v", doc["title"])
+	}
+
+	if err := client.UpdateDocument(ctx, indexName, "doc-1", map[string]interface{}{"value": 2}, routing); err != nil {
+		t.Fatalf("UpdateDocument() with routing error = %v", err)
+	}
+
+	doc, err = client.GetDocument(ctx, indexName, "doc-1", routing)
+	if err != nil {
+		t.Fatalf("GetDocument() after update error = %v", err)
+	}
+	if doc["value"] != float64(2) {
+		t.Errorf("Expected value 2, got %v", doc["value"])
+	}
+
+	if err := client.DeleteDocument(ctx, indexName, "doc-1", routing); err != nil {
+		t.Fatalf("DeleteDocument() with routing error = %v", err)
+	}
+
+	if _, err := client.GetDocument(ctx, indexName, "doc-1", routing); err == nil {
+		t.Error("Document should not exist after deletion")
+	}
+}
+
 func TestUpdateDocument(t *testing.T) {
 	client := setupTestClient(t)
 	indexName := "test-update-doc"
@@ -212,6 +403,7 @@ func TestUpdateDocument(t *testing.T) {
 		docID     string
 		updates   interface{}
 		wantError bool
+		wantIs    error
 		validate  func(t *testing.T, doc map[string]interface{})
 	}{
 		{
@@ -254,6 +446,7 @@ func TestUpdateDocument(t *testing.T) {
 				"value": 300,
 			},
 			wantError: true,
+			wantIs:    ErrDocumentNotFound,
 			validate:  nil,
 		},
 	}
@@ -265,6 +458,9 @@ func TestUpdateDocument(t *testing.T) {
 				t.Errorf("UpdateDocument() error = %v, wantError %v", err, tt.wantError)
 				return
 			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("UpdateDocument() error = %v, want %v", err, tt.wantIs)
+			}
 
 			if !tt.wantError && tt.validate != nil {
 				// Verify update
@@ -278,6 +474,308 @@ func TestUpdateDocument(t *testing.T) {
 	}
 }
 
+func TestUpdateDocumentIfMatch(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-update-if-match"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := client.CreateDocument(ctx, indexName, "doc-1", map[string]interface{}{
+		"title": "Original Title",
+		"value": 100,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test document: %v", err)
+	}
+
+	_, meta, err := client.GetDocumentWithMeta(ctx, indexName, "doc-1")
+	if err != nil {
+		t.Fatalf("GetDocumentWithMeta() error = %v", err)
+	}
+	if meta.PrimaryTerm == 0 {
+		t.Errorf("Expected non-zero primary term, got %d", meta.PrimaryTerm)
+	}
+
+	// First writer succeeds with the current metadata
+	err = client.UpdateDocumentIfMatch(ctx, indexName, "doc-1", map[string]interface{}{
+		"value": 200,
+	}, meta.SeqNo, meta.PrimaryTerm)
+	if err != nil {
+		t.Fatalf("UpdateDocumentIfMatch() with fresh metadata error = %v", err)
+	}
+
+	// Second writer still holds the stale metadata and must be rejected
+	err = client.UpdateDocumentIfMatch(ctx, indexName, "doc-1", map[string]interface{}{
+		"value": 300,
+	}, meta.SeqNo, meta.PrimaryTerm)
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("UpdateDocumentIfMatch() with stale metadata error = %v, want ErrVersionConflict", err)
+	}
+
+	doc, err := client.GetDocument(ctx, indexName, "doc-1")
+	if err != nil {
+		t.Fatalf("Failed to get document: %v", err)
+	}
+	if doc["value"] != float64(200) {
+		t.Errorf("Expected value 200 after rejected stale update, got %v", doc["value"])
+	}
+}
+
+func TestGetDocumentRaw(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-get-raw"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := client.CreateDocument(ctx, indexName, "doc-1", map[string]interface{}{"value": 1})
+	if err != nil {
+		t.Fatalf("Failed to create test document: %v", err)
+	}
+
+	before, err := client.GetDocumentRaw(ctx, indexName, "doc-1")
+	if err != nil {
+		t.Fatalf("GetDocumentRaw() error = %v", err)
+	}
+	if !before.Found || before.ID != "doc-1" || before.Index != indexName {
+		t.Errorf("GetDocumentRaw() = %+v, want found doc-1 in %s", before, indexName)
+	}
+	if before.PrimaryTerm == 0 {
+		t.Errorf("Expected non-zero primary term, got %d", before.PrimaryTerm)
+	}
+
+	if err := client.UpdateDocument(ctx, indexName, "doc-1", map[string]interface{}{"value": 2}); err != nil {
+		t.Fatalf("Failed to update document: %v", err)
+	}
+
+	after, err := client.GetDocumentRaw(ctx, indexName, "doc-1")
+	if err != nil {
+		t.Fatalf("GetDocumentRaw() after update error = %v", err)
+	}
+	if after.Version != before.Version+1 {
+		t.Errorf("Version after update = %d, want %d", after.Version, before.Version+1)
+	}
+	if after.SeqNo <= before.SeqNo {
+		t.Errorf("SeqNo after update = %d, want > %d", after.SeqNo, before.SeqNo)
+	}
+	if after.Source["value"] != float64(2) {
+		t.Errorf("Source value = %v, want 2", after.Source["value"])
+	}
+}
+
+func TestGetSourceRaw_Parse(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the body unchanged", func(t *testing.T) {
+		source := `{"zeta":1,"id":9007199254740993,"alpha":"a"}`
+		stub := &stubTransport{status: 200, body: source}
+		client := newStubClient(t, stub)
+
+		got, err := client.GetSourceRaw(ctx, "books", "1", WithRouting("user-1"))
+		if err != nil {
+			t.Fatalf("GetSourceRaw() error = %v", err)
+		}
+		if string(got) != source {
+			t.Errorf("GetSourceRaw() = %s, want %s", got, source)
+		}
+		if stub.path != "/books/_source/1" || stub.query != "routing=user-1" {
+			t.Errorf("request = %s?%s, want /books/_source/1?routing=user-1", stub.path, stub.query)
+		}
+	})
+
+	t.Run("missing document", func(t *testing.T) {
+		stub := &stubTransport{status: 404, body: `{"error":{"type":"resource_not_found_exception","reason":"Document not found [books]/[1]"},"status":404}`}
+		client := newStubClient(t, stub)
+
+		_, err := client.GetSourceRaw(ctx, "books", "1")
+		if !errors.Is(err, ErrDocumentNotFound) {
+			t.Errorf("GetSourceRaw() error = %v, want ErrDocumentNotFound", err)
+		}
+	})
+}
+
+func TestGetSourceRaw(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-get-source-raw"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// 2^53 + 1 cannot be represented as a float64
+	document := json.RawMessage(`{"title":"Go","views":9007199254740993}`)
+	if err := client.CreateDocument(ctx, indexName, "doc-1", document); err != nil {
+		t.Fatalf("Failed to create test document: %v", err)
+	}
+
+	source, err := client.GetSourceRaw(ctx, indexName, "doc-1")
+	if err != nil {
+		t.Fatalf("GetSourceRaw() error = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(source, &got); err != nil {
+		t.Fatalf("GetSourceRaw() returned invalid JSON %s: %v", source, err)
+	}
+	if got["title"] != "Go" || len(got) != 2 {
+		t.Errorf("GetSourceRaw() = %s, want the original document", source)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(source))
+	dec.UseNumber()
+	var exact map[string]interface{}
+	if err := dec.Decode(&exact); err != nil {
+		t.Fatalf("failed to decode source: %v", err)
+	}
+	if views := exact["views"].(json.Number).String(); views != "9007199254740993" {
+		t.Errorf("views = %s, want 9007199254740993", views)
+	}
+
+	if _, err := client.GetSourceRaw(ctx, indexName, "missing"); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("GetSourceRaw() for a missing document error = %v, want ErrDocumentNotFound", err)
+	}
+}
+
+func TestUseJSONNumber_Parse(t *testing.T) {
+	ctx := context.Background()
+	const id int64 = 9007199254740993 // 2^53 + 1, which float64 rounds to 2^53
+
+	newClientWith := func(t *testing.T, useNumber bool, body string) *Client {
+		t.Helper()
+		client, err := newClient(Config{
+			Addresses:     []string{"http://stub:9200"},
+			DisableRetry:  true,
+			UseJSONNumber: useNumber,
+		}, &stubTransport{status: 200, body: body})
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+		return client
+	}
+
+	getBody := `{"_index":"users","_id":"1","found":true,"_source":{"user_id":9007199254740993,"score":0.5}}`
+	searchBody := `{"hits":{"total":{"value":1},"hits":[{"_id":"1","_score":1.5,"_source":{"user_id":9007199254740993}}]}}`
+
+	t.Run("GetDocument", func(t *testing.T) {
+		doc, err := newClientWith(t, true, getBody).GetDocument(ctx, "users", "1")
+		if err != nil {
+			t.Fatalf("GetDocument() error = %v", err)
+		}
+		number, ok := doc["user_id"].(json.Number)
+		if !ok {
+			t.Fatalf("user_id = %T, want json.Number", doc["user_id"])
+		}
+		if got, err := number.Int64(); err != nil || got != id {
+			t.Errorf("user_id = %v, want %d", number, id)
+		}
+		if doc["score"] != json.Number("0.5") {
+			t.Errorf("score = %#v, want json.Number 0.5", doc["score"])
+		}
+	})
+
+	t.Run("SearchDocuments", func(t *testing.T) {
+		docs, err := newClientWith(t, true, searchBody).SearchDocuments(ctx, "users", MatchAllQuery())
+		if err != nil {
+			t.Fatalf("SearchDocuments() error = %v", err)
+		}
+		if len(docs) != 1 || docs[0]["user_id"] != json.Number("9007199254740993") {
+			t.Errorf("SearchDocuments() = %v, want user_id %d", docs, id)
+		}
+	})
+
+	t.Run("ClusterStats Raw", func(t *testing.T) {
+		stats, err := newClientWith(t, true, `{"cluster_name":"c","indices":{"docs":{"count":9007199254740993}}}`).ClusterStats(ctx)
+		if err != nil {
+			t.Fatalf("ClusterStats() error = %v", err)
+		}
+		docs := stats.Raw["indices"].(map[string]interface{})["docs"].(map[string]interface{})
+		if docs["count"] != json.Number("9007199254740993") {
+			t.Errorf("Raw docs count = %#v, want json.Number %d", docs["count"], id)
+		}
+	})
+
+	t.Run("NodesStats Raw", func(t *testing.T) {
+		stats, err := newClientWith(t, true, `{"nodes":{"n1":{"name":"node-1","indices":{"docs":{"count":9007199254740993}}}}}`).NodesStats(ctx, nil)
+		if err != nil {
+			t.Fatalf("NodesStats() error = %v", err)
+		}
+		docs := stats["n1"].Raw["indices"].(map[string]interface{})["docs"].(map[string]interface{})
+		if docs["count"] != json.Number("9007199254740993") {
+			t.Errorf("Raw docs count = %#v, want json.Number %d", docs["count"], id)
+		}
+	})
+
+	t.Run("ExplainISM Raw", func(t *testing.T) {
+		explanation, err := newClientWith(t, true, `{"logs":{"policy_id":"rollover","policy_seq_no":9007199254740993}}`).ExplainISM(ctx, "logs")
+		if err != nil {
+			t.Fatalf("ExplainISM() error = %v", err)
+		}
+		if explanation.Raw["policy_seq_no"] != json.Number("9007199254740993") {
+			t.Errorf("Raw policy_seq_no = %#v, want json.Number %d", explanation.Raw["policy_seq_no"], id)
+		}
+	})
+
+	t.Run("float64 by default", func(t *testing.T) {
+		doc, err := newClientWith(t, false, getBody).GetDocument(ctx, "users", "1")
+		if err != nil {
+			t.Fatalf("GetDocument() error = %v", err)
+		}
+		if doc["user_id"] != float64(id) {
+			t.Errorf("user_id = %#v, want float64", doc["user_id"])
+		}
+	})
+}
+
+func TestUseJSONNumber(t *testing.T) {
+	client, err := NewClient(Config{
+		Addresses:     []string{integrationURL(t)},
+		UseJSONNumber: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	indexName := "test-use-json-number"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	const id int64 = 9007199254740993
+	document := json.RawMessage(fmt.Sprintf(`{"user_id":%d}`, id))
+	if err := client.CreateDocument(ctx, indexName, "doc-1", document); err != nil {
+		t.Fatalf("Failed to create test document: %v", err)
+	}
+
+	doc, err := client.GetDocument(ctx, indexName, "doc-1")
+	if err != nil {
+		t.Fatalf("GetDocument() error = %v", err)
+	}
+	number, _ := doc["user_id"].(json.Number)
+	if got, err := number.Int64(); err != nil || got != id {
+		t.Errorf("GetDocument() user_id = %#v, want %d", doc["user_id"], id)
+	}
+
+	// Write the decoded document back and read it again: the value must survive the round trip
+	if err := client.CreateDocument(ctx, indexName, "doc-2", doc); err != nil {
+		t.Fatalf("Failed to index the decoded document: %v", err)
+	}
+	docs, err := client.SearchDocuments(ctx, indexName, TermQuery("user_id", number))
+	if err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("SearchDocuments() returned %d documents, want both copies", len(docs))
+	}
+	for _, d := range docs {
+		if d["user_id"] != json.Number("9007199254740993") {
+			t.Errorf("document %v user_id = %#v, want %d", d["_id"], d["user_id"], id)
+		}
+	}
+}
+
 func TestDeleteDocument(t *testing.T) {
 	client := setupTestClient(t)
 	indexName := "test-delete-doc"
@@ -299,6 +797,7 @@ func TestDeleteDocument(t *testing.T) {
 		name      string
 		docID     string
 		wantError bool
+		wantIs    error
 	}{
 		{
 			name:      "Delete existing document",
@@ -309,6 +808,7 @@ func TestDeleteDocument(t *testing.T) {
 			name:      "Delete non-existent document",
 			docID:     "non-existent",
 			wantError: true,
+			wantIs:    ErrDocumentNotFound,
 		},
 	}
 
@@ -319,6 +819,9 @@ func TestDeleteDocument(t *testing.T) {
 				t.Errorf("DeleteDocument() error = %v, wantError %v", err, tt.wantError)
 				return
 			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("DeleteDocument() error = %v, want %v", err, tt.wantIs)
+			}
 
 			if !tt.wantError {
 				// Verify deletion
@@ -331,17 +834,43 @@ func TestDeleteDocument(t *testing.T) {
 	}
 }
 
-func TestSearchDocuments(t *testing.T) {
+func TestDeleteByQuery(t *testing.T) {
 	client := setupTestClient(t)
-	indexName := "test-search-docs"
+	indexName := "test-delete-by-query"
 	cleanup := setupTestIndex(t, client, indexName)
 	defer cleanup()
 
 	ctx := context.Background()
+	seedDocuments(t, client, indexName, 10)
 
-	// Create test documents
-	testDocs := []struct {
-		id  string
+	deleted, err := client.DeleteByQuery(ctx, indexName, RangeQuery("seq", nil, 3))
+	if err != nil {
+		t.Fatalf("DeleteByQuery() error = %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("DeleteByQuery() deleted = %d, want 4", deleted)
+	}
+
+	results, err := client.SearchAll(ctx, indexName)
+	if err != nil {
+		t.Fatalf("SearchAll() error = %v", err)
+	}
+	if len(results) != 6 {
+		t.Errorf("Expected 6 documents left, got %d", len(results))
+	}
+}
+
+func TestSearchDocuments(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-search-docs"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// Create test documents
+	testDocs := []struct {
+		id  string
 		doc map[string]interface{}
 	}{
 		{
@@ -377,8 +906,8 @@ func TestSearchDocuments(t *testing.T) {
 		}
 	}
 
-	// Wait for documents to be indexed
-	time.Sleep(200 * time.Millisecond)
+	// Make indexed documents visible to search
+	refreshTestIndex(t, client, indexName)
 
 	tests := []struct {
 		name           string
@@ -494,6 +1023,557 @@ func TestSearchDocuments(t *testing.T) {
 	}
 }
 
+func TestSearchDocuments_Collapse(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-search-collapse"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	docs := []map[string]interface{}{
+		{"_id": "1", "author": "alice", "title": "First"},
+		{"_id": "2", "author": "alice", "title": "Second"},
+		{"_id": "3", "author": "bob", "title": "Third"},
+		{"_id": "4", "author": "carol", "title": "Fourth"},
+		{"_id": "5", "author": "bob", "title": "Fifth"},
+	}
+	if err := client.BulkCreate(ctx, indexName, docs); err != nil {
+		t.Fatalf("Failed to seed documents: %v", err)
+	}
+
+	query := WithCollapse(MatchAllQuery(), "author.keyword")
+	results, err := client.SearchDocuments(ctx, indexName, query)
+	if err != nil {
+		t.Fatalf("SearchDocuments() with collapse error = %v", err)
+	}
+
+	authors := make(map[interface{}]int)
+	for _, result := range results {
+		authors[result["author"]]++
+	}
+	if len(results) != 3 || len(authors) != 3 {
+		t.Errorf("Expected one hit for each of 3 authors, got %d hits: %v", len(results), authors)
+	}
+}
+
+func TestSearchDocuments_MatchBoolPrefix(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-match-bool-prefix"
+	ctx := context.Background()
+
+	_, _ = client.DeleteIndexIfExists(ctx, indexName)
+	err := client.CreateIndex(ctx, indexName, map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"title": map[string]interface{}{"type": "search_as_you_type"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test index: %v", err)
+	}
+	defer func() { _ = client.DeleteIndex(ctx, indexName) }()
+
+	documents := []map[string]interface{}{
+		{"_id": "1", "title": "quick brown fox"},
+		{"_id": "2", "title": "quick blue hare"},
+		{"_id": "3", "title": "lazy brown dog"},
+	}
+	if err := client.BulkCreate(ctx, indexName, documents); err != nil {
+		t.Fatalf("Failed to create test documents: %v", err)
+	}
+
+	results, err := client.SearchDocuments(ctx, indexName, MatchBoolPrefixQuery("title", "quick br"))
+	if err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+	// The terms are ORed, so every title with "quick" or a word starting with "br" matches,
+	// and the title matching both ranks first
+	if len(results) != 3 || results[0]["_id"] != "1" {
+		t.Errorf("SearchDocuments() = %v, want all three titles with quick brown fox first", results)
+	}
+}
+
+func TestSearchDocuments_TerminateAfter(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-search-terminate-after"
+	cleanup := setupShardedIndex(t, client, indexName, 1)
+	defer cleanup()
+
+	ctx := context.Background()
+	seedDocuments(t, client, indexName, 20)
+
+	var response SearchResponse
+	if err := client.search(ctx, indexName, WithTerminateAfter(MatchAllQuery(), 3), &response); err != nil {
+		t.Fatalf("search() with terminate_after error = %v", err)
+	}
+
+	if !response.TerminatedEarly {
+		t.Error("TerminatedEarly = false, want true")
+	}
+	if response.Hits.Total.Value != 3 || len(response.Hits.Hits) != 3 {
+		t.Errorf("Collected %d documents (%d hits), want 3 of 20", response.Hits.Total.Value, len(response.Hits.Hits))
+	}
+}
+
+func TestSearchRawHits(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-search-raw-hits"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := client.CreateDocument(ctx, indexName, "doc-1", map[string]interface{}{
+		"title": "Golang Tutorial",
+		"views": 150,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test document: %v", err)
+	}
+
+	hits, err := client.SearchRawHits(ctx, indexName, MatchQuery("title", "golang"))
+	if err != nil {
+		t.Fatalf("SearchRawHits() error = %v", err)
+	}
+	if len(hits) != 1 {
+		t.Fatalf("Expected 1 hit, got %d", len(hits))
+	}
+
+	var doc struct {
+		Title string `json:"title"`
+		Views int    `json:"views"`
+	}
+	if err := json.Unmarshal(hits[0].Source, &doc); err != nil {
+		t.Fatalf("Failed to decode raw source: %v", err)
+	}
+	if hits[0].ID != "doc-1" || doc.Title != "Golang Tutorial" || doc.Views != 150 {
+		t.Errorf("Unexpected hit %s: %+v", hits[0].ID, doc)
+	}
+}
+
+func TestSearch_Parse(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{
+		"took":12,
+		"hits":{
+			"total":{"value":10000,"relation":"gte"},
+			"max_score":2.5,
+			"hits":[{"_index":"my-index","_id":"1","_score":2.5,"_source":{"title":"Golang"}}]
+		}
+	}`}
+	client := newStubClient(t, stub)
+
+	result, err := client.Search(context.Background(), "my-index", MatchQuery("title", "golang"))
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.Total != 10000 || result.TotalRelation != "gte" || result.MaxScore != 2.5 || result.TookMs != 12 {
+		t.Errorf("Search() = %+v, want total 10000 (gte), max score 2.5 and took 12", result)
+	}
+	if len(result.Hits) != 1 || result.Hits[0].ID != "1" || result.Hits[0].Source["title"] != "Golang" {
+		t.Errorf("Search() hits = %+v, want document 1", result.Hits)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-search-result"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	seedDocuments(t, client, indexName, 15)
+
+	result, err := client.Search(ctx, indexName, WithSize(MatchAllQuery(), 5))
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.Total != 15 || result.TotalRelation != "eq" {
+		t.Errorf("Total = %d (%s), want 15 (eq)", result.Total, result.TotalRelation)
+	}
+	if len(result.Hits) != 5 {
+		t.Errorf("Search() returned %d hits, want 5", len(result.Hits))
+	}
+	if result.MaxScore != 1 {
+		t.Errorf("MaxScore = %v, want 1 for match_all", result.MaxScore)
+	}
+	// A search this small may take under a millisecond, so took can be 0
+	if result.TookMs < 0 {
+		t.Errorf("TookMs = %d, want the took time of the response", result.TookMs)
+	}
+}
+
+func TestSearchWithProfile_Parse(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{
+		"hits":{"hits":[{"_id":"1","_source":{"title":"Golang"}}]},
+		"profile":{"shards":[{
+			"id":"[node-1][my-index][0]",
+			"searches":[{
+				"query":[{
+					"type":"TermQuery",
+					"description":"title:golang",
+					"time_in_nanos":1500,
+					"breakdown":{"create_weight":1000,"next_doc":500}
+				}],
+				"rewrite_time":300,
+				"collector":[{"name":"SimpleTopScoreDocCollector","reason":"search_top_hits","time_in_nanos":200}]
+			}],
+			"aggregations":[]
+		}]}
+	}`}
+	client := newStubClient(t, stub)
+
+	query := MatchQuery("title", "golang")
+	results, profile, err := client.SearchWithProfile(context.Background(), "my-index", query)
+	if err != nil {
+		t.Fatalf("SearchWithProfile() error = %v", err)
+	}
+	if _, set := query["profile"]; set {
+		t.Error("SearchWithProfile() modified the caller's query")
+	}
+	if !strings.Contains(string(stub.sent), `"profile":true`) {
+		t.Errorf("Request body = %s, want profile enabled", stub.sent)
+	}
+	if len(results) != 1 {
+		t.Errorf("SearchWithProfile() returned %d results, want 1", len(results))
+	}
+
+	if len(profile.Shards) != 1 || len(profile.Shards[0].Searches) != 1 {
+		t.Fatalf("Profile = %+v, want one shard with one search", profile)
+	}
+	search := profile.Shards[0].Searches[0]
+	if len(search.Query) != 1 || search.Query[0].Type != "TermQuery" || search.Query[0].TimeInNanos != 1500 || search.Query[0].Breakdown["next_doc"] != 500 {
+		t.Errorf("Query profile = %+v, want the TermQuery timings", search.Query)
+	}
+	if search.RewriteTime != 300 || len(search.Collector) != 1 || search.Collector[0].Name != "SimpleTopScoreDocCollector" {
+		t.Errorf("Search profile = %+v, want rewrite time and collector", search)
+	}
+}
+
+func TestSearchWithProfile(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-search-profile"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	err := client.CreateDocument(ctx, indexName, "1", map[string]interface{}{"title": "Golang Tutorial"})
+	if err != nil {
+		t.Fatalf("Failed to create test document: %v", err)
+	}
+
+	results, profile, err := client.SearchWithProfile(ctx, indexName, MatchQuery("title", "golang"))
+	if err != nil {
+		t.Fatalf("SearchWithProfile() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("SearchWithProfile() returned %d results, want 1", len(results))
+	}
+	if len(profile.Shards) == 0 || len(profile.Shards[0].Searches) == 0 || len(profile.Shards[0].Searches[0].Query) == 0 {
+		t.Fatalf("Profile = %+v, want query timings", profile)
+	}
+	if profile.Shards[0].Searches[0].Query[0].TimeInNanos <= 0 {
+		t.Errorf("Query time = %d ns, want it measured", profile.Shards[0].Searches[0].Query[0].TimeInNanos)
+	}
+}
+
+func TestMultiSearch_Parse(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{"took":3,"responses":[
+		{"hits":{"hits":[{"_id":"1","_source":{"title":"Golang"}}]},"status":200},
+		{"hits":{"hits":[]},"status":200}
+	]}`}
+	client := newStubClient(t, stub)
+
+	results, err := client.MultiSearch(context.Background(), []SearchSpec{
+		{Index: "books", Query: MatchQuery("title", "golang")},
+		{Index: "articles", Query: TermQuery("author", "jane")},
+	})
+	if err != nil {
+		t.Fatalf("MultiSearch() error = %v", err)
+	}
+	if stub.path != "/_msearch" {
+		t.Errorf("Request path = %s, want /_msearch", stub.path)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(stub.sent), "\n"), "\n")
+	if len(lines) != 4 || lines[0] != `{"index":"books"}` || lines[2] != `{"index":"articles"}` {
+		t.Errorf("Request body = %s, want a header and body line per search", stub.sent)
+	}
+	if len(results) != 2 || len(results[0]) != 1 || results[0][0]["_id"] != "1" || len(results[1]) != 0 {
+		t.Errorf("MultiSearch() = %v, want one hit for the first search and none for the second", results)
+	}
+
+	t.Run("Failed search", func(t *testing.T) {
+		stub := &stubTransport{status: 200, body: `{"responses":[
+			{"hits":{"hits":[]},"status":200},
+			{"error":{"type":"index_not_found_exception","reason":"no such index [missing]"},"status":404}
+		]}`}
+		client := newStubClient(t, stub)
+
+		_, err := client.MultiSearch(context.Background(), []SearchSpec{{Index: "books"}, {Index: "missing"}})
+		if err == nil || !strings.Contains(err.Error(), "search 1 on missing") || !strings.Contains(err.Error(), "no such index") {
+			t.Errorf("MultiSearch() error = %v, want the second search's failure", err)
+		}
+		var osErr *OpenSearchError
+		if !errors.Is(err, ErrIndexNotFound) || !errors.As(err, &osErr) || osErr.StatusCode != 404 {
+			t.Errorf("MultiSearch() error = %v, want a 404 *OpenSearchError matching ErrIndexNotFound", err)
+		}
+	})
+}
+
+func TestMultiSearch(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-multi-search"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	documents := map[string]map[string]interface{}{
+		"doc-1": {"title": "Golang Tutorial", "category": "programming"},
+		"doc-2": {"title": "Python Guide", "category": "programming"},
+		"doc-3": {"title": "Bread Baking", "category": "cooking"},
+	}
+	for id, doc := range documents {
+		if err := client.CreateDocument(ctx, indexName, id, doc); err != nil {
+			t.Fatalf("Failed to create test document %s: %v", id, err)
+		}
+	}
+
+	results, err := client.MultiSearch(ctx, []SearchSpec{
+		{Index: indexName, Query: MatchQuery("title", "golang")},
+		{Index: indexName, Query: TermQuery("category", "programming")},
+	})
+	if err != nil {
+		t.Fatalf("MultiSearch() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("MultiSearch() returned %d responses, want 2", len(results))
+	}
+	if len(results[0]) != 1 || results[0][0]["_id"] != "doc-1" {
+		t.Errorf("First search = %v, want doc-1", results[0])
+	}
+	if len(results[1]) != 2 {
+		t.Errorf("Second search returned %d documents, want 2", len(results[1]))
+	}
+}
+
+func TestSearchHits_NamedQueries(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-search-named-queries"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	documents := map[string]map[string]interface{}{
+		"doc-1": {"title": "Golang Tutorial", "category": "programming"},
+		"doc-2": {"title": "Golang Cookbook", "category": "cooking"},
+	}
+	for id, doc := range documents {
+		if err := client.CreateDocument(ctx, indexName, id, doc); err != nil {
+			t.Fatalf("Failed to create test document %s: %v", id, err)
+		}
+	}
+
+	query := NewBoolBuilder().
+		Should(
+			NamedQuery("title-golang", MatchQuery("title", "golang")),
+			NamedQuery("category-programming", MatchQuery("category", "programming")),
+		).
+		Query()
+
+	hits, err := client.SearchHits(ctx, indexName, query)
+	if err != nil {
+		t.Fatalf("SearchHits() error = %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("Expected 2 hits, got %d", len(hits))
+	}
+
+	want := map[string][]string{
+		"doc-1": {"category-programming", "title-golang"},
+		"doc-2": {"title-golang"},
+	}
+	for _, hit := range hits {
+		matched := append([]string(nil), hit.MatchedQueries...)
+		sort.Strings(matched)
+		if !reflect.DeepEqual(matched, want[hit.ID]) {
+			t.Errorf("Hit %s matched_queries = %v, want %v", hit.ID, matched, want[hit.ID])
+		}
+	}
+}
+
+func TestExplainDocument(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-explain-doc"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	err := client.CreateDocument(ctx, indexName, "doc-1", map[string]interface{}{
+		"title": "Golang Tutorial",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test document: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		query       map[string]interface{}
+		wantMatched bool
+	}{
+		{
+			name:        "Matching query",
+			query:       MatchQuery("title", "golang"),
+			wantMatched: true,
+		},
+		{
+			name:        "Non-matching query",
+			query:       MatchQuery("title", "python"),
+			wantMatched: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation, err := client.ExplainDocument(ctx, indexName, "doc-1", tt.query)
+			if err != nil {
+				t.Fatalf("ExplainDocument() error = %v", err)
+			}
+
+			matched, ok := explanation["matched"].(bool)
+			if !ok {
+				t.Fatalf("Explanation missing boolean 'matched': %v", prettyPrint(explanation))
+			}
+			if matched != tt.wantMatched {
+				t.Errorf("matched = %v, want %v", matched, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestValidateQuery_Parse(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unwraps a search body", func(t *testing.T) {
+		stub := &stubTransport{status: 200, body: `{
+			"valid":true,
+			"explanations":[{"index":"books","valid":true,"explanation":"views:[1 TO 100]"}]
+		}`}
+		client := newStubClient(t, stub)
+
+		valid, explanations, err := client.ValidateQuery(ctx, "books", WithSize(RangeQuery("views", 1, 100), 5))
+		if err != nil {
+			t.Fatalf("ValidateQuery() error = %v", err)
+		}
+		if !valid {
+			t.Error("ValidateQuery() valid = false, want true")
+		}
+		want := []string{"books: views:[1 TO 100]"}
+		if !reflect.DeepEqual(explanations, want) {
+			t.Errorf("ValidateQuery() explanations = %v, want %v", explanations, want)
+		}
+
+		if stub.path != "/books/_validate/query" || stub.query != "explain=true" {
+			t.Errorf("request = %s?%s, want /books/_validate/query?explain=true", stub.path, stub.query)
+		}
+		var sent map[string]interface{}
+		if err := json.Unmarshal(stub.sent, &sent); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if _, ok := sent["size"]; ok || len(sent) != 1 {
+			t.Errorf("request body = %v, want only the query", sent)
+		}
+	})
+
+	t.Run("per-index errors", func(t *testing.T) {
+		stub := &stubTransport{status: 200, body: `{
+			"valid":false,
+			"explanations":[
+				{"index":"books","valid":false,"error":"failed to parse date field [soon]"},
+				{"index":"papers","valid":true,"explanation":"published:[1 TO 2]"}
+			]
+		}`}
+		client := newStubClient(t, stub)
+
+		valid, explanations, err := client.ValidateQuery(ctx, "books,papers", RangeQuery("published", "soon", nil))
+		if err != nil {
+			t.Fatalf("ValidateQuery() error = %v", err)
+		}
+		if valid {
+			t.Error("ValidateQuery() valid = true, want false")
+		}
+		want := []string{"books: failed to parse date field [soon]", "papers: published:[1 TO 2]"}
+		if !reflect.DeepEqual(explanations, want) {
+			t.Errorf("ValidateQuery() explanations = %v, want %v", explanations, want)
+		}
+	})
+
+	t.Run("parse failure", func(t *testing.T) {
+		stub := &stubTransport{status: 400, body: `{"error":{"type":"parsing_exception","reason":"[range] query does not support [between]"},"status":400}`}
+		client := newStubClient(t, stub)
+
+		valid, explanations, err := client.ValidateQuery(ctx, "books", MatchAllQuery())
+		if err != nil {
+			t.Fatalf("ValidateQuery() error = %v", err)
+		}
+		want := []string{"[range] query does not support [between]"}
+		if valid || !reflect.DeepEqual(explanations, want) {
+			t.Errorf("ValidateQuery() = %v, %v, want false, %v", valid, explanations, want)
+		}
+	})
+}
+
+func TestValidateQuery(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-validate-query"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if err := client.CreateDocument(ctx, indexName, "doc-1", map[string]interface{}{"views": 10}); err != nil {
+		t.Fatalf("Failed to create test document: %v", err)
+	}
+
+	t.Run("Valid range query", func(t *testing.T) {
+		valid, explanations, err := client.ValidateQuery(ctx, indexName, RangeQuery("views", 1, 100))
+		if err != nil {
+			t.Fatalf("ValidateQuery() error = %v", err)
+		}
+		if !valid {
+			t.Errorf("ValidateQuery() valid = false, want true (explanations: %v)", explanations)
+		}
+		if len(explanations) == 0 {
+			t.Error("ValidateQuery() should explain a valid query")
+		}
+	})
+
+	t.Run("Malformed range query", func(t *testing.T) {
+		query := map[string]interface{}{
+			"query": map[string]interface{}{
+				"range": map[string]interface{}{
+					"views": map[string]interface{}{
+						"between": []int{1, 100},
+					},
+				},
+			},
+		}
+
+		valid, explanations, err := client.ValidateQuery(ctx, indexName, query)
+		if err != nil {
+			t.Fatalf("ValidateQuery() error = %v", err)
+		}
+		if valid {
+			t.Error("ValidateQuery() valid = true, want false")
+		}
+		if len(explanations) == 0 || explanations[0] == "" {
+			t.Errorf("ValidateQuery() explanations = %v, want the reason the query is invalid", explanations)
+		}
+	})
+}
+
 func TestSearchAll(t *testing.T) {
 	client := setupTestClient(t)
 	indexName := "test-search-all"
@@ -514,8 +1594,8 @@ func TestSearchAll(t *testing.T) {
 		}
 	}
 
-	// Wait for documents to be indexed
-	time.Sleep(200 * time.Millisecond)
+	// Make indexed documents visible to search
+	refreshTestIndex(t, client, indexName)
 
 	results, err := client.SearchAll(ctx, indexName)
 	if err != nil {
@@ -605,6 +1685,57 @@ func TestCreateIndex(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Create existing index", func(t *testing.T) {
+		indexName := "test-index-existing"
+		cleanup := setupTestIndex(t, client, indexName)
+		defer cleanup()
+
+		err := client.CreateIndex(ctx, indexName, nil)
+		if !errors.Is(err, ErrIndexAlreadyExists) {
+			t.Errorf("CreateIndex() error = %v, want %v", err, ErrIndexAlreadyExists)
+		}
+	})
+}
+
+func TestEnsureIndex(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+	indexName := "test-ensure-index"
+
+	_ = client.DeleteIndex(ctx, indexName)
+	defer func() { _ = client.DeleteIndex(ctx, indexName) }()
+
+	body := map[string]interface{}{
+		"settings": map[string]interface{}{"number_of_replicas": 0},
+	}
+	for i, want := range []bool{true, false} {
+		created, err := client.EnsureIndex(ctx, indexName, body)
+		if err != nil {
+			t.Fatalf("EnsureIndex() call %d error = %v", i+1, err)
+		}
+		if created != want {
+			t.Errorf("EnsureIndex() call %d created = %v, want %v", i+1, created, want)
+		}
+	}
+}
+
+func TestEnsureIndex_CreatedConcurrently(t *testing.T) {
+	// The index is missing when checked, then created by someone else before the create
+	stub := &stubTransport{
+		statuses: []int{404, 400},
+		body: `{"error":{"root_cause":[{"type":"resource_already_exists_exception","reason":"index [books/abc] already exists","index":"books"}],` +
+			`"type":"resource_already_exists_exception","reason":"index [books/abc] already exists","index":"books"},"status":400}`,
+	}
+	client := newStubClient(t, stub)
+
+	created, err := client.EnsureIndex(context.Background(), "books", nil)
+	if err != nil || created {
+		t.Errorf("EnsureIndex() = %v, %v, want not created and no error", created, err)
+	}
+	if stub.calls != 2 {
+		t.Errorf("Expected an exists check and a create, got %d requests", stub.calls)
+	}
 }
 
 func TestDeleteIndex(t *testing.T) {
@@ -615,6 +1746,7 @@ func TestDeleteIndex(t *testing.T) {
 		name      string
 		setup     func(t *testing.T) string
 		wantError bool
+		wantIs    error
 	}{
 		{
 			name: "Delete existing index",
@@ -634,6 +1766,7 @@ func TestDeleteIndex(t *testing.T) {
 				return "non-existent-index"
 			},
 			wantError: true,
+			wantIs:    ErrIndexNotFound,
 		},
 	}
 
@@ -645,6 +1778,9 @@ func TestDeleteIndex(t *testing.T) {
 			if (err != nil) != tt.wantError {
 				t.Errorf("DeleteIndex() error = %v, wantError %v", err, tt.wantError)
 			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("DeleteIndex() error = %v, want %v", err, tt.wantIs)
+			}
 
 			if !tt.wantError {
 				// Verify deletion
@@ -657,6 +1793,29 @@ func TestDeleteIndex(t *testing.T) {
 	}
 }
 
+func TestDeleteIndexIfExists(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+	indexName := "test-delete-index-if-exists"
+
+	_ = client.DeleteIndex(ctx, indexName)
+
+	deleted, err := client.DeleteIndexIfExists(ctx, indexName)
+	if err != nil || deleted {
+		t.Errorf("DeleteIndexIfExists() of a missing index = %v, %v, want false, nil", deleted, err)
+	}
+
+	if err := client.CreateIndex(ctx, indexName, nil); err != nil {
+		t.Fatalf("Failed to create test index: %v", err)
+	}
+	deleted, err = client.DeleteIndexIfExists(ctx, indexName)
+	if err != nil || !deleted {
+		t.Errorf("DeleteIndexIfExists() of an existing index = %v, %v, want true, nil", deleted, err)
+	}
+	if exists, _ := client.IndexExists(ctx, indexName); exists {
+		t.Error("Index should not exist after deletion")
+	}
+}
 func TestIndexExists(t *testing.T) {
 	client := setupTestClient(t)
 	ctx := context.Background()
@@ -703,6 +1862,74 @@ func TestIndexExists(t *testing.T) {
 	}
 }
 
+func TestIndicesExist(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	cleanupA := setupTestIndex(t, client, "test-exist-a")
+	defer cleanupA()
+	cleanupB := setupTestIndex(t, client, "test-exist-b")
+	defer cleanupB()
+
+	exists, err := client.IndicesExist(ctx, []string{"test-exist-a", "test-exist-b", "test-exist-missing"})
+	if err != nil {
+		t.Fatalf("IndicesExist() error = %v", err)
+	}
+
+	want := map[string]bool{
+		"test-exist-a":       true,
+		"test-exist-b":       true,
+		"test-exist-missing": false,
+	}
+	if len(exists) != len(want) {
+		t.Errorf("IndicesExist() returned %d entries, want %d: %v", len(exists), len(want), exists)
+	}
+	for index, wantExists := range want {
+		if exists[index] != wantExists {
+			t.Errorf("IndicesExist()[%s] = %v, want %v", index, exists[index], wantExists)
+		}
+	}
+}
+
+func TestBulkCreateWithRefresh(t *testing.T) {
+	docs := []map[string]interface{}{{"_id": "1", "title": "Go"}}
+
+	tests := []struct {
+		refresh   string
+		wantQuery string
+	}{
+		{refresh: "true", wantQuery: "refresh=true"},
+		{refresh: "false", wantQuery: "refresh=false"},
+		{refresh: "wait_for", wantQuery: "refresh=wait_for"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.refresh, func(t *testing.T) {
+			stub := &stubTransport{status: 200, body: `{"errors":false,"items":[]}`}
+			client := newStubClient(t, stub)
+
+			if err := client.BulkCreateWithRefresh(context.Background(), "books", docs, tt.refresh); err != nil {
+				t.Fatalf("BulkCreateWithRefresh() error = %v", err)
+			}
+			if stub.path != "/_bulk" || stub.query != tt.wantQuery {
+				t.Errorf("request = %s?%s, want /_bulk?%s", stub.path, stub.query, tt.wantQuery)
+			}
+		})
+	}
+
+	t.Run("BulkCreate refreshes", func(t *testing.T) {
+		stub := &stubTransport{status: 200, body: `{"errors":false,"items":[]}`}
+		client := newStubClient(t, stub)
+
+		if err := client.BulkCreate(context.Background(), "books", docs); err != nil {
+			t.Fatalf("BulkCreate() error = %v", err)
+		}
+		if stub.query != "refresh=true" {
+			t.Errorf("query = %q, want refresh=true", stub.query)
+		}
+	})
+}
+
 func TestBulkCreate(t *testing.T) {
 	client := setupTestClient(t)
 	indexName := "test-bulk-create"
@@ -743,8 +1970,8 @@ func TestBulkCreate(t *testing.T) {
 			},
 			wantError: false,
 			validateFunc: func(t *testing.T) {
-				// Wait for documents to be indexed
-				time.Sleep(200 * time.Millisecond)
+				// Make indexed documents visible to search
+				refreshTestIndex(t, client, indexName)
 
 				// Verify documents were created
 				for i := 1; i <= 3; i++ {
@@ -773,8 +2000,8 @@ func TestBulkCreate(t *testing.T) {
 			},
 			wantError: false,
 			validateFunc: func(t *testing.T) {
-				// Wait for documents to be indexed
-				time.Sleep(200 * time.Millisecond)
+				// Make indexed documents visible to search
+				refreshTestIndex(t, client, indexName)
 
 				// Verify documents exist via search
 				results, err := client.SearchAll(ctx, indexName)
@@ -801,8 +2028,8 @@ func TestBulkCreate(t *testing.T) {
 			}(),
 			wantError: false,
 			validateFunc: func(t *testing.T) {
-				// Wait for documents to be indexed
-				time.Sleep(500 * time.Millisecond)
+				// Make indexed documents visible to search
+				refreshTestIndex(t, client, indexName)
 
 				// Verify some documents were created
 				doc, err := client.GetDocument(ctx, indexName, "large-batch-50")
@@ -831,6 +2058,59 @@ func TestBulkCreate(t *testing.T) {
 	}
 }
 
+// TestBulkCreateChunked tests that documents are sent in chunks and that cancellation stops
+// between chunks
+func TestBulkCreateChunked(t *testing.T) {
+	docs := make([]map[string]interface{}, 25)
+	for i := range docs {
+		docs[i] = map[string]interface{}{"seq": i}
+	}
+
+	t.Run("All chunks", func(t *testing.T) {
+		stub := &stubTransport{status: 200, body: `{"took":1,"errors":false,"items":[]}`}
+		client := newStubClient(t, stub)
+
+		completed, err := client.BulkCreateChunked(context.Background(), "test-chunked", docs, 10)
+		if err != nil {
+			t.Fatalf("BulkCreateChunked() error = %v", err)
+		}
+		if completed != 3 || stub.calls != 3 {
+			t.Errorf("BulkCreateChunked() completed %d chunks in %d requests, want 3", completed, stub.calls)
+		}
+	})
+
+	t.Run("Cancelled mid-run", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		stub := &stubTransport{status: 200, body: `{"took":1,"errors":false,"items":[]}`}
+		stub.onRequest = func() {
+			if stub.calls == 2 {
+				cancel()
+			}
+		}
+		client := newStubClient(t, stub)
+
+		completed, err := client.BulkCreateChunked(ctx, "test-chunked", docs, 5)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("BulkCreateChunked() error = %v, want context.Canceled", err)
+		}
+		if completed != 2 {
+			t.Errorf("BulkCreateChunked() completed = %d, want 2", completed)
+		}
+		if stub.calls != 2 {
+			t.Errorf("Expected 2 bulk requests before stopping, got %d", stub.calls)
+		}
+	})
+
+	t.Run("Invalid chunk size", func(t *testing.T) {
+		client := newStubClient(t, &stubTransport{status: 200, body: `{}`})
+		if _, err := client.BulkCreateChunked(context.Background(), "test-chunked", docs, 0); err == nil {
+			t.Error("BulkCreateChunked() with chunk size 0 expected error but got nil")
+		}
+	})
+}
+
 // TestIntegrationWorkflow tests a complete CRUD workflow
 func TestIntegrationWorkflow(t *testing.T) {
 	client := setupTestClient(t)
@@ -852,7 +2132,7 @@ func TestIntegrationWorkflow(t *testing.T) {
 		t.Fatalf("Failed to create documents: %v", err)
 	}
 
-	time.Sleep(200 * time.Millisecond)
+	refreshTestIndex(t, client, indexName)
 
 	// 2. Search all
 	t.Log("Step 2: Searching all documents")
@@ -941,4 +2221,498 @@ func prettyPrint(v interface{}) string {
 		return fmt.Sprintf("%+v", v)
 	}
 	return string(b)
-}
\ No newline at end of file
+}
+
+func TestSentinelErrors(t *testing.T) {
+	ctx := context.Background()
+	indexMissing := `{"error":{"root_cause":[{"type":"index_not_found_exception","reason":"no such index [books]","index":"books"}],` +
+		`"type":"index_not_found_exception","reason":"no such index [books]","index":"books"},"status":404}`
+
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		call     func(client *Client) error
+		want     error
+		wantText string
+	}{
+		{
+			name:     "Get missing document",
+			status:   404,
+			body:     `{"_index":"books","_id":"1","found":false}`,
+			call:     func(client *Client) error { _, err := client.GetDocument(ctx, "books", "1"); return err },
+			want:     ErrDocumentNotFound,
+			wantText: "document 1 not found in index books",
+		},
+		{
+			name:     "Get from missing index",
+			status:   404,
+			body:     indexMissing,
+			call:     func(client *Client) error { _, err := client.GetDocument(ctx, "books", "1"); return err },
+			want:     ErrIndexNotFound,
+			wantText: "index books not found",
+		},
+		{
+			name:   "Update missing document",
+			status: 404,
+			body: `{"error":{"root_cause":[{"type":"document_missing_exception","reason":"[1]: document missing","index":"books"}],` +
+				`"type":"document_missing_exception","reason":"[1]: document missing","index":"books"},"status":404}`,
+			call: func(client *Client) error {
+				return client.UpdateDocument(ctx, "books", "1", map[string]interface{}{"views": 1})
+			},
+			want:     ErrDocumentNotFound,
+			wantText: "document 1 not found in index books",
+		},
+		{
+			name:     "Delete missing document",
+			status:   404,
+			body:     `{"_index":"books","_id":"1","result":"not_found"}`,
+			call:     func(client *Client) error { return client.DeleteDocument(ctx, "books", "1") },
+			want:     ErrDocumentNotFound,
+			wantText: "document 1 not found in index books",
+		},
+		{
+			name:     "Delete missing index",
+			status:   404,
+			body:     indexMissing,
+			call:     func(client *Client) error { return client.DeleteIndex(ctx, "books") },
+			want:     ErrIndexNotFound,
+			wantText: "index books not found",
+		},
+		{
+			name:   "Create existing index",
+			status: 400,
+			body: `{"error":{"root_cause":[{"type":"resource_already_exists_exception","reason":"index [books/abc] already exists","index":"books"}],` +
+				`"type":"resource_already_exists_exception","reason":"index [books/abc] already exists","index":"books"},"status":400}`,
+			call:     func(client *Client) error { return client.CreateIndex(ctx, "books", nil) },
+			want:     ErrIndexAlreadyExists,
+			wantText: "index [books/abc] already exists",
+		},
+		{
+			name:   "Version conflict",
+			status: 409,
+			body:   `{"error":{"type":"version_conflict_engine_exception","reason":"[1]: version conflict","index":"books"},"status":409}`,
+			call: func(client *Client) error {
+				return client.UpdateDocumentIfMatch(ctx, "books", "1", map[string]interface{}{"views": 1}, 3, 1)
+			},
+			want:     ErrVersionConflict,
+			wantText: "update of document 1 rejected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStubClient(t, &stubTransport{status: tt.status, body: tt.body})
+
+			err := tt.call(client)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantText)
+			}
+		})
+	}
+}
+
+// TestDocumentLifecycle_Fake tests the document requests against a fake cluster
+func TestDocumentLifecycle_Fake(t *testing.T) {
+	client, server := newFakeClient(t)
+	ctx := context.Background()
+
+	if err := client.CreateDocument(ctx, "books", "1", map[string]interface{}{"title": "Dune", "year": 1965}); err != nil {
+		t.Fatalf("CreateDocument() error = %v", err)
+	}
+	req := server.LastRequest()
+	if req.Method != "PUT" || req.Path != "/books/_doc/1" || req.Query != "refresh=true" {
+		t.Errorf("CreateDocument() sent %s %s?%s, want PUT /books/_doc/1?refresh=true", req.Method, req.Path, req.Query)
+	}
+
+	if err := client.UpdateDocument(ctx, "books", "1", map[string]interface{}{"year": 1966}); err != nil {
+		t.Fatalf("UpdateDocument() error = %v", err)
+	}
+	if got := string(server.LastRequest().Body); got != `{"doc":{"year":1966}}` {
+		t.Errorf("UpdateDocument() sent body %s, want the updates wrapped in doc", got)
+	}
+
+	doc, meta, err := client.GetDocumentWithMeta(ctx, "books", "1")
+	if err != nil {
+		t.Fatalf("GetDocumentWithMeta() error = %v", err)
+	}
+	want := map[string]interface{}{"title": "Dune", "year": float64(1966)}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("GetDocumentWithMeta() = %v, want %v", doc, want)
+	}
+	if meta != (DocumentMeta{Version: 2, SeqNo: 1, PrimaryTerm: 1}) {
+		t.Errorf("GetDocumentWithMeta() meta = %+v, want version 2, seq_no 1, primary term 1", meta)
+	}
+
+	if err := client.UpdateDocumentIfMatch(ctx, "books", "1", map[string]interface{}{"year": 1967}, meta.SeqNo, meta.PrimaryTerm); err != nil {
+		t.Fatalf("UpdateDocumentIfMatch() error = %v", err)
+	}
+
+	if err := client.DeleteDocument(ctx, "books", "1"); err != nil {
+		t.Fatalf("DeleteDocument() error = %v", err)
+	}
+	if _, ok := server.Document("books", "1"); ok {
+		t.Error("DeleteDocument() left the document in place")
+	}
+}
+
+// TestErrorMapping_Fake tests that error responses map to the sentinel errors
+func TestErrorMapping_Fake(t *testing.T) {
+	tests := []struct {
+		name    string
+		call    func(ctx context.Context, client *Client) error
+		wantIs  error
+		wantErr string
+	}{
+		{
+			name: "get missing document",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.GetDocument(ctx, "books", "missing")
+				return err
+			},
+			wantIs:  ErrDocumentNotFound,
+			wantErr: "document missing not found in index books",
+		},
+		{
+			name: "get from missing index",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.GetDocument(ctx, "nope", "1")
+				return err
+			},
+			wantIs:  ErrIndexNotFound,
+			wantErr: "index nope not found",
+		},
+		{
+			name: "update missing document",
+			call: func(ctx context.Context, client *Client) error {
+				return client.UpdateDocument(ctx, "books", "missing", map[string]interface{}{"a": 1})
+			},
+			wantIs:  ErrDocumentNotFound,
+			wantErr: "document missing not found in index books",
+		},
+		{
+			name: "delete missing document",
+			call: func(ctx context.Context, client *Client) error {
+				return client.DeleteDocument(ctx, "books", "missing")
+			},
+			wantIs:  ErrDocumentNotFound,
+			wantErr: "document missing not found in index books",
+		},
+		{
+			name: "strict create of existing document",
+			call: func(ctx context.Context, client *Client) error {
+				return client.CreateDocumentStrict(ctx, "books", "1", map[string]interface{}{"title": "again"})
+			},
+			wantIs:  ErrVersionConflict,
+			wantErr: "index of document 1 rejected: version conflict",
+		},
+		{
+			name: "external version older than stored",
+			call: func(ctx context.Context, client *Client) error {
+				return client.CreateDocumentVersioned(ctx, "books", "1", map[string]interface{}{"title": "old"}, 1, "external")
+			},
+			wantIs: ErrVersionConflict,
+		},
+		{
+			name: "update with stale sequence number",
+			call: func(ctx context.Context, client *Client) error {
+				return client.UpdateDocumentIfMatch(ctx, "books", "1", map[string]interface{}{"a": 1}, 7, 1)
+			},
+			wantIs:  ErrVersionConflict,
+			wantErr: "update of document 1 rejected: version conflict",
+		},
+		{
+			name: "create existing index",
+			call: func(ctx context.Context, client *Client) error {
+				return client.CreateIndex(ctx, "books", nil)
+			},
+			wantIs:  ErrIndexAlreadyExists,
+			wantErr: "create index request failed with status: 400 Bad Request",
+		},
+		{
+			name: "delete missing index",
+			call: func(ctx context.Context, client *Client) error {
+				return client.DeleteIndex(ctx, "nope")
+			},
+			wantIs:  ErrIndexNotFound,
+			wantErr: "index nope not found",
+		},
+		{
+			name: "search missing index",
+			call: func(ctx context.Context, client *Client) error {
+				_, err := client.SearchDocuments(ctx, "nope", MatchAllQuery())
+				return err
+			},
+			wantIs:  ErrIndexNotFound,
+			wantErr: "search request failed with status: 404 Not Found: no such index [nope]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := newFakeClient(t)
+			// Store document 1 twice, so that it is at version 2
+			server.PutDocument("books", "1", map[string]interface{}{"title": "Dune"})
+			server.PutDocument("books", "1", map[string]interface{}{"title": "Dune"})
+
+			err := tt.call(context.Background(), client)
+			if !errors.Is(err, tt.wantIs) {
+				t.Fatalf("error = %v, want %v", err, tt.wantIs)
+			}
+			if tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("write to blocked index", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		server.SetReadOnly("books", true)
+
+		err := client.CreateDocument(context.Background(), "books", "1", map[string]interface{}{"title": "Dune"})
+		var osErr *OpenSearchError
+		if !errors.As(err, &osErr) {
+			t.Fatalf("CreateDocument() error = %v, want an *OpenSearchError", err)
+		}
+		if osErr.StatusCode != 403 || osErr.Type != "cluster_block_exception" || osErr.Index != "books" {
+			t.Errorf("CreateDocument() error = %+v, want a 403 cluster_block_exception on books", osErr)
+		}
+	})
+}
+
+// TestSearch_Fake tests building search requests and parsing their responses
+func TestSearch_Fake(t *testing.T) {
+	client, server := newFakeClient(t)
+	ctx := context.Background()
+	server.PutDocument("books", "1", map[string]interface{}{"title": "Dune Messiah", "genre": "scifi"})
+	server.PutDocument("books", "2", map[string]interface{}{"title": "Emma", "genre": "classic"})
+	server.PutDocument("books", "3", map[string]interface{}{"title": "Children of Dune", "genre": "scifi"})
+
+	docs, err := client.SearchDocuments(ctx, "books", TermQuery("genre", "scifi"))
+	if err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+	if len(docs) != 2 || docs[0]["_id"] != "1" || docs[1]["_id"] != "3" {
+		t.Errorf("SearchDocuments() = %v, want documents 1 and 3", docs)
+	}
+	if got := string(server.LastRequest().Body); got != `{"query":{"term":{"genre":"scifi"}}}` {
+		t.Errorf("SearchDocuments() sent body %s", got)
+	}
+
+	all, err := client.SearchAll(ctx, "books")
+	if err != nil {
+		t.Fatalf("SearchAll() error = %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("SearchAll() returned %d documents, want 3", len(all))
+	}
+
+	results, err := client.MultiSearch(ctx, []SearchSpec{
+		{Index: "books", Query: MatchQuery("title", "dune")},
+		{Index: "books", Query: TermQuery("genre", "classic")},
+	})
+	if err != nil {
+		t.Fatalf("MultiSearch() error = %v", err)
+	}
+	if len(results) != 2 || len(results[0]) != 2 || len(results[1]) != 1 || results[1][0]["title"] != "Emma" {
+		t.Errorf("MultiSearch() = %v, want 2 dune books and Emma", results)
+	}
+
+	_, err = client.MultiSearch(ctx, []SearchSpec{{Index: "books"}, {Index: "nope"}})
+	if err == nil || !strings.Contains(err.Error(), "search 1 on nope failed with status 404: index_not_found_exception") {
+		t.Errorf("MultiSearch() error = %v, want search 1 to fail on the missing index", err)
+	}
+}
+
+// TestBulkCreate_Fake tests the bulk request body and the handling of item errors
+func TestBulkCreate_Fake(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("indexes documents", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		docs := []map[string]interface{}{
+			{"_id": "a", "title": "Dune"},
+			{"title": "Emma"},
+		}
+		if err := client.BulkCreate(ctx, "books", docs); err != nil {
+			t.Fatalf("BulkCreate() error = %v", err)
+		}
+
+		wantBody := `{"index":{"_id":"a","_index":"books"}}` + "\n" + `{"title":"Dune"}` + "\n" +
+			`{"index":{"_index":"books"}}` + "\n" + `{"title":"Emma"}` + "\n"
+		req := server.LastRequest()
+		if req.Path != "/_bulk" || string(req.Body) != wantBody {
+			t.Errorf("BulkCreate() sent %s with body\n%s\nwant /_bulk with body\n%s", req.Path, req.Body, wantBody)
+		}
+		if doc, ok := server.Document("books", "a"); !ok || doc["title"] != "Dune" {
+			t.Errorf("document a = %v, %v, want Dune", doc, ok)
+		}
+	})
+
+	t.Run("item errors", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		server.SetReadOnly("books", true)
+
+		err := client.BulkCreate(ctx, "books", []map[string]interface{}{{"title": "Dune"}})
+		if err == nil || !strings.Contains(err.Error(), "bulk operation had errors: cluster_block_exception: index [books] blocked") {
+			t.Errorf("BulkCreate() error = %v, want the item error", err)
+		}
+	})
+
+	t.Run("chunked", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		docs := make([]map[string]interface{}, 5)
+		for i := range docs {
+			docs[i] = map[string]interface{}{"n": i}
+		}
+
+		chunks, err := client.BulkCreateChunked(ctx, "books", docs, 2)
+		if err != nil {
+			t.Fatalf("BulkCreateChunked() error = %v", err)
+		}
+		if chunks != 3 || len(server.Requests()) != 3 {
+			t.Errorf("BulkCreateChunked() = %d chunks in %d requests, want 3", chunks, len(server.Requests()))
+		}
+	})
+}
+
+// TestIndexLifecycle_Fake tests the index requests against a fake cluster
+func TestIndexLifecycle_Fake(t *testing.T) {
+	client, _ := newFakeClient(t)
+	ctx := context.Background()
+
+	created, err := client.EnsureIndex(ctx, "books", nil)
+	if err != nil || !created {
+		t.Fatalf("EnsureIndex() = %v, %v, want true, nil", created, err)
+	}
+	created, err = client.EnsureIndex(ctx, "books", nil)
+	if err != nil || created {
+		t.Fatalf("EnsureIndex() of existing index = %v, %v, want false, nil", created, err)
+	}
+
+	exists, err := client.IndexExists(ctx, "books")
+	if err != nil || !exists {
+		t.Errorf("IndexExists() = %v, %v, want true, nil", exists, err)
+	}
+	if err := client.RefreshIndex(ctx, "books"); err != nil {
+		t.Errorf("RefreshIndex() error = %v", err)
+	}
+
+	deleted, err := client.DeleteIndexIfExists(ctx, "books")
+	if err != nil || !deleted {
+		t.Errorf("DeleteIndexIfExists() = %v, %v, want true, nil", deleted, err)
+	}
+	deleted, err = client.DeleteIndexIfExists(ctx, "books")
+	if err != nil || deleted {
+		t.Errorf("DeleteIndexIfExists() of missing index = %v, %v, want false, nil", deleted, err)
+	}
+	exists, err = client.IndexExists(ctx, "books")
+	if err != nil || exists {
+		t.Errorf("IndexExists() after delete = %v, %v, want false, nil", exists, err)
+	}
+}
+
+// celsius decodes temperatures written as strings such as "21.5C"
+type celsius float64
+
+func (c *celsius) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(s, "C") {
+		return fmt.Errorf("temperature %q has no C suffix", s)
+	}
+	var value float64
+	if _, err := fmt.Sscanf(strings.TrimSuffix(s, "C"), "%g", &value); err != nil {
+		return fmt.Errorf("invalid temperature %q: %w", s, err)
+	}
+	*c = celsius(value)
+	return nil
+}
+
+type sensorLocation struct {
+	Site string `json:"site"`
+	Room string `json:"room"`
+}
+
+type sensorReading struct {
+	sensorLocation
+	Sensor      string  `json:"sensor"`
+	Temperature celsius `json:"temperature"`
+}
+
+// TestDecodeDocuments_Fake tests decoding documents into caller structs
+func TestDecodeDocuments_Fake(t *testing.T) {
+	ctx := context.Background()
+	client, server := newFakeClient(t)
+	server.PutDocument("readings", "r1", map[string]interface{}{"sensor": "a", "site": "hq", "room": "101", "temperature": "21.5C"})
+	server.PutDocument("readings", "r2", map[string]interface{}{"sensor": "b", "site": "hq", "room": "102", "temperature": "19C"})
+	server.PutDocument("broken", "bad-1", map[string]interface{}{"sensor": "c", "temperature": "hot"})
+
+	t.Run("get", func(t *testing.T) {
+		var reading sensorReading
+		if err := client.GetDocumentAs(ctx, "readings", "r1", &reading); err != nil {
+			t.Fatalf("GetDocumentAs() error = %v", err)
+		}
+		want := sensorReading{sensorLocation: sensorLocation{Site: "hq", Room: "101"}, Sensor: "a", Temperature: 21.5}
+		if reading != want {
+			t.Errorf("GetDocumentAs() = %+v, want %+v", reading, want)
+		}
+	})
+
+	t.Run("get missing document", func(t *testing.T) {
+		var reading sensorReading
+		if err := client.GetDocumentAs(ctx, "readings", "nope", &reading); !errors.Is(err, ErrDocumentNotFound) {
+			t.Errorf("GetDocumentAs() error = %v, want ErrDocumentNotFound", err)
+		}
+	})
+
+	t.Run("get decode error names the document", func(t *testing.T) {
+		var reading sensorReading
+		err := client.GetDocumentAs(ctx, "broken", "bad-1", &reading)
+		if err == nil || !strings.Contains(err.Error(), "failed to decode document bad-1") {
+			t.Errorf("GetDocumentAs() error = %v, want a decode error naming bad-1", err)
+		}
+	})
+
+	t.Run("search into slice of structs", func(t *testing.T) {
+		readings := []sensorReading{{Sensor: "stale"}}
+		if err := client.SearchDocumentsAs(ctx, "readings", WithSort(MatchAllQuery(), "sensor", "asc"), &readings); err != nil {
+			t.Fatalf("SearchDocumentsAs() error = %v", err)
+		}
+		if len(readings) != 2 || readings[0].Sensor != "a" || readings[1].Room != "102" || readings[1].Temperature != 19 {
+			t.Errorf("SearchDocumentsAs() = %+v, want readings a and b", readings)
+		}
+	})
+
+	t.Run("search into slice of pointers", func(t *testing.T) {
+		var readings []*sensorReading
+		if err := client.SearchDocumentsAs(ctx, "readings", TermQuery("sensor", "b"), &readings); err != nil {
+			t.Fatalf("SearchDocumentsAs() error = %v", err)
+		}
+		if len(readings) != 1 || readings[0].Site != "hq" || readings[0].Temperature != 19 {
+			t.Errorf("SearchDocumentsAs() = %+v, want reading b", readings)
+		}
+	})
+
+	t.Run("search decode error names the document", func(t *testing.T) {
+		var readings []sensorReading
+		err := client.SearchDocumentsAs(ctx, "broken", MatchAllQuery(), &readings)
+		if err == nil || !strings.Contains(err.Error(), "failed to decode document bad-1") {
+			t.Errorf("SearchDocumentsAs() error = %v, want a decode error naming bad-1", err)
+		}
+	})
+
+	t.Run("search requires a pointer to a slice", func(t *testing.T) {
+		var reading sensorReading
+		for _, out := range []interface{}{nil, []sensorReading{}, &reading, (*[]sensorReading)(nil)} {
+			if err := client.SearchDocumentsAs(ctx, "readings", MatchAllQuery(), out); err == nil {
+				t.Errorf("SearchDocumentsAs(%T) succeeded, want an error", out)
+			}
+		}
+	})
+}
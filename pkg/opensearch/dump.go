@@ -0,0 +1,268 @@
+package opensearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// DumpOptions controls what DumpIndex writes and how it reports progress
+type DumpOptions struct {
+	// Query selects the documents to dump (default every document)
+	Query map[string]interface{}
+	// PageSize is the number of documents fetched per request (default 500)
+	PageSize int
+	// Progress, when set, is called after each page is written with the number of
+	// documents written so far and the number of matching documents
+	Progress func(written, total int)
+}
+
+// dumpLine is one line of a DumpIndex dump
+type dumpLine struct {
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// DumpIndex writes the documents of index to w as newline-delimited JSON, one
+// {"_id": ..., "_source": ...} object per line. Documents are read a page at a time
+// within a point in time, like SearchIterator, and written as they arrive, so memory use
+// does not grow with the size of the index. Sources are written as returned by OpenSearch,
+// so large numbers keep their precision. On error, w may hold a partial dump.
+func (c *Client) DumpIndex(ctx context.Context, index string, w io.Writer, opts DumpOptions) (err error) {
+	defer c.observe("DumpIndex", time.Now(), &err)
+
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultScrollSize
+	}
+	query := opts.Query
+	if query == nil {
+		query = MatchAllQuery()
+	}
+
+	it := c.SearchIterator(ctx, index, query, pageSize)
+	defer it.Close()
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	written := 0
+	for it.Next() {
+		if err := enc.Encode(dumpLine{ID: it.raw.ID, Source: it.raw.Source}); err != nil {
+			return fmt.Errorf("failed to write document %s: %w", it.raw.ID, err)
+		}
+		written++
+		if opts.Progress != nil && len(it.page) == 0 {
+			opts.Progress(written, it.Total())
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("failed to dump index %s after %d documents: %w", index, written, err)
+	}
+	return nil
+}
+
+const (
+	// defaultLoadBatchSize is the number of documents LoadIndex sends per bulk request
+	defaultLoadBatchSize = 500
+	// maxLoadErrors is the number of error reasons a LoadResult keeps
+	maxLoadErrors = 10
+)
+
+// LoadOptions controls how LoadIndex reads and indexes documents
+type LoadOptions struct {
+	// BatchSize is the number of documents sent per bulk request (default 500)
+	BatchSize int
+	// SkipMalformed skips lines that are not a JSON object instead of stopping the load
+	SkipMalformed bool
+	// Refresh is the refresh parameter of each bulk request, e.g. "wait_for" (default none)
+	Refresh string
+}
+
+// LoadResult counts the documents handled by LoadIndex
+type LoadResult struct {
+	Indexed int
+	Failed  int
+	// Skipped is the number of malformed lines skipped with SkipMalformed
+	Skipped int
+	// Errors holds the reasons of the first failed documents and skipped lines
+	Errors []string
+}
+
+// addError keeps the reason of a failed document or skipped line while there is room
+func (r *LoadResult) addError(reason string) {
+	if len(r.Errors) < maxLoadErrors {
+		r.Errors = append(r.Errors, reason)
+	}
+}
+
+// LoadIndex indexes the newline-delimited JSON documents read from r into index. Lines can
+// be in the DumpIndex format, {"_id": ..., "_source": ...}, or plain document sources; an
+// "_id" is kept as the document ID, and documents without one get a generated ID. Blank
+// lines are ignored. Documents are sent in bulk requests of opts.BatchSize, so memory use
+// does not grow with the size of the input, and their sources are sent as read, so large
+// numbers keep their precision.
+//
+// Documents rejected by the bulk API are counted as failed and do not stop the load. A
+// malformed line stops it with an error unless opts.SkipMalformed is set; so does a failed
+// bulk request or read. The result counts what was done until then.
+func (c *Client) LoadIndex(ctx context.Context, index string, r io.Reader, opts LoadOptions) (_ LoadResult, err error) {
+	defer c.observe("LoadIndex", time.Now(), &err)
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultLoadBatchSize
+	}
+
+	var result LoadResult
+	var buf bytes.Buffer
+	docs := 0
+	send := func() error {
+		if docs == 0 {
+			return nil
+		}
+		err := c.loadBatch(ctx, &buf, docs, opts.Refresh, &result)
+		buf.Reset()
+		docs = 0
+		return err
+	}
+
+	reader := bufio.NewReader(r)
+	for lineNo := 1; ; lineNo++ {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return result, fmt.Errorf("failed to read line %d: %w", lineNo, readErr)
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			id, source, err := parseLoadLine(line)
+			if err != nil {
+				err = fmt.Errorf("line %d: %w", lineNo, err)
+				if !opts.SkipMalformed {
+					return result, err
+				}
+				result.Skipped++
+				result.addError(err.Error())
+			} else {
+				if err := writeBulkRawIndexAction(&buf, index, id, source); err != nil {
+					return result, err
+				}
+				docs++
+				if docs == batchSize {
+					if err := send(); err != nil {
+						return result, err
+					}
+				}
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	if err := send(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// parseLoadLine returns the document ID, which may be empty, and source of a LoadIndex line
+func parseLoadLine(line []byte) (string, json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil || fields == nil {
+		return "", nil, fmt.Errorf("not a JSON object")
+	}
+
+	var id string
+	if raw, ok := fields["_id"]; ok {
+		if err := json.Unmarshal(raw, &id); err != nil {
+			return "", nil, fmt.Errorf("_id is not a string")
+		}
+	}
+
+	if source, ok := fields["_source"]; ok {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(source, &object); err != nil || object == nil {
+			return "", nil, fmt.Errorf("_source is not a JSON object")
+		}
+		return id, source, nil
+	}
+
+	// A plain source: _id is metadata and cannot be part of the document
+	if _, ok := fields["_id"]; !ok {
+		return "", line, nil
+	}
+	delete(fields, "_id")
+	source, err := json.Marshal(fields)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal document: %w", err)
+	}
+	return id, source, nil
+}
+
+// writeBulkRawIndexAction appends the action and document lines that index source under
+// id, or a generated ID when id is empty, to buf
+func writeBulkRawIndexAction(buf *bytes.Buffer, index, id string, source json.RawMessage) error {
+	meta := map[string]interface{}{"_index": index}
+	if id != "" {
+		meta["_id"] = id
+	}
+	action, err := json.Marshal(map[string]interface{}{"index": meta})
+	if err != nil {
+		return fmt.Errorf("failed to marshal bulk action: %w", err)
+	}
+
+	buf.Write(action)
+	buf.WriteByte('\n')
+	buf.Write(source)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// loadBatch sends the docs documents in body as one bulk request and adds the outcome of
+// each document to result
+func (c *Client) loadBatch(ctx context.Context, body *bytes.Buffer, docs int, refresh string, result *LoadResult) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("load aborted after %d documents: %w", result.Indexed+result.Failed, err)
+	}
+
+	req := opensearchapi.BulkRequest{
+		Body:    body,
+		Refresh: refresh,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to perform bulk operation: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return requestError("bulk", res)
+	}
+
+	var response BulkResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, item := range response.Items {
+		for _, op := range item {
+			if op.Error.Type == "" {
+				continue
+			}
+			failed++
+			result.addError(fmt.Sprintf("document %s: %s: %s", op.ID, op.Error.Type, op.Error.Reason))
+		}
+	}
+	result.Indexed += docs - failed
+	result.Failed += failed
+	return nil
+}
@@ -0,0 +1,334 @@
+package opensearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/yenonn/go-opensearch/pkg/opensearch/internal/fakeos"
+)
+
+// failingWriter fails every write after the first n
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("disk full")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestDumpIndex(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dumps every document", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		for i := 0; i < 1000; i++ {
+			server.PutDocument("books", fmt.Sprintf("book-%d", i), map[string]interface{}{
+				"title": fmt.Sprintf("Book %d", i),
+				"pages": i,
+				"tags":  []interface{}{"fiction", fmt.Sprintf("shelf-%d", i%10)},
+			})
+		}
+
+		var progress [][2]int
+		var buf bytes.Buffer
+		err := client.DumpIndex(ctx, "books", &buf, DumpOptions{
+			PageSize: 300,
+			Progress: func(written, total int) {
+				progress = append(progress, [2]int{written, total})
+			},
+		})
+		if err != nil {
+			t.Fatalf("DumpIndex() error = %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != 1000 {
+			t.Fatalf("DumpIndex() wrote %d lines, want 1000", len(lines))
+		}
+
+		ids := make(map[string]bool, len(lines))
+		for _, line := range lines {
+			var doc struct {
+				ID     string                 `json:"_id"`
+				Source map[string]interface{} `json:"_source"`
+			}
+			if err := json.Unmarshal([]byte(line), &doc); err != nil {
+				t.Fatalf("line %q is not a JSON document: %v", line, err)
+			}
+			ids[doc.ID] = true
+
+			if doc.ID == "book-427" {
+				want, _ := server.Document("books", "book-427")
+				if !reflect.DeepEqual(doc.Source, want) {
+					t.Errorf("book-427 source = %v, want %v", doc.Source, want)
+				}
+			}
+		}
+		if len(ids) != 1000 {
+			t.Errorf("DumpIndex() wrote %d distinct documents, want 1000", len(ids))
+		}
+
+		wantProgress := [][2]int{{300, 1000}, {600, 1000}, {900, 1000}, {1000, 1000}}
+		if !reflect.DeepEqual(progress, wantProgress) {
+			t.Errorf("progress = %v, want %v", progress, wantProgress)
+		}
+		if open := server.OpenPointsInTime(); open != 0 {
+			t.Errorf("%d points in time left open, want 0", open)
+		}
+	})
+
+	t.Run("query filter", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		for i := 0; i < 20; i++ {
+			server.PutDocument("books", fmt.Sprintf("book-%d", i), map[string]interface{}{"even": i%2 == 0})
+		}
+
+		var buf bytes.Buffer
+		if err := client.DumpIndex(ctx, "books", &buf, DumpOptions{Query: TermQuery("even", true)}); err != nil {
+			t.Fatalf("DumpIndex() error = %v", err)
+		}
+
+		count := 0
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var doc struct {
+				ID     string                 `json:"_id"`
+				Source map[string]interface{} `json:"_source"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+				t.Fatalf("line %q is not a JSON document: %v", scanner.Text(), err)
+			}
+			if doc.Source["even"] != true {
+				t.Errorf("DumpIndex() wrote %s, which does not match the query", doc.ID)
+			}
+			count++
+		}
+		if count != 10 {
+			t.Errorf("DumpIndex() wrote %d lines, want 10", count)
+		}
+	})
+
+	t.Run("sources as returned", func(t *testing.T) {
+		// 405 to opening the point in time falls back to search_after on the live index
+		stub := &stubTransport{
+			statuses: []int{http.StatusMethodNotAllowed},
+			status:   http.StatusOK,
+			body: `{"hits":{"total":{"value":1},"hits":[` +
+				`{"_id":"1","_source":{"views": 9007199254740993, "price": 1.10, "tag": "<b>"}}]}}`,
+		}
+		client := newStubClient(t, stub)
+
+		var buf bytes.Buffer
+		if err := client.DumpIndex(ctx, "books", &buf, DumpOptions{}); err != nil {
+			t.Fatalf("DumpIndex() error = %v", err)
+		}
+
+		want := `{"_id":"1","_source":{"views":9007199254740993,"price":1.10,"tag":"<b>"}}` + "\n"
+		if got := buf.String(); got != want {
+			t.Errorf("DumpIndex() wrote %q, want %q", got, want)
+		}
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "books", 50)
+
+		err := client.DumpIndex(ctx, "books", &failingWriter{n: 5}, DumpOptions{PageSize: 10})
+		if err == nil || !strings.Contains(err.Error(), "disk full") {
+			t.Fatalf("DumpIndex() error = %v, want the write error", err)
+		}
+		if open := server.OpenPointsInTime(); open != 0 {
+			t.Errorf("%d points in time left open, want 0", open)
+		}
+	})
+
+	t.Run("search error", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "books", 50)
+		server.Fail(func(r fakeos.Request) bool {
+			return bytes.Contains(r.Body, []byte("search_after"))
+		}, http.StatusInternalServerError, "search_phase_execution_exception")
+
+		var buf bytes.Buffer
+		err := client.DumpIndex(ctx, "books", &buf, DumpOptions{PageSize: 20})
+		var osErr *OpenSearchError
+		if !errors.As(err, &osErr) || osErr.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("DumpIndex() error = %v, want the search error", err)
+		}
+		if !strings.Contains(err.Error(), "after 20 documents") {
+			t.Errorf("DumpIndex() error = %v, want it to say how far the dump got", err)
+		}
+	})
+}
+
+func TestLoadIndex(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("round trip", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		for i := 0; i < 1000; i++ {
+			server.PutDocument("books", fmt.Sprintf("book-%d", i), map[string]interface{}{
+				"title": fmt.Sprintf("Book %d", i),
+				"pages": i,
+			})
+		}
+
+		var dump bytes.Buffer
+		if err := client.DumpIndex(ctx, "books", &dump, DumpOptions{}); err != nil {
+			t.Fatalf("DumpIndex() error = %v", err)
+		}
+		original := dump.String()
+
+		result, err := client.LoadIndex(ctx, "books-copy", &dump, LoadOptions{BatchSize: 300})
+		if err != nil {
+			t.Fatalf("LoadIndex() error = %v", err)
+		}
+		if !reflect.DeepEqual(result, LoadResult{Indexed: 1000}) {
+			t.Errorf("LoadIndex() = %+v, want 1000 indexed", result)
+		}
+
+		bulks := 0
+		for _, r := range server.Requests() {
+			if r.Path == "/_bulk" {
+				bulks++
+			}
+		}
+		if bulks != 4 {
+			t.Errorf("LoadIndex() sent %d bulk requests, want 4", bulks)
+		}
+
+		for _, id := range []string{"book-0", "book-517", "book-999"} {
+			want, _ := server.Document("books", id)
+			got, ok := server.Document("books-copy", id)
+			if !ok || !reflect.DeepEqual(got, want) {
+				t.Errorf("loaded %s = %v, want %v", id, got, want)
+			}
+		}
+
+		var copied bytes.Buffer
+		if err := client.DumpIndex(ctx, "books-copy", &copied, DumpOptions{}); err != nil {
+			t.Fatalf("DumpIndex() of the copy error = %v", err)
+		}
+		if got, want := sortedLines(copied.String()), sortedLines(original); !reflect.DeepEqual(got, want) {
+			t.Errorf("the copy has %d documents, want the %d of the original", len(got), len(want))
+		}
+	})
+
+	t.Run("plain sources", func(t *testing.T) {
+		client, server := newFakeClient(t)
+
+		input := `{"_id":"a","title":"With ID"}
+
+{"title":"Without ID","views":9007199254740993}
+`
+		result, err := client.LoadIndex(ctx, "books", strings.NewReader(input), LoadOptions{})
+		if err != nil {
+			t.Fatalf("LoadIndex() error = %v", err)
+		}
+		if result.Indexed != 2 {
+			t.Errorf("LoadIndex() = %+v, want 2 indexed", result)
+		}
+
+		doc, ok := server.Document("books", "a")
+		if !ok || !reflect.DeepEqual(doc, map[string]interface{}{"title": "With ID"}) {
+			t.Errorf("document a = %v, want its source without _id", doc)
+		}
+		if body := string(server.LastRequest().Body); !strings.Contains(body, `"views":9007199254740993`) {
+			t.Errorf("bulk body = %s, want the source as read", body)
+		}
+	})
+
+	t.Run("malformed lines", func(t *testing.T) {
+		input := `{"_id":"1","_source":{"title":"Go"}}
+not json
+{"_id":2,"title":"Numeric ID"}
+{"_id":"3","_source":"text"}
+{"_id":"4","title":"Rust"}
+`
+		client, server := newFakeClient(t)
+		result, err := client.LoadIndex(ctx, "books", strings.NewReader(input), LoadOptions{})
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Fatalf("LoadIndex() error = %v, want an error for line 2", err)
+		}
+		if result.Indexed != 0 {
+			t.Errorf("LoadIndex() = %+v, want nothing indexed", result)
+		}
+		if _, ok := server.Document("books", "1"); ok {
+			t.Error("LoadIndex() indexed documents before the malformed line, want a fatal error to stop the load")
+		}
+
+		client, server = newFakeClient(t)
+		result, err = client.LoadIndex(ctx, "books", strings.NewReader(input), LoadOptions{SkipMalformed: true})
+		if err != nil {
+			t.Fatalf("LoadIndex() with SkipMalformed error = %v", err)
+		}
+		want := LoadResult{
+			Indexed: 2,
+			Skipped: 3,
+			Errors: []string{
+				"line 2: not a JSON object",
+				"line 3: _id is not a string",
+				"line 4: _source is not a JSON object",
+			},
+		}
+		if !reflect.DeepEqual(result, want) {
+			t.Errorf("LoadIndex() = %+v, want %+v", result, want)
+		}
+		if _, ok := server.Document("books", "4"); !ok {
+			t.Error("LoadIndex() did not index the document after the malformed lines")
+		}
+	})
+
+	t.Run("failed documents", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		server.CreateIndex("books")
+		server.SetReadOnly("books", true)
+
+		var input strings.Builder
+		for i := 0; i < 25; i++ {
+			fmt.Fprintf(&input, `{"_id":"%d","n":%d}`+"\n", i, i)
+		}
+
+		result, err := client.LoadIndex(ctx, "books", strings.NewReader(input.String()), LoadOptions{BatchSize: 10})
+		if err != nil {
+			t.Fatalf("LoadIndex() error = %v", err)
+		}
+		if result.Indexed != 0 || result.Failed != 25 {
+			t.Errorf("LoadIndex() = %d indexed, %d failed, want 0 and 25", result.Indexed, result.Failed)
+		}
+		if len(result.Errors) != maxLoadErrors || !strings.HasPrefix(result.Errors[0], "document 0: cluster_block_exception") {
+			t.Errorf("LoadIndex() errors = %v, want the first %d reasons", result.Errors, maxLoadErrors)
+		}
+	})
+
+	t.Run("bulk request error", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		server.Fail(func(r fakeos.Request) bool { return r.Path == "/_bulk" }, http.StatusServiceUnavailable, "unavailable_shards_exception")
+
+		_, err := client.LoadIndex(ctx, "books", strings.NewReader(`{"title":"Go"}`), LoadOptions{})
+		var osErr *OpenSearchError
+		if !errors.As(err, &osErr) || osErr.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("LoadIndex() error = %v, want the bulk error", err)
+		}
+	})
+}
+
+// sortedLines returns the non-empty lines of s in sorted order
+func sortedLines(s string) []string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	sort.Strings(lines)
+	return lines
+}
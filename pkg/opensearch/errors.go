@@ -0,0 +1,163 @@
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// ErrDocumentNotFound is returned when the requested document does not exist
+var ErrDocumentNotFound = errors.New("document not found")
+
+// ErrIndexNotFound is returned when the index a request names does not exist
+var ErrIndexNotFound = errors.New("index not found")
+
+// ErrIndexAlreadyExists is returned by CreateIndex when the index already exists
+var ErrIndexAlreadyExists = errors.New("index already exists")
+
+// ErrVersionConflict is returned when a conditional write is rejected because
+// the document was modified concurrently
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrCircuitOpen is returned without sending the request while the circuit breaker of
+// the node it would be sent to is open (see CircuitBreakerConfig)
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// ErrUnauthorized is returned by WaitForReady when the cluster rejects the client's
+// credentials with 401 or 403
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ErrSnapshotNotFound is returned when the snapshot a request names does not exist
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// ErrISMPolicyNotFound is returned by GetISMPolicy and DeleteISMPolicy when the ISM
+// policy does not exist
+var ErrISMPolicyNotFound = errors.New("ISM policy not found")
+
+// maxErrorBodySize caps how much of an error response body is read
+const maxErrorBodySize = 64 << 10
+
+// errorTypeCauses maps OpenSearch exception types to the sentinel errors they match
+var errorTypeCauses = map[string]error{
+	"document_missing_exception":        ErrDocumentNotFound,
+	"index_not_found_exception":         ErrIndexNotFound,
+	"resource_already_exists_exception": ErrIndexAlreadyExists,
+	"snapshot_missing_exception":        ErrSnapshotNotFound,
+	"version_conflict_engine_exception": ErrVersionConflict,
+}
+
+// OpenSearchError is the error returned for a request that OpenSearch answered with an
+// error status. Use errors.As to inspect why a request failed:
+//
+//	var osErr *opensearch.OpenSearchError
+//	if errors.As(err, &osErr) && osErr.Type == "illegal_argument_exception" {
+//		...
+//	}
+type OpenSearchError struct {
+	StatusCode int
+	// Type is the OpenSearch exception type, e.g. "index_not_found_exception". It is empty
+	// when the response body is not an OpenSearch error, e.g. from a proxy.
+	Type string
+	// Reason is the reason given by OpenSearch, or the raw response body when it is not JSON
+	Reason    string
+	RootCause []ErrorCause
+	// Index is the index the error concerns, if any
+	Index string
+
+	message string
+	// cause is a sentinel such as ErrVersionConflict the error matches with errors.Is
+	cause error
+}
+
+// Error returns the error message
+func (e *OpenSearchError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the sentinel error matching the failure, if any
+func (e *OpenSearchError) Unwrap() error {
+	return e.cause
+}
+
+// requestError builds an error for a failed request, including the reason
+// reported by the server when the response body carries one
+func requestError(action string, res *opensearchapi.Response) error {
+	err := parseOpenSearchError(res)
+	err.message = fmt.Sprintf("%s request failed with status: %s", action, res.Status())
+	if err.Reason != "" {
+		err.message += ": " + err.Reason
+	}
+	return err
+}
+
+// responseError builds an error with the given message for a failed request, such as
+// "index not found", matching cause (if not nil) with errors.Is
+func responseError(res *opensearchapi.Response, message string, cause error) error {
+	err := parseOpenSearchError(res)
+	err.message = message
+	if cause != nil {
+		err.cause = cause
+	}
+	return err
+}
+
+// notFoundError builds the error for a 404 response to a request for the document id in
+// index, telling a missing index apart from a missing document
+func notFoundError(res *opensearchapi.Response, index, id string) error {
+	err := parseOpenSearchError(res)
+	if err.cause == ErrIndexNotFound {
+		err.message = fmt.Sprintf("index %s not found", index)
+		return err
+	}
+	err.message = fmt.Sprintf("document %s not found in index %s", id, index)
+	err.cause = ErrDocumentNotFound
+	return err
+}
+
+// parseOpenSearchError reads the error body of res. A body that is not an OpenSearch
+// error is kept as the reason.
+func parseOpenSearchError(res *opensearchapi.Response) *OpenSearchError {
+	osErr := &OpenSearchError{StatusCode: res.StatusCode}
+	if res.Body == nil {
+		return osErr
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	if err != nil || len(bytes.TrimSpace(body)) == 0 {
+		return osErr
+	}
+
+	var response ErrorResponse
+	if err := json.Unmarshal(body, &response); err != nil || response.Error.Type == "" {
+		// JSON that is not an error, such as the body of a get of a missing document,
+		// has no reason to give
+		if !json.Valid(body) {
+			osErr.Reason = strings.TrimSpace(string(body))
+		}
+		return osErr
+	}
+
+	return newOpenSearchError(res.StatusCode, response.Error.Type, response.Error.Reason, response.Error.Index, response.Error.RootCause)
+}
+
+// newOpenSearchError builds the error for an OpenSearch error object, such as the error of
+// a whole response or of one item of a multi search
+func newOpenSearchError(status int, errType, reason, index string, rootCause []ErrorCause) *OpenSearchError {
+	osErr := &OpenSearchError{
+		StatusCode: status,
+		Type:       errType,
+		Reason:     reason,
+		RootCause:  rootCause,
+		Index:      index,
+	}
+	if osErr.Index == "" && len(osErr.RootCause) > 0 {
+		osErr.Index = osErr.RootCause[0].Index
+	}
+	osErr.cause = errorTypeCauses[osErr.Type]
+	return osErr
+}
@@ -0,0 +1,260 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultHealthCheckInterval is the time between health checks of a FailoverClient when
+// FailoverOptions.HealthCheckInterval is 0
+const defaultHealthCheckInterval = 10 * time.Second
+
+// WriteMode controls which clusters a FailoverClient sends writes to
+type WriteMode int
+
+const (
+	// WritePrimaryOnly sends writes to the primary cluster only, failing while it is down
+	WritePrimaryOnly WriteMode = iota
+	// WriteMirrored sends writes to every cluster, failing if any of them fails
+	WriteMirrored
+)
+
+// FailoverEvent describes a change of the cluster a FailoverClient reads from
+type FailoverEvent struct {
+	// From and To are the positions of the clusters in the list given to NewFailoverClient
+	From int
+	To   int
+	// Failback is true when reads return to a cluster of higher priority that has recovered
+	Failback bool
+}
+
+// FailoverOptions configures a FailoverClient
+type FailoverOptions struct {
+	// HealthCheckInterval is the time between pings of every cluster (default 10s)
+	HealthCheckInterval time.Duration
+	// WriteMode selects primary-only (the default) or mirrored writes
+	WriteMode WriteMode
+	// OnFailover, when set, is called whenever reads move to another cluster
+	OnFailover func(event FailoverEvent)
+}
+
+// FailoverClient spreads requests over clusters given in priority order, e.g. an active
+// cluster and a standby in another region. Reads go to the healthiest cluster of highest
+// priority: when a read fails and its cluster no longer answers pings, the cluster is marked
+// down and the read is retried on the next one. A background health check marks clusters
+// up again, so reads fail back once the primary recovers.
+type FailoverClient struct {
+	clients []*Client
+	opts    FailoverOptions
+
+	mu      sync.Mutex
+	healthy []bool
+	active  int
+
+	// cancel stops the health checks, interrupting a ping in flight
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewFailoverClient creates a FailoverClient over clients, the first being the primary,
+// and starts its health checks. Call Close to stop them.
+func NewFailoverClient(clients []*Client, opts FailoverOptions) (*FailoverClient, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("at least one client is required")
+	}
+	if opts.HealthCheckInterval <= 0 {
+		opts.HealthCheckInterval = defaultHealthCheckInterval
+	}
+
+	healthy := make([]bool, len(clients))
+	for i := range healthy {
+		healthy[i] = true
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &FailoverClient{
+		clients: clients,
+		opts:    opts,
+		healthy: healthy,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go f.healthLoop(ctx)
+
+	return f, nil
+}
+
+// Close stops the health checks, cancelling a health check in progress, and waits for
+// them to finish
+func (f *FailoverClient) Close() {
+	f.cancel()
+	<-f.done
+}
+
+// Active returns the position of the cluster reads are currently sent to
+func (f *FailoverClient) Active() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// Read runs fn against the active cluster, failing over to the next healthy cluster when
+// the active one turns out to be down. Only a transport error, ErrCircuitOpen or a 5xx
+// response has the cluster pinged to tell whether it is down; other errors, such as a
+// missing document, and errors from a cluster that still answers pings are returned as
+// is, since another cluster would not do better.
+func (f *FailoverClient) Read(ctx context.Context, fn func(client *Client) error) error {
+	var err error
+	for _, i := range f.readOrder() {
+		if err = fn(f.clients[i]); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil || !clusterFailure(err) {
+			return err
+		}
+		if pingErr := f.clients[i].Ping(ctx); pingErr == nil {
+			return err
+		}
+		f.setHealthy(i, false)
+	}
+	return err
+}
+
+// clusterFailure reports whether err may come from the cluster being down, rather than
+// from a request the cluster answered
+func clusterFailure(err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return true
+	}
+	var osErr *OpenSearchError
+	if errors.As(err, &osErr) {
+		return osErr.StatusCode >= 500
+	}
+	return true
+}
+
+// Write runs fn against the primary cluster, or against every cluster with WriteMirrored
+func (f *FailoverClient) Write(ctx context.Context, fn func(client *Client) error) error {
+	if f.opts.WriteMode != WriteMirrored {
+		return fn(f.clients[0])
+	}
+
+	var errs []error
+	for i, client := range f.clients {
+		if err := fn(client); err != nil {
+			errs = append(errs, fmt.Errorf("cluster %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetDocument retrieves a document from the active cluster
+func (f *FailoverClient) GetDocument(ctx context.Context, index, id string, opts ...DocumentOption) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	err := f.Read(ctx, func(client *Client) error {
+		var err error
+		doc, err = client.GetDocument(ctx, index, id, opts...)
+		return err
+	})
+	return doc, err
+}
+
+// SearchDocuments searches the active cluster
+func (f *FailoverClient) SearchDocuments(ctx context.Context, index string, query map[string]interface{}) ([]map[string]interface{}, error) {
+	var results []map[string]interface{}
+	err := f.Read(ctx, func(client *Client) error {
+		var err error
+		results, err = client.SearchDocuments(ctx, index, query)
+		return err
+	})
+	return results, err
+}
+
+// CreateDocument indexes a document according to the write mode
+func (f *FailoverClient) CreateDocument(ctx context.Context, index, id string, document interface{}, opts ...DocumentOption) error {
+	return f.Write(ctx, func(client *Client) error {
+		return client.CreateDocument(ctx, index, id, document, opts...)
+	})
+}
+
+// DeleteDocument deletes a document according to the write mode
+func (f *FailoverClient) DeleteDocument(ctx context.Context, index, id string, opts ...DocumentOption) error {
+	return f.Write(ctx, func(client *Client) error {
+		return client.DeleteDocument(ctx, index, id, opts...)
+	})
+}
+
+// readOrder returns the positions of the healthy clusters in priority order, or of every
+// cluster when none is healthy
+func (f *FailoverClient) readOrder() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	order := make([]int, 0, len(f.clients))
+	for i, healthy := range f.healthy {
+		if healthy {
+			order = append(order, i)
+		}
+	}
+	if len(order) == 0 {
+		for i := range f.clients {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// setHealthy records the health of a cluster and moves reads to the first healthy cluster,
+// calling OnFailover when that changes
+func (f *FailoverClient) setHealthy(i int, healthy bool) {
+	f.mu.Lock()
+	f.healthy[i] = healthy
+
+	from := f.active
+	for j, ok := range f.healthy {
+		if ok {
+			f.active = j
+			break
+		}
+	}
+	to := f.active
+	f.mu.Unlock()
+
+	if from != to && f.opts.OnFailover != nil {
+		f.opts.OnFailover(FailoverEvent{From: from, To: to, Failback: to < from})
+	}
+}
+
+// healthLoop pings every cluster each HealthCheckInterval until ctx is cancelled by Close
+func (f *FailoverClient) healthLoop(ctx context.Context) {
+	defer close(f.done)
+
+	ticker := time.NewTicker(f.opts.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.checkHealth(ctx)
+		}
+	}
+}
+
+// checkHealth pings every cluster, giving each at most one interval to answer. A ping cut
+// short by Close leaves the cluster's state as it was.
+func (f *FailoverClient) checkHealth(ctx context.Context) {
+	for i, client := range f.clients {
+		pingCtx, cancel := context.WithTimeout(ctx, f.opts.HealthCheckInterval)
+		err := client.Ping(pingCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		f.setHealthy(i, err == nil)
+	}
+}
@@ -0,0 +1,305 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// clusterStub answers every request with a document naming the cluster while it is up,
+// and refuses connections while it is down
+type clusterStub struct {
+	name     string
+	down     atomic.Bool
+	requests atomic.Int32
+	writes   atomic.Int32
+	// status, when set, answers every request with that status and an error body
+	status atomic.Int32
+}
+
+func (s *clusterStub) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.requests.Add(1)
+	if s.down.Load() {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	}
+	if status := int(s.status.Load()); status != 0 {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"_id":"1","found":false,"status":%d}`, status))),
+			Request:    req,
+		}, nil
+	}
+	if req.Method == http.MethodPut || req.Method == http.MethodDelete {
+		s.writes.Add(1)
+	}
+
+	body := fmt.Sprintf(`{"_id":"1","found":true,"result":"created","_source":{"cluster":%q},"hits":{"hits":[{"_id":"1","_source":{"cluster":%q}}]}}`, s.name, s.name)
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// newFailoverStubs creates a FailoverClient over a stub primary and secondary cluster
+func newFailoverStubs(t *testing.T, opts FailoverOptions) (*FailoverClient, *clusterStub, *clusterStub) {
+	t.Helper()
+
+	primary := &clusterStub{name: "primary"}
+	secondary := &clusterStub{name: "secondary"}
+
+	clients := make([]*Client, 0, 2)
+	for _, stub := range []*clusterStub{primary, secondary} {
+		client, err := newClient(Config{
+			Addresses:    []string{"http://" + stub.name + ":9200"},
+			DisableRetry: true,
+		}, stub)
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+		clients = append(clients, client)
+	}
+
+	failover, err := NewFailoverClient(clients, opts)
+	if err != nil {
+		t.Fatalf("NewFailoverClient() error = %v", err)
+	}
+	t.Cleanup(failover.Close)
+
+	return failover, primary, secondary
+}
+
+// readCluster returns the name of the cluster a GetDocument was answered by
+func readCluster(t *testing.T, failover *FailoverClient) string {
+	t.Helper()
+
+	doc, err := failover.GetDocument(context.Background(), "my-index", "1")
+	if err != nil {
+		t.Fatalf("GetDocument() error = %v", err)
+	}
+	return doc["cluster"].(string)
+}
+
+func TestFailoverClient_FailsOverReads(t *testing.T) {
+	var mu sync.Mutex
+	var events []FailoverEvent
+	failover, primary, _ := newFailoverStubs(t, FailoverOptions{
+		HealthCheckInterval: time.Hour,
+		OnFailover: func(event FailoverEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+		},
+	})
+
+	if got := readCluster(t, failover); got != "primary" {
+		t.Fatalf("Read from %s, want primary", got)
+	}
+
+	primary.down.Store(true)
+	if got := readCluster(t, failover); got != "secondary" {
+		t.Errorf("Read after the primary went down from %s, want secondary", got)
+	}
+	if failover.Active() != 1 {
+		t.Errorf("Active() = %d, want 1", failover.Active())
+	}
+
+	// Once marked down, the primary is skipped rather than tried first
+	before := primary.requests.Load()
+	results, err := failover.SearchDocuments(context.Background(), "my-index", MatchAllQuery())
+	if err != nil || len(results) != 1 || results[0]["cluster"] != "secondary" {
+		t.Errorf("SearchDocuments() = %v, %v, want the secondary's document", results, err)
+	}
+	if primary.requests.Load() != before {
+		t.Error("SearchDocuments() tried the primary while it was marked down")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 || events[0] != (FailoverEvent{From: 0, To: 1}) {
+		t.Errorf("OnFailover events = %+v, want one failover from 0 to 1", events)
+	}
+}
+
+func TestFailoverClient_Failback(t *testing.T) {
+	failedBack := make(chan FailoverEvent, 1)
+	failover, primary, _ := newFailoverStubs(t, FailoverOptions{
+		HealthCheckInterval: 5 * time.Millisecond,
+		OnFailover: func(event FailoverEvent) {
+			if event.Failback {
+				failedBack <- event
+			}
+		},
+	})
+
+	primary.down.Store(true)
+	if got := readCluster(t, failover); got != "secondary" {
+		t.Fatalf("Read after the primary went down from %s, want secondary", got)
+	}
+
+	primary.down.Store(false)
+	select {
+	case event := <-failedBack:
+		if event.From != 1 || event.To != 0 {
+			t.Errorf("Failback event = %+v, want from 1 to 0", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Reads did not fail back after the primary recovered")
+	}
+
+	if got := readCluster(t, failover); got != "primary" {
+		t.Errorf("Read after failback from %s, want primary", got)
+	}
+}
+
+func TestFailoverClient_RequestErrorDoesNotFailOver(t *testing.T) {
+	failover, _, secondary := newFailoverStubs(t, FailoverOptions{HealthCheckInterval: time.Hour})
+
+	errBad := fmt.Errorf("bad request")
+	calls := 0
+	err := failover.Read(context.Background(), func(client *Client) error {
+		calls++
+		return errBad
+	})
+	if err != errBad || calls != 1 {
+		t.Errorf("Read() = %v after %d calls, want the request's error after 1", err, calls)
+	}
+	if failover.Active() != 0 || secondary.requests.Load() != 0 {
+		t.Error("Read() failed over although the primary still answers pings")
+	}
+}
+
+func TestFailoverClient_ClientErrorSendsNoPing(t *testing.T) {
+	failover, primary, secondary := newFailoverStubs(t, FailoverOptions{HealthCheckInterval: time.Hour})
+
+	primary.status.Store(404)
+	_, err := failover.GetDocument(context.Background(), "my-index", "1")
+	if !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("GetDocument() error = %v, want ErrDocumentNotFound", err)
+	}
+	if got := primary.requests.Load(); got != 1 {
+		t.Errorf("primary got %d requests, want the read alone without a ping", got)
+	}
+	if failover.Active() != 0 || secondary.requests.Load() != 0 {
+		t.Error("GetDocument() failed over on a missing document")
+	}
+}
+
+func TestFailoverClient_ServerErrorFailsOver(t *testing.T) {
+	failover, primary, _ := newFailoverStubs(t, FailoverOptions{HealthCheckInterval: time.Hour})
+
+	// The primary answers every request, pings included, with a 503
+	primary.status.Store(503)
+	if got := readCluster(t, failover); got != "secondary" {
+		t.Errorf("Read after the primary started failing from %s, want secondary", got)
+	}
+	if failover.Active() != 1 {
+		t.Errorf("Active() = %d, want 1", failover.Active())
+	}
+}
+
+func TestFailoverClient_Writes(t *testing.T) {
+	ctx := context.Background()
+	doc := map[string]interface{}{"title": "one"}
+
+	t.Run("Primary only", func(t *testing.T) {
+		failover, primary, secondary := newFailoverStubs(t, FailoverOptions{HealthCheckInterval: time.Hour})
+
+		if err := failover.CreateDocument(ctx, "my-index", "1", doc); err != nil {
+			t.Fatalf("CreateDocument() error = %v", err)
+		}
+		if primary.writes.Load() != 1 || secondary.writes.Load() != 0 {
+			t.Errorf("Writes = %d to primary, %d to secondary, want 1 and 0", primary.writes.Load(), secondary.writes.Load())
+		}
+
+		primary.down.Store(true)
+		if err := failover.CreateDocument(ctx, "my-index", "1", doc); err == nil {
+			t.Error("CreateDocument() with the primary down succeeded, want an error")
+		}
+		if secondary.writes.Load() != 0 {
+			t.Error("CreateDocument() wrote to the secondary in primary-only mode")
+		}
+	})
+
+	t.Run("Mirrored", func(t *testing.T) {
+		failover, primary, secondary := newFailoverStubs(t, FailoverOptions{HealthCheckInterval: time.Hour, WriteMode: WriteMirrored})
+
+		if err := failover.DeleteDocument(ctx, "my-index", "1"); err != nil {
+			t.Fatalf("DeleteDocument() error = %v", err)
+		}
+		if primary.writes.Load() != 1 || secondary.writes.Load() != 1 {
+			t.Errorf("Writes = %d to primary, %d to secondary, want 1 each", primary.writes.Load(), secondary.writes.Load())
+		}
+
+		secondary.down.Store(true)
+		err := failover.CreateDocument(ctx, "my-index", "1", doc)
+		if err == nil || !strings.Contains(err.Error(), "cluster 1") {
+			t.Errorf("CreateDocument() error = %v, want the secondary's failure", err)
+		}
+		if primary.writes.Load() != 2 {
+			t.Errorf("Primary has %d writes, want the mirrored write to still reach it", primary.writes.Load())
+		}
+	})
+}
+
+// hangingTransport blocks every request until its context is done
+type hangingTransport struct {
+	started chan struct{}
+	once    sync.Once
+}
+
+func (h *hangingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.once.Do(func() { close(h.started) })
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestFailoverClient_CloseCancelsHealthCheck(t *testing.T) {
+	hanging := &hangingTransport{started: make(chan struct{})}
+	client, err := newClient(Config{Addresses: []string{"http://primary:9200"}, DisableRetry: true}, hanging)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	interval := 100 * time.Millisecond
+	failover, err := NewFailoverClient([]*Client{client}, FailoverOptions{HealthCheckInterval: interval})
+	if err != nil {
+		t.Fatalf("NewFailoverClient() error = %v", err)
+	}
+
+	select {
+	case <-hanging.started:
+	case <-time.After(time.Second):
+		t.Fatal("The health check did not ping the cluster")
+	}
+
+	start := time.Now()
+	failover.Close()
+	if elapsed := time.Since(start); elapsed >= interval/2 {
+		t.Errorf("Close() took %s, want it to cancel the ping in flight", elapsed)
+	}
+	failover.mu.Lock()
+	healthy := failover.healthy[0]
+	failover.mu.Unlock()
+	if !healthy {
+		t.Error("A ping cancelled by Close marked the cluster down")
+	}
+	failover.Close()
+}
+
+func TestNewFailoverClient_NoClients(t *testing.T) {
+	if _, err := NewFailoverClient(nil, FailoverOptions{}); err == nil {
+		t.Error("NewFailoverClient() without clients expected error but got nil")
+	}
+}
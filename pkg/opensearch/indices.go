@@ -0,0 +1,743 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// defaultHealthTimeout bounds server-side health waits when the context has no deadline
+const defaultHealthTimeout = 30 * time.Second
+
+// RefreshIndex makes all operations performed on the given indices since the last
+// refresh visible to search. With no indices, every index in the cluster is refreshed.
+func (c *Client) RefreshIndex(ctx context.Context, indices ...string) (err error) {
+	defer c.observe("RefreshIndex", time.Now(), &err)
+
+	req := opensearchapi.IndicesRefreshRequest{
+		Index: indices,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to refresh index: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return requestError("refresh", res)
+	}
+
+	return nil
+}
+
+// ShrinkIndex shrinks a source index into a new target index with fewer primary shards.
+// It blocks writes on the source and relocates all of its shards to a single data node,
+// waits for relocation to finish, issues the shrink and waits for the target to go green.
+// The source's previous write block and allocation filter are restored once the target is
+// ready, or when the shrink fails, so a source that was already read-only stays read-only.
+func (c *Client) ShrinkIndex(ctx context.Context, source, target string, targetShards int) (err error) {
+	defer c.observe("ShrinkIndex", time.Now(), &err)
+
+	current, err := c.indexSettings(ctx, source, "index.blocks.write", "index.routing.allocation.require._name")
+	if err != nil {
+		return fmt.Errorf("shrink %s: failed to read source settings: %w", source, err)
+	}
+
+	node, err := c.shrinkNodeName(ctx, source)
+	if err != nil {
+		return fmt.Errorf("shrink %s: failed to pick a node to allocate shards to: %w", source, err)
+	}
+
+	// Only the settings changed here are restored: a source that was already read-only
+	// stays read-only, and a previous allocation filter is put back
+	prepare := map[string]interface{}{"index.routing.allocation.require._name": node}
+	restore := map[string]interface{}{"index.routing.allocation.require._name": nil}
+	if previous, ok := current["index.routing.allocation.require._name"]; ok {
+		restore["index.routing.allocation.require._name"] = previous
+	}
+	if current["index.blocks.write"] != "true" {
+		prepare["index.blocks.write"] = true
+		restore["index.blocks.write"] = nil
+	}
+	if err := c.putIndexSettings(ctx, source, prepare); err != nil {
+		return fmt.Errorf("shrink %s: failed to make source read-only on node %s: %w", source, node, err)
+	}
+	defer c.restoreIndexSettings(ctx, "shrink", source, restore, &err)
+
+	if err := c.waitForIndexHealth(ctx, source, "", true); err != nil {
+		return fmt.Errorf("shrink %s: failed waiting for shard relocation: %w", source, err)
+	}
+
+	body := resizeBody(targetShards, map[string]interface{}{
+		"index.routing.allocation.require._name": nil,
+		"index.blocks.write":                     nil,
+	})
+	req := opensearchapi.IndicesShrinkRequest{
+		Index:  source,
+		Target: target,
+		Body:   bytes.NewReader(body),
+	}
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("shrink %s: failed to shrink index: %w", source, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("shrink %s: %w", source, requestError("shrink index", res))
+	}
+
+	if err := c.waitForIndexHealth(ctx, target, "green", false); err != nil {
+		return fmt.Errorf("shrink %s: failed waiting for target %s to go green: %w", source, target, err)
+	}
+
+	return nil
+}
+
+// SplitIndex splits a source index into a new target index with more primary shards.
+// targetShards must be a multiple of the source's shard count. Writes on the source
+// are blocked for the duration of the split, and a block added by the split is lifted
+// once the target is green, or when the split fails.
+func (c *Client) SplitIndex(ctx context.Context, source, target string, targetShards int) (err error) {
+	defer c.observe("SplitIndex", time.Now(), &err)
+
+	blocked, err := c.indexWriteBlocked(ctx, source)
+	if err != nil {
+		return fmt.Errorf("split %s: failed to read source settings: %w", source, err)
+	}
+
+	if !blocked {
+		if err := c.putIndexSettings(ctx, source, map[string]interface{}{"index.blocks.write": true}); err != nil {
+			return fmt.Errorf("split %s: failed to make source read-only: %w", source, err)
+		}
+		defer c.restoreIndexSettings(ctx, "split", source, map[string]interface{}{"index.blocks.write": nil}, &err)
+	}
+
+	body := resizeBody(targetShards, map[string]interface{}{
+		"index.blocks.write": nil,
+	})
+	req := opensearchapi.IndicesSplitRequest{
+		Index:  source,
+		Target: target,
+		Body:   bytes.NewReader(body),
+	}
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("split %s: failed to split index: %w", source, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("split %s: %w", source, requestError("split index", res))
+	}
+
+	if err := c.waitForIndexHealth(ctx, target, "green", false); err != nil {
+		return fmt.Errorf("split %s: failed waiting for target %s to go green: %w", source, target, err)
+	}
+
+	return nil
+}
+
+// CloneIndex copies a source index into a new target index with the same shard count.
+// The source is write-blocked for the duration of the clone (as the clone API requires)
+// and its previous write-block setting is restored afterwards, even on failure.
+// targetBody may carry additional settings or aliases for the target and can be nil.
+// It returns once the target reaches at least yellow health.
+func (c *Client) CloneIndex(ctx context.Context, source, target string, targetBody map[string]interface{}) (err error) {
+	defer c.observe("CloneIndex", time.Now(), &err)
+
+	blocked, err := c.indexWriteBlocked(ctx, source)
+	if err != nil {
+		return fmt.Errorf("clone %s: failed to read source settings: %w", source, err)
+	}
+
+	if !blocked {
+		if err := c.putIndexSettings(ctx, source, map[string]interface{}{"index.blocks.write": true}); err != nil {
+			return fmt.Errorf("clone %s: failed to make source read-only: %w", source, err)
+		}
+		defer c.restoreIndexSettings(ctx, "clone", source, map[string]interface{}{"index.blocks.write": nil}, &err)
+	}
+
+	var bodyReader *bytes.Reader
+	if targetBody != nil {
+		body, err := json.Marshal(targetBody)
+		if err != nil {
+			return fmt.Errorf("clone %s: failed to marshal target body: %w", source, err)
+		}
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req := opensearchapi.IndicesCloneRequest{
+		Index:  source,
+		Target: target,
+	}
+	if bodyReader != nil {
+		req.Body = bodyReader
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("clone %s: failed to clone index: %w", source, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("clone %s: %w", source, requestError("clone index", res))
+	}
+
+	if err := c.waitForIndexHealth(ctx, target, "yellow", false); err != nil {
+		return fmt.Errorf("clone %s: failed waiting for target %s: %w", source, target, err)
+	}
+
+	return nil
+}
+
+// restoreIndexSettings undoes the settings a resize operation applied to its source. It runs
+// even when ctx is done, so a cancelled resize does not leave the source write-blocked. A
+// failed restore is reported through err unless the operation already failed.
+func (c *Client) restoreIndexSettings(ctx context.Context, op, source string, settings map[string]interface{}, err *error) {
+	restoreErr := c.putIndexSettings(context.WithoutCancel(ctx), source, settings)
+	if restoreErr != nil && *err == nil {
+		*err = fmt.Errorf("%s %s: failed to restore source settings: %w", op, source, restoreErr)
+	}
+}
+
+// indexWriteBlocked reports whether index.blocks.write is currently set on an index
+func (c *Client) indexWriteBlocked(ctx context.Context, index string) (bool, error) {
+	settings, err := c.indexSettings(ctx, index, "index.blocks.write")
+	if err != nil {
+		return false, err
+	}
+	return settings["index.blocks.write"] == "true", nil
+}
+
+// indexSettings returns the named settings of an index, as flat setting names and their
+// values. Settings that are not set are missing from the result.
+func (c *Client) indexSettings(ctx context.Context, index string, names ...string) (map[string]string, error) {
+	flat := true
+	req := opensearchapi.IndicesGetSettingsRequest{
+		Index:        []string{index},
+		Name:         names,
+		FlatSettings: &flat,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index settings: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return nil, requestError("get index settings", res)
+	}
+
+	var response map[string]struct {
+		Settings map[string]string `json:"settings"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	return response[index].Settings, nil
+}
+
+// resizeBody builds the body of a shrink or split request
+func resizeBody(targetShards int, settings map[string]interface{}) []byte {
+	settings["index.number_of_shards"] = targetShards
+	body, _ := json.Marshal(map[string]interface{}{
+		"settings": settings,
+	})
+	return body
+}
+
+// putIndexSettings updates dynamic settings of an index. A nil value resets a setting to its default.
+func (c *Client) putIndexSettings(ctx context.Context, index string, settings map[string]interface{}) error {
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index settings: %w", err)
+	}
+
+	req := opensearchapi.IndicesPutSettingsRequest{
+		Index: []string{index},
+		Body:  bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to update index settings: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return requestError("update index settings", res)
+	}
+
+	return nil
+}
+
+// waitForIndexHealth blocks until the index reaches the given status (if any) and,
+// optionally, has no relocating shards. The server-side wait is bounded by the
+// context deadline, or defaultHealthTimeout when there is none.
+func (c *Client) waitForIndexHealth(ctx context.Context, index, status string, noRelocating bool) error {
+	timeout := defaultHealthTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	req := opensearchapi.ClusterHealthRequest{
+		Index:         []string{index},
+		WaitForStatus: status,
+		Timeout:       timeout,
+	}
+	if noRelocating {
+		req.WaitForNoRelocatingShards = &noRelocating
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to get index health: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 408 {
+		return fmt.Errorf("timed out waiting for index %s health", index)
+	}
+	if res.IsError() {
+		return requestError("cluster health", res)
+	}
+
+	return nil
+}
+
+// shrinkNodeName picks the data node to gather the shards of index on for a shrink: the one
+// holding copies of the most of its shards, ideally all of them, so the least data moves.
+// Ties go to the first node by name.
+func (c *Client) shrinkNodeName(ctx context.Context, index string) (string, error) {
+	req := opensearchapi.NodesInfoRequest{
+		FilterPath: []string{"nodes.*.name", "nodes.*.roles"},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return "", fmt.Errorf("failed to get nodes info: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return "", requestError("nodes info", res)
+	}
+
+	var response struct {
+		Nodes map[string]struct {
+			Name  string   `json:"name"`
+			Roles []string `json:"roles"`
+		} `json:"nodes"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return "", err
+	}
+
+	var dataNodes []string
+	for _, node := range response.Nodes {
+		for _, role := range node.Roles {
+			if role == "data" {
+				dataNodes = append(dataNodes, node.Name)
+				break
+			}
+		}
+	}
+	if len(dataNodes) == 0 {
+		return "", fmt.Errorf("no data nodes found")
+	}
+	sort.Strings(dataNodes)
+
+	shards, err := c.catShards(ctx, index)
+	if err != nil {
+		return "", err
+	}
+	held := make(map[string]map[int]bool)
+	for _, shard := range shards {
+		if shard.State != "STARTED" {
+			continue
+		}
+		if held[shard.Node] == nil {
+			held[shard.Node] = make(map[int]bool)
+		}
+		held[shard.Node][shard.Shard] = true
+	}
+
+	best := dataNodes[0]
+	for _, name := range dataNodes[1:] {
+		if len(held[name]) > len(held[best]) {
+			best = name
+		}
+	}
+	return best, nil
+}
+
+// FlushIndex flushes the given indices, writing in-memory operations to disk and
+// clearing the transaction log. With no indices, every index in the cluster is flushed.
+func (c *Client) FlushIndex(ctx context.Context, indices ...string) (err error) {
+	defer c.observe("FlushIndex", time.Now(), &err)
+
+	req := opensearchapi.IndicesFlushRequest{
+		Index: indices,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to flush index: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return requestError("flush", res)
+	}
+
+	return nil
+}
+
+// Analyze runs text through an analyzer and returns the tokens it produces. With an index,
+// the index's custom analyzers can be used; an empty index uses the built-in analyzers only.
+func (c *Client) Analyze(ctx context.Context, index, analyzer, text string) (_ []string, err error) {
+	defer c.observe("Analyze", time.Now(), &err)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"analyzer": analyzer,
+		"text":     text,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal analyze request: %w", err)
+	}
+
+	req := opensearchapi.IndicesAnalyzeRequest{
+		Index: index,
+		Body:  bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze text: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return nil, requestError("analyze", res)
+	}
+
+	var response struct {
+		Tokens []struct {
+			Token string `json:"token"`
+		} `json:"tokens"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]string, 0, len(response.Tokens))
+	for _, token := range response.Tokens {
+		tokens = append(tokens, token.Token)
+	}
+
+	return tokens, nil
+}
+
+// FieldCaps returns the capabilities of the given fields across indices, keyed by field name
+// and then by type, e.g. caps["title"]["text"]["searchable"]. Fields may use wildcards, and
+// "*" or no fields returns every field.
+func (c *Client) FieldCaps(ctx context.Context, indices []string, fields []string) (_ map[string]interface{}, err error) {
+	defer c.observe("FieldCaps", time.Now(), &err)
+
+	if len(fields) == 0 {
+		fields = []string{"*"}
+	}
+
+	req := opensearchapi.FieldCapsRequest{
+		Index:  indices,
+		Fields: fields,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get field capabilities: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return nil, requestError("field caps", res)
+	}
+
+	var response struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Fields, nil
+}
+
+// ForceMerge merges the segments of the given indices and returns the shard summary
+// once the merge has finished. maxNumSegments of 0 leaves the segment count to the
+// server; onlyExpungeDeletes restricts the merge to segments with deleted documents.
+// Merging large indices can take a long time, see ForceMergeAsync.
+func (c *Client) ForceMerge(ctx context.Context, indices []string, maxNumSegments int, onlyExpungeDeletes bool) (_ ShardsInfo, err error) {
+	defer c.observe("ForceMerge", time.Now(), &err)
+
+	req := opensearchapi.IndicesForcemergeRequest{
+		Index: indices,
+	}
+	if maxNumSegments > 0 {
+		req.MaxNumSegments = &maxNumSegments
+	}
+	if onlyExpungeDeletes {
+		req.OnlyExpungeDeletes = &onlyExpungeDeletes
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return ShardsInfo{}, fmt.Errorf("failed to force merge: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return ShardsInfo{}, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return ShardsInfo{}, requestError("force merge", res)
+	}
+
+	var response ShardsResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return ShardsInfo{}, err
+	}
+
+	return response.Shards, nil
+}
+
+// ForceMergeAsync starts a force merge without waiting for it to finish and
+// returns the ID of the server-side task running it. opensearchapi has no
+// wait_for_completion parameter for force merge, so the request is built by hand.
+func (c *Client) ForceMergeAsync(ctx context.Context, indices []string, maxNumSegments int, onlyExpungeDeletes bool) (_ string, err error) {
+	defer c.observe("ForceMergeAsync", time.Now(), &err)
+
+	path := "/_forcemerge"
+	if len(indices) > 0 {
+		path = "/" + strings.Join(indices, ",") + path
+	}
+
+	params := url.Values{}
+	params.Set("wait_for_completion", "false")
+	if maxNumSegments > 0 {
+		params.Set("max_num_segments", strconv.Itoa(maxNumSegments))
+	}
+	if onlyExpungeDeletes {
+		params.Set("only_expunge_deletes", "true")
+	}
+
+	res, err := c.perform(ctx, http.MethodPost, path, params, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to force merge: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return "", responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return "", requestError("force merge", res)
+	}
+
+	var response TaskResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return "", err
+	}
+
+	return response.Task, nil
+}
+
+// IndexStats returns document, store, indexing and search totals per index,
+// summed over primaries and replicas. With no indices, every index is included.
+func (c *Client) IndexStats(ctx context.Context, indices ...string) (_ map[string]IndexStatsResult, err error) {
+	defer c.observe("IndexStats", time.Now(), &err)
+
+	req := opensearchapi.IndicesStatsRequest{
+		Index: indices,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index stats: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return nil, requestError("index stats", res)
+	}
+
+	var response struct {
+		Indices map[string]struct {
+			Total json.RawMessage `json:"total"`
+		} `json:"indices"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]IndexStatsResult, len(response.Indices))
+	for name, index := range response.Indices {
+		var section indexStatsSection
+		if err := json.Unmarshal(index.Total, &section); err != nil {
+			return nil, fmt.Errorf("failed to parse stats for index %s: %w", name, err)
+		}
+		stats[name] = IndexStatsResult{
+			DocsCount:        section.Docs.Count,
+			DocsDeleted:      section.Docs.Deleted,
+			StoreSizeBytes:   section.Store.SizeInBytes,
+			IndexingTotal:    section.Indexing.IndexTotal,
+			SearchQueryTotal: section.Search.QueryTotal,
+			Raw:              index.Total,
+		}
+	}
+
+	return stats, nil
+}
+
+// ListIndices returns the indices matching pattern, sorted by name. An empty pattern lists every index.
+func (c *Client) ListIndices(ctx context.Context, pattern string) (_ []IndexInfo, err error) {
+	defer c.observe("ListIndices", time.Now(), &err)
+
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	return c.catIndices(ctx, []string{pattern})
+}
+
+// catIndices lists the given indices (every index, including hidden ones, when empty) with the cat indices API
+func (c *Client) catIndices(ctx context.Context, indices []string) ([]IndexInfo, error) {
+	req := opensearchapi.CatIndicesRequest{
+		Index:  indices,
+		Format: "json",
+		Bytes:  "b",
+		H:      []string{"index", "health", "status", "docs.count", "store.size"},
+		S:      []string{"index"},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indices: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return nil, requestError("cat indices", res)
+	}
+
+	// Numbers come back as strings, and are null for closed indices
+	var rows []struct {
+		Index     string  `json:"index"`
+		Health    string  `json:"health"`
+		Status    string  `json:"status"`
+		DocsCount *string `json:"docs.count"`
+		StoreSize *string `json:"store.size"`
+	}
+	if err := c.parseResponse(res.Body, &rows); err != nil {
+		return nil, err
+	}
+
+	infos := make([]IndexInfo, 0, len(rows))
+	for _, row := range rows {
+		infos = append(infos, IndexInfo{
+			Index:     row.Index,
+			Health:    row.Health,
+			Status:    row.Status,
+			DocsCount: catInt(row.DocsCount),
+			StoreSize: catInt(row.StoreSize),
+		})
+	}
+
+	return infos, nil
+}
+
+// DeleteIndices deletes several indices in a single request. Entries may be wildcard
+// patterns such as "logs-2023-*" or comma-separated lists. Entries with a part that
+// matches every index ("", "*" or "_all", as in "logs,*") are rejected, use
+// DeleteAllIndices for that.
+func (c *Client) DeleteIndices(ctx context.Context, indices []string) (err error) {
+	defer c.observe("DeleteIndices", time.Now(), &err)
+
+	if len(indices) == 0 {
+		return fmt.Errorf("at least one index is required")
+	}
+	for _, index := range indices {
+		for _, part := range strings.Split(index, ",") {
+			switch strings.TrimSpace(part) {
+			case "", "*", "_all":
+				return fmt.Errorf("refusing to delete all indices with pattern %q", index)
+			}
+		}
+	}
+
+	return c.deleteIndices(ctx, indices)
+}
+
+// DeleteAllIndices deletes every index in the cluster
+func (c *Client) DeleteAllIndices(ctx context.Context) (err error) {
+	defer c.observe("DeleteAllIndices", time.Now(), &err)
+
+	return c.deleteIndices(ctx, []string{"_all"})
+}
+
+// deleteIndices deletes the given indices. A wildcard matching nothing is not an error.
+func (c *Client) deleteIndices(ctx context.Context, indices []string) error {
+	req := opensearchapi.IndicesDeleteRequest{
+		Index: indices,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to delete indices: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return requestError("delete indices", res)
+	}
+
+	return nil
+}
@@ -0,0 +1,641 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// setupShardedIndex creates an index with the given primary shard count and no replicas,
+// so health can reach green on a single-node cluster
+func setupShardedIndex(t *testing.T, client *Client, indexName string, shards int) func() {
+	t.Helper()
+	ctx := context.Background()
+
+	_ = client.DeleteIndex(ctx, indexName)
+
+	err := client.CreateIndex(ctx, indexName, map[string]interface{}{
+		"settings": map[string]interface{}{
+			"index.number_of_shards":   shards,
+			"index.number_of_replicas": 0,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test index: %v", err)
+	}
+
+	return func() {
+		_ = client.DeleteIndex(ctx, indexName)
+	}
+}
+
+// seedDocuments bulk-indexes count small documents into the index
+func seedDocuments(t *testing.T, client *Client, indexName string, count int) {
+	t.Helper()
+
+	docs := make([]map[string]interface{}, count)
+	for i := 0; i < count; i++ {
+		docs[i] = map[string]interface{}{"_id": fmt.Sprintf("doc-%d", i), "seq": i}
+	}
+	if err := client.BulkCreate(context.Background(), indexName, docs); err != nil {
+		t.Fatalf("Failed to seed documents: %v", err)
+	}
+}
+
+func TestRefreshIndex(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-refresh-index"
+	ctx := context.Background()
+
+	_ = client.DeleteIndex(ctx, indexName)
+	err := client.CreateIndex(ctx, indexName, map[string]interface{}{
+		"settings": map[string]interface{}{
+			"index.refresh_interval": "-1",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test index: %v", err)
+	}
+	defer func() { _ = client.DeleteIndex(ctx, indexName) }()
+
+	// Write without refreshing so the document is not yet searchable
+	req := opensearchapi.IndexRequest{
+		Index:      indexName,
+		DocumentID: "doc-1",
+		Body:       strings.NewReader(`{"title":"Unrefreshed"}`),
+		Refresh:    "false",
+	}
+	res, err := req.Do(ctx, client.GetClient())
+	if err != nil {
+		t.Fatalf("Failed to index document: %v", err)
+	}
+	res.Body.Close()
+
+	results, err := client.SearchAll(ctx, indexName)
+	if err != nil {
+		t.Fatalf("SearchAll() before refresh error = %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("Expected no hits before refresh, got %d", len(results))
+	}
+
+	if err := client.RefreshIndex(ctx, indexName); err != nil {
+		t.Fatalf("RefreshIndex() error = %v", err)
+	}
+
+	results, err = client.SearchAll(ctx, indexName)
+	if err != nil {
+		t.Fatalf("SearchAll() after refresh error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected 1 hit after refresh, got %d", len(results))
+	}
+}
+
+func TestResizeBody(t *testing.T) {
+	body := resizeBody(1, map[string]interface{}{"index.blocks.write": nil})
+	want := `{"settings":{"index.blocks.write":null,"index.number_of_shards":1}}`
+	if string(body) != want {
+		t.Errorf("resizeBody() = %s, want %s", body, want)
+	}
+}
+
+func TestShrinkIndex(t *testing.T) {
+	client := setupTestClient(t)
+	source := "test-shrink-source"
+	target := "test-shrink-target"
+	cleanup := setupShardedIndex(t, client, source, 2)
+	defer cleanup()
+	defer func() { _ = client.DeleteIndex(context.Background(), target) }()
+
+	ctx := context.Background()
+	seedDocuments(t, client, source, 20)
+
+	if err := client.ShrinkIndex(ctx, source, target, 1); err != nil {
+		t.Fatalf("ShrinkIndex() error = %v", err)
+	}
+
+	results, err := client.SearchDocuments(ctx, target, WithSize(MatchAllQuery(), 100))
+	if err != nil {
+		t.Fatalf("Failed to search target: %v", err)
+	}
+	if len(results) != 20 {
+		t.Errorf("Expected 20 documents in shrunk index, got %d", len(results))
+	}
+
+	// Source must be writable again once the shrink completes
+	if err := client.CreateDocument(ctx, source, "after-shrink", map[string]interface{}{"seq": 99}); err != nil {
+		t.Errorf("Source should be writable after shrink: %v", err)
+	}
+}
+
+func TestSplitIndex(t *testing.T) {
+	client := setupTestClient(t)
+	source := "test-split-source"
+	target := "test-split-target"
+	cleanup := setupShardedIndex(t, client, source, 1)
+	defer cleanup()
+	defer func() { _ = client.DeleteIndex(context.Background(), target) }()
+
+	ctx := context.Background()
+	seedDocuments(t, client, source, 20)
+
+	if err := client.SplitIndex(ctx, source, target, 2); err != nil {
+		t.Fatalf("SplitIndex() error = %v", err)
+	}
+
+	results, err := client.SearchDocuments(ctx, target, WithSize(MatchAllQuery(), 100))
+	if err != nil {
+		t.Fatalf("Failed to search target: %v", err)
+	}
+	if len(results) != 20 {
+		t.Errorf("Expected 20 documents in split index, got %d", len(results))
+	}
+}
+
+func TestSplitIndex_RestoresOnFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The split request fails after ctx is cancelled; the write block is still lifted
+	stub := &stubTransport{statuses: []int{200, 200, 400, 200}, status: 200, body: `{}`}
+	stub.onRequest = func() {
+		if stub.calls == 3 {
+			cancel()
+		}
+	}
+	client := newStubClient(t, stub)
+
+	if err := client.SplitIndex(ctx, "books", "books-split", 2); err == nil {
+		t.Fatal("SplitIndex() expected error but got nil")
+	}
+	if stub.calls != 4 {
+		t.Fatalf("SplitIndex() sent %d requests, want read, prepare, split and restore", stub.calls)
+	}
+	if stub.path != "/books/_settings" || string(stub.sent) != `{"index.blocks.write":null}` {
+		t.Errorf("last request = %s %s, want the write block lifted on books", stub.path, stub.sent)
+	}
+	if stub.ctx.Err() != nil {
+		t.Errorf("restore was sent with a done context: %v", stub.ctx.Err())
+	}
+}
+
+func TestSplitIndex_ReadOnlySource(t *testing.T) {
+	// Read settings (already blocked), split, wait for the target: the block is kept
+	stub := &stubTransport{status: 200, body: `{"books":{"settings":{"index.blocks.write":"true"}}}`}
+	client := newStubClient(t, stub)
+
+	if err := client.SplitIndex(context.Background(), "books", "books-split", 2); err != nil {
+		t.Fatalf("SplitIndex() error = %v", err)
+	}
+	if stub.calls != 3 || stub.path != "/_cluster/health/books-split" {
+		t.Errorf("last of %d requests = %s %s, want no settings change after the target health", stub.calls, stub.method, stub.path)
+	}
+}
+
+func TestShrinkIndex_ReadOnlySource(t *testing.T) {
+	settings := `{"books":{"settings":{"index.blocks.write":"true","index.routing.allocation.require._name":"node-2"}}}`
+	nodes := `{"nodes":{"a":{"name":"node-1","roles":["data"]}}}`
+	shards := `[{"index":"books","shard":"0","prirep":"p","state":"STARTED","node":"node-1"}]`
+	stub := &stubTransport{status: 200, body: `{}`, bodies: []string{settings, nodes, shards}}
+	var prepare []byte
+	stub.onRequest = func() {
+		// Before the fifth request, sent still holds the fourth: the prepare
+		if stub.calls == 5 {
+			prepare = stub.sent
+		}
+	}
+	client := newStubClient(t, stub)
+
+	if err := client.ShrinkIndex(context.Background(), "books", "books-shrunk", 1); err != nil {
+		t.Fatalf("ShrinkIndex() error = %v", err)
+	}
+	if string(prepare) != `{"index.routing.allocation.require._name":"node-1"}` {
+		t.Errorf("prepare = %s, want only the allocation filter set", prepare)
+	}
+	if stub.path != "/books/_settings" || string(stub.sent) != `{"index.routing.allocation.require._name":"node-2"}` {
+		t.Errorf("last request = %s %s, want the previous allocation filter put back and the write block kept", stub.path, stub.sent)
+	}
+}
+
+func TestShrinkNodeName(t *testing.T) {
+	nodes := `{"nodes":{
+		"a":{"name":"manager-1","roles":["cluster_manager"]},
+		"b":{"name":"data-3","roles":["data","ingest"]},
+		"c":{"name":"data-1","roles":["data"]},
+		"d":{"name":"data-2","roles":["data"]},
+		"e":{"name":"ingest-1","roles":["ingest"]}
+	}}`
+
+	tests := []struct {
+		name   string
+		shards string
+		want   string
+	}{
+		{
+			name: "node holding every shard",
+			shards: `[
+				{"index":"books","shard":"0","prirep":"p","state":"STARTED","node":"data-1"},
+				{"index":"books","shard":"1","prirep":"p","state":"STARTED","node":"data-2"},
+				{"index":"books","shard":"0","prirep":"r","state":"STARTED","node":"data-3"},
+				{"index":"books","shard":"1","prirep":"r","state":"STARTED","node":"data-3"}
+			]`,
+			want: "data-3",
+		},
+		{
+			name: "no node holds every shard",
+			shards: `[
+				{"index":"books","shard":"0","prirep":"p","state":"STARTED","node":"data-2"},
+				{"index":"books","shard":"1","prirep":"p","state":"STARTED","node":"data-3"},
+				{"index":"books","shard":"1","prirep":"r","state":"RELOCATING","node":"data-1"}
+			]`,
+			want: "data-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubTransport{status: 200, bodies: []string{nodes, tt.shards}}
+			client := newStubClient(t, stub)
+
+			got, err := client.shrinkNodeName(context.Background(), "books")
+			if err != nil {
+				t.Fatalf("shrinkNodeName() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("shrinkNodeName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no data nodes", func(t *testing.T) {
+		stub := &stubTransport{status: 200, body: `{"nodes":{"a":{"name":"manager-1","roles":["cluster_manager"]}}}`}
+		client := newStubClient(t, stub)
+
+		if _, err := client.shrinkNodeName(context.Background(), "books"); err == nil {
+			t.Error("shrinkNodeName() expected error but got nil")
+		}
+	})
+}
+
+func TestCloneIndex_RestoresOnFailure(t *testing.T) {
+	// Read settings (not blocked), block writes, clone fails, lift the block
+	stub := &stubTransport{statuses: []int{200, 200, 400, 200}, status: 200, body: `{}`}
+	client := newStubClient(t, stub)
+
+	if err := client.CloneIndex(context.Background(), "books", "books-clone", nil); err == nil {
+		t.Fatal("CloneIndex() expected error but got nil")
+	}
+	if stub.calls != 4 || stub.path != "/books/_settings" || string(stub.sent) != `{"index.blocks.write":null}` {
+		t.Errorf("last of %d requests = %s %s, want the write block lifted on books", stub.calls, stub.path, stub.sent)
+	}
+}
+
+func TestCloneIndex(t *testing.T) {
+	client := setupTestClient(t)
+	source := "test-clone-source"
+	target := "test-clone-target"
+	cleanup := setupShardedIndex(t, client, source, 1)
+	defer cleanup()
+	defer func() { _ = client.DeleteIndex(context.Background(), target) }()
+
+	ctx := context.Background()
+	seedDocuments(t, client, source, 15)
+
+	if err := client.CloneIndex(ctx, source, target, nil); err != nil {
+		t.Fatalf("CloneIndex() error = %v", err)
+	}
+
+	sourceDocs, err := client.SearchDocuments(ctx, source, WithSize(MatchAllQuery(), 100))
+	if err != nil {
+		t.Fatalf("Failed to search source: %v", err)
+	}
+	targetDocs, err := client.SearchDocuments(ctx, target, WithSize(MatchAllQuery(), 100))
+	if err != nil {
+		t.Fatalf("Failed to search target: %v", err)
+	}
+	if len(targetDocs) != len(sourceDocs) {
+		t.Errorf("Cloned index has %d documents, source has %d", len(targetDocs), len(sourceDocs))
+	}
+
+	blocked, err := client.indexWriteBlocked(ctx, source)
+	if err != nil {
+		t.Fatalf("Failed to read source settings: %v", err)
+	}
+	if blocked {
+		t.Error("Source write block should be restored after clone")
+	}
+}
+
+func TestFlushIndex(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-flush-index"
+	cleanup := setupShardedIndex(t, client, indexName, 1)
+	defer cleanup()
+
+	seedDocuments(t, client, indexName, 10)
+
+	if err := client.FlushIndex(context.Background(), indexName); err != nil {
+		t.Errorf("FlushIndex() error = %v", err)
+	}
+
+	if err := client.FlushIndex(context.Background(), "test-flush-missing"); err == nil {
+		t.Error("FlushIndex() on missing index expected error but got nil")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-analyze"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	tests := []struct {
+		name  string
+		index string
+	}{
+		{name: "Without index", index: ""},
+		{name: "With index", index: indexName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := client.Analyze(context.Background(), tt.index, "standard", "Hello World")
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if !reflect.DeepEqual(tokens, []string{"hello", "world"}) {
+				t.Errorf("Analyze() = %v, want [hello world]", tokens)
+			}
+		})
+	}
+}
+
+func TestFieldCaps(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-field-caps"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	err := client.CreateDocument(ctx, indexName, "doc-1", map[string]interface{}{
+		"title": "Golang Tutorial",
+		"views": 150,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test document: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		fields   []string
+		field    string
+		wantType string
+	}{
+		{name: "Named field", fields: []string{"views"}, field: "views", wantType: "long"},
+		{name: "All fields", fields: []string{"*"}, field: "title", wantType: "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps, err := client.FieldCaps(ctx, []string{indexName}, tt.fields)
+			if err != nil {
+				t.Fatalf("FieldCaps() error = %v", err)
+			}
+
+			types, ok := caps[tt.field].(map[string]interface{})
+			if !ok {
+				t.Fatalf("FieldCaps() has no entry for %s: %v", tt.field, caps)
+			}
+			if _, ok := types[tt.wantType]; !ok || len(types) != 1 {
+				t.Errorf("%s types = %v, want only %s", tt.field, types, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestForceMerge_Parameters(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxNumSegments int
+		wantQuery      string
+	}{
+		{name: "Zero omits max_num_segments", maxNumSegments: 0, wantQuery: ""},
+		{name: "One segment", maxNumSegments: 1, wantQuery: "max_num_segments=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubTransport{status: 200, body: `{"_shards":{"total":1,"successful":1,"failed":0}}`}
+			client := newStubClient(t, stub)
+
+			if _, err := client.ForceMerge(context.Background(), []string{"logs"}, tt.maxNumSegments, false); err != nil {
+				t.Fatalf("ForceMerge() error = %v", err)
+			}
+			if stub.path != "/logs/_forcemerge" {
+				t.Errorf("Request path = %s, want /logs/_forcemerge", stub.path)
+			}
+			if stub.query != tt.wantQuery {
+				t.Errorf("Query string = %q, want %q", stub.query, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestForceMerge(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-forcemerge-index"
+	cleanup := setupShardedIndex(t, client, indexName, 2)
+	defer cleanup()
+
+	ctx := context.Background()
+	seedDocuments(t, client, indexName, 20)
+
+	tests := []struct {
+		name               string
+		maxNumSegments     int
+		onlyExpungeDeletes bool
+	}{
+		{name: "Merge to one segment", maxNumSegments: 1},
+		{name: "Server default segment count", maxNumSegments: 0},
+		{name: "Only expunge deletes", onlyExpungeDeletes: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shards, err := client.ForceMerge(ctx, []string{indexName}, tt.maxNumSegments, tt.onlyExpungeDeletes)
+			if err != nil {
+				t.Fatalf("ForceMerge() error = %v", err)
+			}
+			if shards.Total != 2 || shards.Successful != 2 || shards.Failed != 0 {
+				t.Errorf("ForceMerge() shards = %+v, want 2 total, 2 successful, 0 failed", shards)
+			}
+		})
+	}
+
+	t.Run("Async", func(t *testing.T) {
+		taskID, err := client.ForceMergeAsync(ctx, []string{indexName}, 1, false)
+		if err != nil {
+			t.Fatalf("ForceMergeAsync() error = %v", err)
+		}
+		if !strings.Contains(taskID, ":") {
+			t.Errorf("ForceMergeAsync() task ID = %q, want node:id", taskID)
+		}
+	})
+
+	t.Run("Missing index", func(t *testing.T) {
+		if _, err := client.ForceMerge(ctx, []string{"test-forcemerge-missing"}, 1, false); err == nil {
+			t.Error("ForceMerge() on missing index expected error but got nil")
+		}
+	})
+}
+
+func TestIndexStats(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-stats-index"
+	cleanup := setupShardedIndex(t, client, indexName, 1)
+	defer cleanup()
+
+	ctx := context.Background()
+	seedDocuments(t, client, indexName, 12)
+
+	stats, err := client.IndexStats(ctx, indexName)
+	if err != nil {
+		t.Fatalf("IndexStats() error = %v", err)
+	}
+
+	result, ok := stats[indexName]
+	if !ok {
+		t.Fatalf("IndexStats() missing entry for %s: %+v", indexName, stats)
+	}
+	if result.DocsCount != 12 {
+		t.Errorf("DocsCount = %d, want 12", result.DocsCount)
+	}
+	if result.IndexingTotal < 12 {
+		t.Errorf("IndexingTotal = %d, want at least 12", result.IndexingTotal)
+	}
+	if result.StoreSizeBytes <= 0 {
+		t.Errorf("StoreSizeBytes = %d, want > 0", result.StoreSizeBytes)
+	}
+	if len(result.Raw) == 0 {
+		t.Error("Raw stats should be kept")
+	}
+
+	if _, err := client.IndexStats(ctx, "test-stats-missing"); err == nil {
+		t.Error("IndexStats() on missing index expected error but got nil")
+	}
+}
+
+func TestListAndDeleteIndices(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	names := []string{"test-listing-a", "test-listing-b", "test-listing-c"}
+	for _, name := range names {
+		cleanup := setupTestIndex(t, client, name)
+		defer cleanup()
+	}
+	seedDocuments(t, client, "test-listing-b", 3)
+
+	t.Run("List by pattern", func(t *testing.T) {
+		indices, err := client.ListIndices(ctx, "test-listing-*")
+		if err != nil {
+			t.Fatalf("ListIndices() error = %v", err)
+		}
+		if len(indices) != len(names) {
+			t.Fatalf("ListIndices() returned %d indices, want %d: %+v", len(indices), len(names), indices)
+		}
+		for i, info := range indices {
+			if info.Index != names[i] {
+				t.Errorf("indices[%d].Index = %s, want %s", i, info.Index, names[i])
+			}
+			if info.Status != "open" {
+				t.Errorf("%s status = %s, want open", info.Index, info.Status)
+			}
+			if info.Health == "" {
+				t.Errorf("%s has no health", info.Index)
+			}
+			if info.StoreSize <= 0 {
+				t.Errorf("%s store size = %d, want > 0", info.Index, info.StoreSize)
+			}
+		}
+		if indices[1].DocsCount != 3 {
+			t.Errorf("%s docs count = %d, want 3", indices[1].Index, indices[1].DocsCount)
+		}
+	})
+
+	t.Run("Delete refuses match-all patterns", func(t *testing.T) {
+		for _, pattern := range []string{"", "*", "_all"} {
+			if err := client.DeleteIndices(ctx, []string{"test-listing-a", pattern}); err == nil {
+				t.Errorf("DeleteIndices(%q) expected error but got nil", pattern)
+			}
+		}
+		if err := client.DeleteIndices(ctx, nil); err == nil {
+			t.Error("DeleteIndices(nil) expected error but got nil")
+		}
+	})
+
+	t.Run("Delete by pattern", func(t *testing.T) {
+		if err := client.DeleteIndices(ctx, []string{"test-listing-*"}); err != nil {
+			t.Fatalf("DeleteIndices() error = %v", err)
+		}
+
+		indices, err := client.ListIndices(ctx, "test-listing-*")
+		if err != nil {
+			t.Fatalf("ListIndices() error = %v", err)
+		}
+		if len(indices) != 0 {
+			t.Errorf("Expected no indices left, got %+v", indices)
+		}
+	})
+}
+
+func TestDeleteIndices_MatchAllParts(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{"acknowledged":true}`}
+	client := newStubClient(t, stub)
+
+	for _, pattern := range []string{"logs-1,*", "logs-1,_all", "logs-1,", ",logs-1", "logs-1, * "} {
+		if err := client.DeleteIndices(context.Background(), []string{pattern}); err == nil {
+			t.Errorf("DeleteIndices(%q) expected error but got nil", pattern)
+		}
+	}
+	if stub.calls != 0 {
+		t.Errorf("DeleteIndices() sent %d requests, want none", stub.calls)
+	}
+
+	if err := client.DeleteIndices(context.Background(), []string{"logs-1,logs-2-*"}); err != nil {
+		t.Fatalf("DeleteIndices() error = %v", err)
+	}
+	if stub.path != "/logs-1,logs-2-*" {
+		t.Errorf("DeleteIndices() path = %s, want /logs-1,logs-2-*", stub.path)
+	}
+}
+
+func TestDeleteIndices(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	names := []string{"test-multi-delete-1", "test-multi-delete-2", "test-multi-delete-3"}
+	for _, name := range names {
+		cleanup := setupTestIndex(t, client, name)
+		defer cleanup()
+	}
+
+	if err := client.DeleteIndices(ctx, names); err != nil {
+		t.Fatalf("DeleteIndices() error = %v", err)
+	}
+
+	for _, name := range names {
+		exists, err := client.IndexExists(ctx, name)
+		if err != nil {
+			t.Fatalf("IndexExists(%s) error = %v", name, err)
+		}
+		if exists {
+			t.Errorf("Index %s still exists after DeleteIndices()", name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package opensearch
+
+import "context"
+
+// The interfaces below group the most used methods of Client, so that code depending on
+// them can be unit tested against a fake such as opensearchtest.MockClient. Depend on the
+// smallest interface that covers what the code needs; ClientAPI combines them all.
+
+// StatusAPI checks that the cluster is reachable
+type StatusAPI interface {
+	Ping(ctx context.Context) error
+	Info(ctx context.Context) (map[string]interface{}, error)
+}
+
+// DocumentAPI reads and writes single documents
+type DocumentAPI interface {
+	CreateDocument(ctx context.Context, index, id string, document interface{}, opts ...DocumentOption) error
+	GetDocument(ctx context.Context, index, id string, opts ...DocumentOption) (map[string]interface{}, error)
+	UpdateDocument(ctx context.Context, index, id string, updates interface{}, opts ...DocumentOption) error
+	DeleteDocument(ctx context.Context, index, id string, opts ...DocumentOption) error
+}
+
+// Searcher searches indices
+type Searcher interface {
+	SearchDocuments(ctx context.Context, index string, query map[string]interface{}) ([]map[string]interface{}, error)
+	SearchAll(ctx context.Context, index string) ([]map[string]interface{}, error)
+	MultiSearch(ctx context.Context, searches []SearchSpec) ([][]map[string]interface{}, error)
+	SearchWithAggregations(ctx context.Context, index string, query map[string]interface{}) ([]map[string]interface{}, map[string]interface{}, error)
+}
+
+// BulkAPI indexes documents in bulk
+type BulkAPI interface {
+	BulkCreate(ctx context.Context, index string, documents []map[string]interface{}) error
+	BulkCreateWithRefresh(ctx context.Context, index string, documents []map[string]interface{}, refresh string) error
+}
+
+// IndexAPI manages indices
+type IndexAPI interface {
+	CreateIndex(ctx context.Context, index string, body map[string]interface{}) error
+	EnsureIndex(ctx context.Context, index string, body map[string]interface{}) (bool, error)
+	DeleteIndex(ctx context.Context, index string) error
+	DeleteIndexIfExists(ctx context.Context, index string) (bool, error)
+	IndexExists(ctx context.Context, index string) (bool, error)
+	RefreshIndex(ctx context.Context, indices ...string) error
+}
+
+// ClientAPI is the combined interface of the methods above
+type ClientAPI interface {
+	StatusAPI
+	DocumentAPI
+	Searcher
+	BulkAPI
+	IndexAPI
+}
+
+// Client implements ClientAPI
+var _ ClientAPI = (*Client)(nil)
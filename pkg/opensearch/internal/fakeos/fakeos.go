@@ -0,0 +1,964 @@
+// Package fakeos is an in-memory stand-in for an OpenSearch cluster, for unit tests that
+// exercise request construction and response parsing without a running cluster.
+//
+// It implements just enough of the document, search, bulk and index endpoints to answer
+// the requests the client sends, with the status codes and error bodies of OpenSearch.
+// Searches support match_all, term, terms, match and ids queries, a bool query of those,
+// percolate queries against stored queries of those, size, from, sort and search_after,
+// and points in time.
+package fakeos
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   []byte
+}
+
+// Server is a fake OpenSearch cluster served over HTTP
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	indices  map[string]*index
+	pits     map[string]*pointInTime
+	failures []failure
+	requests []Request
+	nextID   int
+}
+
+// failure is a rule added with Fail
+type failure struct {
+	match     func(Request) bool
+	status    int
+	errorType string
+}
+
+// pointInTime is a copy of an index's documents taken when the PIT was opened
+type pointInTime struct {
+	index string
+	docs  map[string]*document
+}
+
+type index struct {
+	docs     map[string]*document
+	readOnly bool
+}
+
+type document struct {
+	source  map[string]interface{}
+	version int64
+	seqNo   int64
+}
+
+// New starts a server that is closed when the test ends
+func New(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{indices: make(map[string]*index), pits: make(map[string]*pointInTime)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// CreateIndex creates an empty index
+func (s *Server) CreateIndex(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.indices[name] = &index{docs: make(map[string]*document)}
+}
+
+// SetReadOnly blocks writes to the index, creating it if needed. Writes are rejected
+// with a cluster_block_exception, as with the index.blocks.write setting.
+func (s *Server) SetReadOnly(name string, readOnly bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.getOrCreateIndex(name).readOnly = readOnly
+}
+
+// PutDocument stores a document directly, without a request. The source is normalized
+// through JSON, so numbers are stored as float64 as they are for indexed documents.
+func (s *Server) PutDocument(indexName, id string, source map[string]interface{}) {
+	var normalized map[string]interface{}
+	if body, err := json.Marshal(source); err == nil {
+		_ = json.Unmarshal(body, &normalized)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(s.getOrCreateIndex(indexName), id, normalized, 0)
+}
+
+// OpenPointsInTime returns the number of points in time that have not been closed
+func (s *Server) OpenPointsInTime() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pits)
+}
+
+// Document returns the source of a stored document
+func (s *Server) Document(indexName, id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx, ok := s.indices[indexName]
+	if !ok {
+		return nil, false
+	}
+	doc, ok := idx.docs[id]
+	if !ok {
+		return nil, false
+	}
+	return doc.source, true
+}
+
+// Fail makes every request for which match returns true fail with status and an error of
+// errorType, e.g. to fail the second search of a pagination. match is called with the
+// server locked and must not call its methods.
+func (s *Server) Fail(match func(r Request) bool, status int, errorType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{match: match, status: status, errorType: errorType})
+}
+
+// Requests returns the requests received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest returns the most recent request received
+func (s *Server) LastRequest() Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return Request{}
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	req := Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Body: body}
+	s.requests = append(s.requests, req)
+
+	status, response := s.respond(r, req, body)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead && response != nil {
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
+
+// respond answers the request with the first matching failure, or by routing it
+func (s *Server) respond(r *http.Request, req Request, body []byte) (int, interface{}) {
+	for _, f := range s.failures {
+		if f.match(req) {
+			return errorResponse(f.status, f.errorType, "injected failure", "")
+		}
+	}
+	return s.route(r, body)
+}
+
+// route dispatches a request on its method and path segments
+func (s *Server) route(r *http.Request, body []byte) (int, interface{}) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] == "" {
+		parts = nil
+	}
+
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodHead:
+		return http.StatusOK, nil
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		return http.StatusOK, map[string]interface{}{
+			"name":         "fakeos",
+			"cluster_name": "fakeos",
+			"version":      map[string]interface{}{"distribution": "opensearch", "number": "2.11.0"},
+		}
+	case len(parts) == 1 && parts[0] == "_bulk":
+		return s.bulk(body)
+	case len(parts) == 1 && parts[0] == "_msearch":
+		return s.msearch(body)
+	case len(parts) == 1 && parts[0] == "_search":
+		return s.searchPointInTime(body)
+	case len(parts) == 2 && parts[0] == "_search" && parts[1] == "point_in_time" && r.Method == http.MethodDelete:
+		return s.closePointInTime(body)
+	case len(parts) == 3 && parts[1] == "_search" && parts[2] == "point_in_time" && r.Method == http.MethodPost:
+		return s.openPointInTime(parts[0])
+	case len(parts) == 1 && parts[0] == "_refresh":
+		return http.StatusOK, map[string]interface{}{"_shards": map[string]interface{}{"failed": 0}}
+	case len(parts) == 1:
+		return s.indexOp(r.Method, parts[0])
+	case len(parts) == 2 && parts[1] == "_search":
+		return s.search(parts[0], body)
+	case len(parts) == 2 && parts[1] == "_refresh":
+		if _, ok := s.indices[parts[0]]; !ok {
+			return indexNotFound(parts[0])
+		}
+		return http.StatusOK, map[string]interface{}{"_shards": map[string]interface{}{"failed": 0}}
+	case len(parts) == 2 && parts[1] == "_doc" && r.Method == http.MethodPost:
+		s.nextID++
+		return s.indexDocument(parts[0], fmt.Sprintf("auto-%d", s.nextID), r, body)
+	case len(parts) == 3 && parts[1] == "_doc":
+		switch r.Method {
+		case http.MethodPut, http.MethodPost:
+			return s.indexDocument(parts[0], parts[2], r, body)
+		case http.MethodGet:
+			return s.getDocument(parts[0], parts[2])
+		case http.MethodDelete:
+			return s.deleteDocument(parts[0], parts[2])
+		}
+	case len(parts) == 3 && parts[1] == "_update" && r.Method == http.MethodPost:
+		return s.updateDocument(parts[0], parts[2], r, body)
+	}
+
+	return errorResponse(http.StatusBadRequest, "illegal_argument_exception",
+		fmt.Sprintf("fakeos does not support %s %s", r.Method, r.URL.Path), "")
+}
+
+// indexOp handles requests on an index itself
+func (s *Server) indexOp(method, name string) (int, interface{}) {
+	_, exists := s.indices[name]
+	switch method {
+	case http.MethodHead:
+		if !exists {
+			return http.StatusNotFound, nil
+		}
+		return http.StatusOK, nil
+	case http.MethodPut:
+		if exists {
+			return errorResponse(http.StatusBadRequest, "resource_already_exists_exception",
+				fmt.Sprintf("index [%s/fake-uuid] already exists", name), name)
+		}
+		s.indices[name] = &index{docs: make(map[string]*document)}
+		return http.StatusOK, map[string]interface{}{"acknowledged": true, "shards_acknowledged": true, "index": name}
+	case http.MethodDelete:
+		if !exists {
+			return indexNotFound(name)
+		}
+		delete(s.indices, name)
+		return http.StatusOK, map[string]interface{}{"acknowledged": true}
+	}
+	return errorResponse(http.StatusMethodNotAllowed, "illegal_argument_exception",
+		fmt.Sprintf("fakeos does not support %s /%s", method, name), "")
+}
+
+func (s *Server) indexDocument(indexName, id string, r *http.Request, body []byte) (int, interface{}) {
+	var source map[string]interface{}
+	if err := json.Unmarshal(body, &source); err != nil {
+		return errorResponse(http.StatusBadRequest, "mapper_parsing_exception", "failed to parse", indexName)
+	}
+
+	idx := s.getOrCreateIndex(indexName)
+	if idx.readOnly {
+		return blocked(indexName)
+	}
+
+	query := r.URL.Query()
+	existing, exists := idx.docs[id]
+	if exists && query.Get("op_type") == "create" {
+		return versionConflict(indexName, id, "document already exists")
+	}
+
+	var version int64
+	if v := query.Get("version"); v != "" {
+		version, _ = strconv.ParseInt(v, 10, 64)
+		if exists {
+			versionType := query.Get("version_type")
+			if (versionType == "external" && version <= existing.version) ||
+				(versionType == "external_gte" && version < existing.version) {
+				return versionConflict(indexName, id, fmt.Sprintf(
+					"current version [%d] is higher or equal to the one provided [%d]", existing.version, version))
+			}
+		}
+	}
+
+	doc := s.store(idx, id, source, version)
+	result := "created"
+	status := http.StatusCreated
+	if exists {
+		result = "updated"
+		status = http.StatusOK
+	}
+	return status, writeResult(indexName, id, doc, result)
+}
+
+func (s *Server) getDocument(indexName, id string) (int, interface{}) {
+	idx, ok := s.indices[indexName]
+	if !ok {
+		return indexNotFound(indexName)
+	}
+	doc, ok := idx.docs[id]
+	if !ok {
+		return http.StatusNotFound, map[string]interface{}{"_index": indexName, "_id": id, "found": false}
+	}
+	return http.StatusOK, map[string]interface{}{
+		"_index":        indexName,
+		"_id":           id,
+		"_version":      doc.version,
+		"_seq_no":       doc.seqNo,
+		"_primary_term": 1,
+		"found":         true,
+		"_source":       doc.source,
+	}
+}
+
+func (s *Server) updateDocument(indexName, id string, r *http.Request, body []byte) (int, interface{}) {
+	var update struct {
+		Doc map[string]interface{} `json:"doc"`
+	}
+	if err := json.Unmarshal(body, &update); err != nil {
+		return errorResponse(http.StatusBadRequest, "x_content_parse_exception", "failed to parse update", indexName)
+	}
+
+	idx, ok := s.indices[indexName]
+	if !ok {
+		return indexNotFound(indexName)
+	}
+	doc, ok := idx.docs[id]
+	if !ok {
+		return errorResponse(http.StatusNotFound, "document_missing_exception",
+			fmt.Sprintf("[%s]: document missing", id), indexName)
+	}
+	if idx.readOnly {
+		return blocked(indexName)
+	}
+
+	query := r.URL.Query()
+	if seqNo := query.Get("if_seq_no"); seqNo != "" {
+		if seqNo != strconv.FormatInt(doc.seqNo, 10) || query.Get("if_primary_term") != "1" {
+			return versionConflict(indexName, id, fmt.Sprintf(
+				"required seqNo [%s], primary term [%s]. current document has seqNo [%d] and primary term [1]",
+				seqNo, query.Get("if_primary_term"), doc.seqNo))
+		}
+	}
+
+	source := make(map[string]interface{}, len(doc.source)+len(update.Doc))
+	for k, v := range doc.source {
+		source[k] = v
+	}
+	for k, v := range update.Doc {
+		source[k] = v
+	}
+	doc = s.store(idx, id, source, 0)
+	return http.StatusOK, writeResult(indexName, id, doc, "updated")
+}
+
+func (s *Server) deleteDocument(indexName, id string) (int, interface{}) {
+	idx, ok := s.indices[indexName]
+	if !ok {
+		return indexNotFound(indexName)
+	}
+	doc, ok := idx.docs[id]
+	if !ok {
+		return http.StatusNotFound, map[string]interface{}{"_index": indexName, "_id": id, "result": "not_found"}
+	}
+	if idx.readOnly {
+		return blocked(indexName)
+	}
+	delete(idx.docs, id)
+	return http.StatusOK, writeResult(indexName, id, doc, "deleted")
+}
+
+func (s *Server) search(indexName string, body []byte) (int, interface{}) {
+	idx, ok := s.indices[indexName]
+	if !ok {
+		return indexNotFound(indexName)
+	}
+
+	var request map[string]interface{}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &request); err != nil {
+			return errorResponse(http.StatusBadRequest, "parsing_exception", "failed to parse search body", indexName)
+		}
+	}
+	return s.searchDocs(indexName, idx.docs, request)
+}
+
+func (s *Server) openPointInTime(indexName string) (int, interface{}) {
+	idx, ok := s.indices[indexName]
+	if !ok {
+		return indexNotFound(indexName)
+	}
+
+	docs := make(map[string]*document, len(idx.docs))
+	for id, doc := range idx.docs {
+		copied := *doc
+		docs[id] = &copied
+	}
+	s.nextID++
+	id := fmt.Sprintf("pit-%d", s.nextID)
+	s.pits[id] = &pointInTime{index: indexName, docs: docs}
+
+	return http.StatusOK, map[string]interface{}{
+		"pit_id":        id,
+		"_shards":       map[string]interface{}{"total": 1, "successful": 1, "skipped": 0, "failed": 0},
+		"creation_time": 0,
+	}
+}
+
+func (s *Server) closePointInTime(body []byte) (int, interface{}) {
+	var request struct {
+		PitID []string `json:"pit_id"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return errorResponse(http.StatusBadRequest, "parsing_exception", "failed to parse pit_id", "")
+	}
+
+	var pits []interface{}
+	for _, id := range request.PitID {
+		if _, ok := s.pits[id]; !ok {
+			return errorResponse(http.StatusNotFound, "search_context_missing_exception",
+				fmt.Sprintf("No search context found for id [%s]", id), "")
+		}
+		delete(s.pits, id)
+		pits = append(pits, map[string]interface{}{"pit_id": id, "successful": true})
+	}
+	return http.StatusOK, map[string]interface{}{"pits": pits}
+}
+
+// searchPointInTime runs a search that names its point in time in the body
+func (s *Server) searchPointInTime(body []byte) (int, interface{}) {
+	var request map[string]interface{}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return errorResponse(http.StatusBadRequest, "parsing_exception", "failed to parse search body", "")
+	}
+
+	pitParams, _ := request["pit"].(map[string]interface{})
+	id, _ := pitParams["id"].(string)
+	pit, ok := s.pits[id]
+	if !ok {
+		return errorResponse(http.StatusNotFound, "search_context_missing_exception",
+			fmt.Sprintf("No search context found for id [%s]", id), "")
+	}
+	return s.searchDocs(pit.index, pit.docs, request)
+}
+
+// searchDocs runs a parsed search request against the documents of an index
+func (s *Server) searchDocs(indexName string, docs map[string]*document, request map[string]interface{}) (int, interface{}) {
+	query, _ := request["query"].(map[string]interface{})
+	if query == nil {
+		query = map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+	sortFields, err := parseSort(request["sort"])
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "parsing_exception", err.Error(), indexName)
+	}
+
+	ids := make([]string, 0, len(docs))
+	for id := range docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	type match struct {
+		hit  map[string]interface{}
+		sort []interface{}
+	}
+	var matched []match
+	for position, id := range ids {
+		ok, err := matches(query, id, docs[id].source)
+		if err != nil {
+			return errorResponse(http.StatusBadRequest, "parsing_exception", err.Error(), indexName)
+		}
+		if !ok {
+			continue
+		}
+		hit := map[string]interface{}{
+			"_index":  indexName,
+			"_id":     id,
+			"_score":  1.0,
+			"_source": docs[id].source,
+		}
+		var values []interface{}
+		if sortFields != nil {
+			values = sortValues(sortFields, position, id, docs[id].source)
+			hit["sort"] = values
+		}
+		matched = append(matched, match{hit: hit, sort: values})
+	}
+	total := len(matched)
+
+	if sortFields != nil {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return compareSort(sortFields, matched[i].sort, matched[j].sort) < 0
+		})
+	}
+
+	var hits []interface{}
+	after, hasAfter := request["search_after"].([]interface{})
+	for _, m := range matched {
+		if hasAfter && compareSort(sortFields, m.sort, after) <= 0 {
+			continue
+		}
+		hits = append(hits, m.hit)
+	}
+
+	from := intParam(request["from"], 0)
+	size := intParam(request["size"], 10)
+	if from > len(hits) {
+		from = len(hits)
+	}
+	hits = hits[from:]
+	if size < len(hits) {
+		hits = hits[:size]
+	}
+	if hits == nil {
+		hits = []interface{}{}
+	}
+
+	return http.StatusOK, map[string]interface{}{
+		"took":      1,
+		"timed_out": false,
+		"hits": map[string]interface{}{
+			"total":     map[string]interface{}{"value": total, "relation": "eq"},
+			"max_score": 1.0,
+			"hits":      hits,
+		},
+	}
+}
+
+func (s *Server) msearch(body []byte) (int, interface{}) {
+	lines := ndjsonLines(body)
+	if len(lines)%2 != 0 {
+		return errorResponse(http.StatusBadRequest, "illegal_argument_exception", "msearch body must pair headers with queries", "")
+	}
+
+	responses := make([]interface{}, 0, len(lines)/2)
+	for i := 0; i < len(lines); i += 2 {
+		var header struct {
+			Index string `json:"index"`
+		}
+		var request map[string]interface{}
+		if err := json.Unmarshal(lines[i], &header); err != nil {
+			return errorResponse(http.StatusBadRequest, "parsing_exception", "failed to parse msearch header", "")
+		}
+		if err := json.Unmarshal(lines[i+1], &request); err != nil {
+			return errorResponse(http.StatusBadRequest, "parsing_exception", "failed to parse msearch query", "")
+		}
+
+		var status int
+		var response interface{}
+		if idx, ok := s.indices[header.Index]; ok {
+			status, response = s.searchDocs(header.Index, idx.docs, request)
+		} else {
+			status, response = indexNotFound(header.Index)
+		}
+		response.(map[string]interface{})["status"] = status
+		responses = append(responses, response)
+	}
+
+	return http.StatusOK, map[string]interface{}{"took": 1, "responses": responses}
+}
+
+func (s *Server) bulk(body []byte) (int, interface{}) {
+	lines := ndjsonLines(body)
+	var items []interface{}
+	hasErrors := false
+
+	for i := 0; i < len(lines); i++ {
+		var action map[string]struct {
+			Index string `json:"_index"`
+			ID    string `json:"_id"`
+		}
+		if err := json.Unmarshal(lines[i], &action); err != nil || len(action) != 1 {
+			return errorResponse(http.StatusBadRequest, "illegal_argument_exception", "malformed action/metadata line", "")
+		}
+
+		for op, meta := range action {
+			id := meta.ID
+			if id == "" {
+				s.nextID++
+				id = fmt.Sprintf("auto-%d", s.nextID)
+			}
+
+			var source map[string]interface{}
+			if op != "delete" {
+				i++
+				if i >= len(lines) || json.Unmarshal(lines[i], &source) != nil {
+					return errorResponse(http.StatusBadRequest, "illegal_argument_exception", "action is missing its document", "")
+				}
+			}
+
+			item := s.bulkItem(op, meta.Index, id, source)
+			if _, failed := item["error"]; failed {
+				hasErrors = true
+			}
+			items = append(items, map[string]interface{}{op: item})
+		}
+	}
+
+	return http.StatusOK, map[string]interface{}{"took": 1, "errors": hasErrors, "items": items}
+}
+
+// bulkItem applies one bulk action and returns its item in the bulk response
+func (s *Server) bulkItem(op, indexName, id string, source map[string]interface{}) map[string]interface{} {
+	itemError := func(status int, errorType, reason string) map[string]interface{} {
+		return map[string]interface{}{
+			"_index": indexName,
+			"_id":    id,
+			"status": status,
+			"error":  map[string]interface{}{"type": errorType, "reason": reason, "index": indexName},
+		}
+	}
+
+	idx := s.getOrCreateIndex(indexName)
+	if idx.readOnly {
+		return itemError(http.StatusForbidden, "cluster_block_exception", blockReason(indexName))
+	}
+
+	_, exists := idx.docs[id]
+	switch op {
+	case "index", "create":
+		if exists && op == "create" {
+			return itemError(http.StatusConflict, "version_conflict_engine_exception",
+				fmt.Sprintf("[%s]: version conflict, document already exists", id))
+		}
+		doc := s.store(idx, id, source, 0)
+		item := writeResult(indexName, id, doc, "created")
+		item["status"] = http.StatusCreated
+		if exists {
+			item["result"] = "updated"
+			item["status"] = http.StatusOK
+		}
+		return item
+	case "delete":
+		if !exists {
+			return map[string]interface{}{"_index": indexName, "_id": id, "status": http.StatusNotFound, "result": "not_found"}
+		}
+		doc := idx.docs[id]
+		delete(idx.docs, id)
+		item := writeResult(indexName, id, doc, "deleted")
+		item["status"] = http.StatusOK
+		return item
+	}
+	return itemError(http.StatusBadRequest, "illegal_argument_exception", fmt.Sprintf("fakeos does not support bulk %s", op))
+}
+
+func (s *Server) getOrCreateIndex(name string) *index {
+	idx, ok := s.indices[name]
+	if !ok {
+		idx = &index{docs: make(map[string]*document)}
+		s.indices[name] = idx
+	}
+	return idx
+}
+
+// store writes the document, bumping its version unless an explicit version is given
+func (s *Server) store(idx *index, id string, source map[string]interface{}, version int64) *document {
+	doc, ok := idx.docs[id]
+	if !ok {
+		doc = &document{seqNo: -1}
+		idx.docs[id] = doc
+	}
+	if version == 0 {
+		version = doc.version + 1
+	}
+	doc.source = source
+	doc.version = version
+	doc.seqNo++
+	return doc
+}
+
+// sortField is a field of a search's sort
+type sortField struct {
+	field string
+	desc  bool
+}
+
+// parseSort reads the sort of a search, e.g. ["_doc"] or [{"year": {"order": "desc"}}]
+func parseSort(v interface{}) ([]sortField, error) {
+	if v == nil {
+		return nil, nil
+	}
+	entries, ok := v.([]interface{})
+	if !ok {
+		entries = []interface{}{v}
+	}
+
+	fields := make([]sortField, 0, len(entries))
+	for _, entry := range entries {
+		switch e := entry.(type) {
+		case string:
+			fields = append(fields, sortField{field: e})
+		case map[string]interface{}:
+			for field, order := range e {
+				if params, ok := order.(map[string]interface{}); ok {
+					order = params["order"]
+				}
+				fields = append(fields, sortField{field: field, desc: order == "desc"})
+			}
+		default:
+			return nil, fmt.Errorf("malformed sort [%v]", entry)
+		}
+	}
+	return fields, nil
+}
+
+// sortValues returns the sort values of a hit; _doc sorts on the position in the index
+func sortValues(fields []sortField, position int, id string, source map[string]interface{}) []interface{} {
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		switch f.field {
+		case "_doc":
+			values[i] = float64(position)
+		case "_id":
+			values[i] = id
+		default:
+			values[i] = source[f.field]
+		}
+	}
+	return values
+}
+
+// compareSort compares two sets of sort values in the order of fields; missing values sort last
+func compareSort(fields []sortField, a, b []interface{}) int {
+	for i, f := range fields {
+		if i >= len(a) || i >= len(b) {
+			break
+		}
+		c := compareValues(a[i], b[i])
+		if c == 0 {
+			continue
+		}
+		if f.desc && a[i] != nil && b[i] != nil {
+			c = -c
+		}
+		return c
+	}
+	return 0
+}
+
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// matches reports whether the document matches the query
+func matches(query map[string]interface{}, id string, source map[string]interface{}) (bool, error) {
+	if len(query) != 1 {
+		return false, fmt.Errorf("query must have exactly one clause, got %d", len(query))
+	}
+
+	for kind, clause := range query {
+		params, _ := clause.(map[string]interface{})
+		switch kind {
+		case "match_all":
+			return true, nil
+		case "ids":
+			values, _ := params["values"].([]interface{})
+			for _, v := range values {
+				if v == id {
+					return true, nil
+				}
+			}
+			return false, nil
+		case "term", "match":
+			for field, want := range params {
+				if m, ok := want.(map[string]interface{}); ok {
+					want = m["query"]
+					if want == nil {
+						want = m["value"]
+					}
+				}
+				if kind == "match" {
+					return matchText(source[field], want), nil
+				}
+				return equal(source[field], want), nil
+			}
+			return false, nil
+		case "terms":
+			for field, want := range params {
+				values, _ := want.([]interface{})
+				for _, v := range values {
+					if equal(source[field], v) {
+						return true, nil
+					}
+				}
+			}
+			return false, nil
+		case "bool":
+			return matchBool(params, id, source)
+		case "percolate":
+			// The hit is a stored query that matches the percolated document
+			field, _ := params["field"].(string)
+			stored, ok := source[field].(map[string]interface{})
+			if !ok {
+				return false, nil
+			}
+			document, _ := params["document"].(map[string]interface{})
+			return matches(stored, "", document)
+		}
+		return false, fmt.Errorf("unknown query [%s]", kind)
+	}
+	return false, nil
+}
+
+// matchBool evaluates the must, filter, should and must_not clauses of a bool query
+func matchBool(params map[string]interface{}, id string, source map[string]interface{}) (bool, error) {
+	clauses := func(key string) []map[string]interface{} {
+		switch v := params[key].(type) {
+		case map[string]interface{}:
+			return []map[string]interface{}{v}
+		case []interface{}:
+			var out []map[string]interface{}
+			for _, c := range v {
+				if m, ok := c.(map[string]interface{}); ok {
+					out = append(out, m)
+				}
+			}
+			return out
+		}
+		return nil
+	}
+
+	for _, key := range []string{"must", "filter"} {
+		for _, c := range clauses(key) {
+			ok, err := matches(c, id, source)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+	}
+	for _, c := range clauses("must_not") {
+		ok, err := matches(c, id, source)
+		if err != nil || ok {
+			return false, err
+		}
+	}
+	should := clauses("should")
+	if len(should) == 0 {
+		return true, nil
+	}
+	for _, c := range should {
+		ok, err := matches(c, id, source)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// equal compares JSON values, treating numbers of any type alike
+func equal(got, want interface{}) bool {
+	return fmt.Sprint(got) == fmt.Sprint(want)
+}
+
+// matchText reports whether any word of want appears in the text of got, ignoring case
+func matchText(got, want interface{}) bool {
+	text, ok := got.(string)
+	if !ok {
+		return equal(got, want)
+	}
+	words := strings.Fields(strings.ToLower(text))
+	for _, w := range strings.Fields(strings.ToLower(fmt.Sprint(want))) {
+		for _, word := range words {
+			if word == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func intParam(v interface{}, fallback int) int {
+	if n, ok := v.(float64); ok {
+		return int(n)
+	}
+	return fallback
+}
+
+func ndjsonLines(body []byte) [][]byte {
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	return lines
+}
+
+func writeResult(indexName, id string, doc *document, result string) map[string]interface{} {
+	return map[string]interface{}{
+		"_index":        indexName,
+		"_id":           id,
+		"_version":      doc.version,
+		"_seq_no":       doc.seqNo,
+		"_primary_term": 1,
+		"result":        result,
+	}
+}
+
+// errorResponse builds an OpenSearch error body
+func errorResponse(status int, errorType, reason, indexName string) (int, interface{}) {
+	cause := map[string]interface{}{"type": errorType, "reason": reason}
+	if indexName != "" {
+		cause["index"] = indexName
+	}
+	errorBody := map[string]interface{}{"root_cause": []interface{}{cause}}
+	for k, v := range cause {
+		errorBody[k] = v
+	}
+	return status, map[string]interface{}{"error": errorBody, "status": status}
+}
+
+func indexNotFound(indexName string) (int, interface{}) {
+	return errorResponse(http.StatusNotFound, "index_not_found_exception",
+		fmt.Sprintf("no such index [%s]", indexName), indexName)
+}
+
+func versionConflict(indexName, id, detail string) (int, interface{}) {
+	return errorResponse(http.StatusConflict, "version_conflict_engine_exception",
+		fmt.Sprintf("[%s]: version conflict, %s", id, detail), indexName)
+}
+
+func blocked(indexName string) (int, interface{}) {
+	return errorResponse(http.StatusForbidden, "cluster_block_exception", blockReason(indexName), indexName)
+}
+
+func blockReason(indexName string) string {
+	return fmt.Sprintf("index [%s] blocked by: [FORBIDDEN/8/index write (api)];", indexName)
+}
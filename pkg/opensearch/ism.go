@@ -0,0 +1,225 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ismPath is the base path of the Index State Management plugin API
+const ismPath = "/_plugins/_ism"
+
+// ISMExplanation describes where a managed index currently is in its ISM policy
+type ISMExplanation struct {
+	Index    string
+	PolicyID string
+	State    string
+	Action   string
+	Enabled  bool
+	// Raw is the full explain entry for the index
+	Raw map[string]interface{}
+}
+
+// PutISMPolicy creates an ISM policy or replaces an existing one. policy is the body of
+// the "policy" object (description, default_state, states, ...); a body already wrapped
+// in {"policy": ...} is accepted as is. Updates are made conditional on the policy's
+// current seq_no and primary_term, as the ISM API requires.
+func (c *Client) PutISMPolicy(ctx context.Context, name string, policy map[string]interface{}) (err error) {
+	defer c.observe("PutISMPolicy", time.Now(), &err)
+
+	if _, wrapped := policy["policy"]; !wrapped || len(policy) != 1 {
+		policy = map[string]interface{}{"policy": policy}
+	}
+
+	body, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ISM policy: %w", err)
+	}
+
+	params := url.Values{}
+	current, err := c.getISMPolicy(ctx, name)
+	if err == nil {
+		params.Set("if_seq_no", strconv.Itoa(current.SeqNo))
+		params.Set("if_primary_term", strconv.Itoa(current.PrimaryTerm))
+	} else if !errors.Is(err, ErrISMPolicyNotFound) {
+		return err
+	}
+
+	res, err := c.perform(ctx, http.MethodPut, ismPath+"/policies/"+url.PathEscape(name), params, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to put ISM policy: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 409 {
+			return responseError(res, fmt.Sprintf("put ISM policy %s: %v", name, ErrVersionConflict), ErrVersionConflict)
+		}
+		return requestError("put ISM policy", res)
+	}
+
+	return nil
+}
+
+// GetISMPolicy returns the "policy" object of an ISM policy
+func (c *Client) GetISMPolicy(ctx context.Context, name string) (_ map[string]interface{}, err error) {
+	defer c.observe("GetISMPolicy", time.Now(), &err)
+
+	response, err := c.getISMPolicy(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return response.Policy, nil
+}
+
+// DeleteISMPolicy deletes an ISM policy
+func (c *Client) DeleteISMPolicy(ctx context.Context, name string) (err error) {
+	defer c.observe("DeleteISMPolicy", time.Now(), &err)
+
+	res, err := c.perform(ctx, http.MethodDelete, ismPath+"/policies/"+url.PathEscape(name), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete ISM policy: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, fmt.Sprintf("ISM policy %s not found", name), ErrISMPolicyNotFound)
+		}
+		return requestError("delete ISM policy", res)
+	}
+
+	return nil
+}
+
+// AddISMPolicyToIndex attaches an ISM policy to an index (or index pattern)
+func (c *Client) AddISMPolicyToIndex(ctx context.Context, index, policy string) (err error) {
+	defer c.observe("AddISMPolicyToIndex", time.Now(), &err)
+
+	body, err := json.Marshal(map[string]interface{}{"policy_id": policy})
+	if err != nil {
+		return fmt.Errorf("failed to marshal ISM add request: %w", err)
+	}
+
+	res, err := c.perform(ctx, http.MethodPost, ismPath+"/add/"+url.PathEscape(index), nil, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to add ISM policy: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return requestError("add ISM policy", res)
+	}
+
+	var response struct {
+		Failures      bool `json:"failures"`
+		FailedIndices []struct {
+			IndexName string `json:"index_name"`
+			Reason    string `json:"reason"`
+		} `json:"failed_indices"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return err
+	}
+
+	if response.Failures && len(response.FailedIndices) > 0 {
+		failed := response.FailedIndices[0]
+		return fmt.Errorf("failed to add ISM policy %s to index %s: %s", policy, failed.IndexName, failed.Reason)
+	}
+
+	return nil
+}
+
+// ExplainISM returns the ISM state of an index. State and Action are empty until
+// the ISM job has initialized the policy on the index.
+func (c *Client) ExplainISM(ctx context.Context, index string) (_ ISMExplanation, err error) {
+	defer c.observe("ExplainISM", time.Now(), &err)
+
+	res, err := c.perform(ctx, http.MethodGet, ismPath+"/explain/"+url.PathEscape(index), nil, nil)
+	if err != nil {
+		return ISMExplanation{}, fmt.Errorf("failed to explain ISM: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return ISMExplanation{}, responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return ISMExplanation{}, requestError("explain ISM", res)
+	}
+
+	var response map[string]json.RawMessage
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return ISMExplanation{}, err
+	}
+
+	raw, ok := response[index]
+	if !ok {
+		return ISMExplanation{}, fmt.Errorf("index %s not found: %w", index, ErrIndexNotFound)
+	}
+
+	var entry struct {
+		PolicyID string `json:"policy_id"`
+		Enabled  *bool  `json:"enabled"`
+		State    struct {
+			Name string `json:"name"`
+		} `json:"state"`
+		Action struct {
+			Name string `json:"name"`
+		} `json:"action"`
+	}
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return ISMExplanation{}, fmt.Errorf("failed to parse ISM explanation: %w", err)
+	}
+
+	explanation := ISMExplanation{
+		Index:    index,
+		PolicyID: entry.PolicyID,
+		State:    entry.State.Name,
+		Action:   entry.Action.Name,
+		Enabled:  entry.Enabled == nil || *entry.Enabled,
+	}
+	if err := c.parseResponse(bytes.NewReader(raw), &explanation.Raw); err != nil {
+		return ISMExplanation{}, fmt.Errorf("failed to parse ISM explanation: %w", err)
+	}
+
+	return explanation, nil
+}
+
+// ismPolicyResponse represents the response of a get ISM policy request
+type ismPolicyResponse struct {
+	ID          string                 `json:"_id"`
+	Version     int                    `json:"_version"`
+	SeqNo       int                    `json:"_seq_no"`
+	PrimaryTerm int                    `json:"_primary_term"`
+	Policy      map[string]interface{} `json:"policy"`
+}
+
+// getISMPolicy fetches an ISM policy along with its concurrency metadata
+func (c *Client) getISMPolicy(ctx context.Context, name string) (*ismPolicyResponse, error) {
+	res, err := c.perform(ctx, http.MethodGet, ismPath+"/policies/"+url.PathEscape(name), nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ISM policy: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, fmt.Sprintf("ISM policy %s not found", name), ErrISMPolicyNotFound)
+		}
+		return nil, requestError("get ISM policy", res)
+	}
+
+	var response ismPolicyResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
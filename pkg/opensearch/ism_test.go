@@ -0,0 +1,112 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// rolloverPolicy returns a trivial ISM policy that rolls an index over at maxDocs documents
+func rolloverPolicy(maxDocs int) map[string]interface{} {
+	return map[string]interface{}{
+		"description":   "Roll over test indices",
+		"default_state": "hot",
+		"states": []interface{}{
+			map[string]interface{}{
+				"name": "hot",
+				"actions": []interface{}{
+					map[string]interface{}{
+						"rollover": map[string]interface{}{"min_doc_count": maxDocs},
+					},
+				},
+				"transitions": []interface{}{},
+			},
+		},
+	}
+}
+
+func TestISMPolicies(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	policyName := "test-ism-rollover"
+	indexName := "test-ism-000001"
+
+	_ = client.DeleteISMPolicy(ctx, policyName)
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+	defer func() { _ = client.DeleteISMPolicy(ctx, policyName) }()
+
+	if err := client.PutISMPolicy(ctx, policyName, rolloverPolicy(3)); err != nil {
+		t.Fatalf("PutISMPolicy() create error = %v", err)
+	}
+
+	t.Run("Get policy", func(t *testing.T) {
+		policy, err := client.GetISMPolicy(ctx, policyName)
+		if err != nil {
+			t.Fatalf("GetISMPolicy() error = %v", err)
+		}
+		if policy["default_state"] != "hot" {
+			t.Errorf("default_state = %v, want hot", policy["default_state"])
+		}
+	})
+
+	t.Run("Update existing policy", func(t *testing.T) {
+		updated := rolloverPolicy(5)
+		updated["description"] = "Updated rollover"
+		if err := client.PutISMPolicy(ctx, policyName, updated); err != nil {
+			t.Fatalf("PutISMPolicy() update error = %v", err)
+		}
+
+		policy, err := client.GetISMPolicy(ctx, policyName)
+		if err != nil {
+			t.Fatalf("GetISMPolicy() error = %v", err)
+		}
+		if policy["description"] != "Updated rollover" {
+			t.Errorf("description = %v, want updated value", policy["description"])
+		}
+	})
+
+	t.Run("Attach and explain", func(t *testing.T) {
+		if err := client.AddISMPolicyToIndex(ctx, indexName, policyName); err != nil {
+			t.Fatalf("AddISMPolicyToIndex() error = %v", err)
+		}
+
+		explanation, err := client.ExplainISM(ctx, indexName)
+		if err != nil {
+			t.Fatalf("ExplainISM() error = %v", err)
+		}
+		if explanation.PolicyID != policyName {
+			t.Errorf("PolicyID = %q, want %q", explanation.PolicyID, policyName)
+		}
+	})
+
+	t.Run("Missing policy", func(t *testing.T) {
+		if _, err := client.GetISMPolicy(ctx, "test-ism-missing"); err == nil {
+			t.Error("GetISMPolicy() on missing policy expected error but got nil")
+		}
+	})
+}
+
+func TestExplainISM_MissingIndex(t *testing.T) {
+	client := newStubClient(t, &stubTransport{status: 200, body: `{"total_managed_indices":0}`})
+
+	_, err := client.ExplainISM(context.Background(), "logs-1")
+	if !errors.Is(err, ErrIndexNotFound) {
+		t.Errorf("ExplainISM() error = %v, want ErrIndexNotFound", err)
+	}
+}
+
+func TestISMPolicyNotFound(t *testing.T) {
+	missing := `{"error":{"type":"status_exception","reason":"Policy not found"},"status":404}`
+
+	client := newStubClient(t, &stubTransport{status: 404, body: missing})
+	if _, err := client.GetISMPolicy(context.Background(), "hot-warm"); !errors.Is(err, ErrISMPolicyNotFound) {
+		t.Errorf("GetISMPolicy() error = %v, want ErrISMPolicyNotFound", err)
+	}
+
+	client = newStubClient(t, &stubTransport{status: 404, body: missing})
+	if err := client.DeleteISMPolicy(context.Background(), "hot-warm"); !errors.Is(err, ErrISMPolicyNotFound) {
+		t.Errorf("DeleteISMPolicy() error = %v, want ErrISMPolicyNotFound", err)
+	}
+}
@@ -0,0 +1,163 @@
+package opensearch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Operations reported in RequestMetrics.Operation
+const (
+	OperationSearch = "search"
+	OperationIndex  = "index"
+	OperationGet    = "get"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	OperationBulk   = "bulk"
+	OperationOther  = "other"
+)
+
+// MetricsCollector receives a RequestMetrics for every HTTP request the client sends,
+// including each retry. ObserveRequest is called concurrently and should not block.
+type MetricsCollector interface {
+	ObserveRequest(metrics RequestMetrics)
+}
+
+// Observer is told about every call of a Client method, e.g. to export per-method latency
+// and error counts. ObserveRequest is called concurrently and should not block.
+type Observer interface {
+	// ObserveRequest receives the name of the method, e.g. "CreateDocument", how long the
+	// call took, retries included, and the error it returned, if any
+	ObserveRequest(op string, duration time.Duration, err error)
+}
+
+// observe reports a call of the method op that started at start and returned *err to the
+// client's Observer, if any. Call it deferred, with a pointer to the method's error result.
+func (c *Client) observe(op string, start time.Time, err *error) {
+	if c.observer == nil {
+		return
+	}
+	c.observer.ObserveRequest(op, time.Since(start), *err)
+}
+
+// RequestMetrics describes one HTTP request sent to the cluster
+type RequestMetrics struct {
+	// Operation is one of the Operation constants, derived from the request's method and path
+	Operation string
+	// Index is the index (or comma-separated indices) in the request path, if any
+	Index string
+	// Status is the response status, or 0 when no response was received
+	Status int
+	// StatusClass is "2xx", "4xx", "5xx" etc., or "error" when no response was received
+	StatusClass string
+	Duration    time.Duration
+	// RequestBytes is the size of the request body
+	RequestBytes int64
+	// BulkDocuments is the number of actions in a bulk request
+	BulkDocuments int
+}
+
+// metricsTransport reports each request attempt to a MetricsCollector
+type metricsTransport struct {
+	next      http.RoundTripper
+	collector MetricsCollector
+}
+
+// newMetricsTransport wraps next so that requests are reported to collector
+func newMetricsTransport(next http.RoundTripper, collector MetricsCollector) *metricsTransport {
+	return &metricsTransport{next: next, collector: collector}
+}
+
+// RoundTrip sends the request and reports it once the response headers are received
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	metrics := RequestMetrics{RequestBytes: req.ContentLength}
+	metrics.Operation, metrics.Index = classifyRequest(req.Method, req.URL.Path)
+
+	if metrics.Operation == OperationBulk && req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bulk request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		metrics.RequestBytes = int64(len(body))
+		metrics.BulkDocuments = countBulkActions(body)
+	}
+	if metrics.RequestBytes < 0 {
+		metrics.RequestBytes = 0
+	}
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	metrics.Duration = time.Since(start)
+
+	if err != nil {
+		metrics.StatusClass = "error"
+	} else {
+		metrics.Status = res.StatusCode
+		metrics.StatusClass = fmt.Sprintf("%dxx", res.StatusCode/100)
+	}
+	t.collector.ObserveRequest(metrics)
+
+	return res, err
+}
+
+// classifyRequest derives the operation and index of a request from its method and path
+func classifyRequest(method, path string) (operation, index string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 0 && segments[0] != "" && !strings.HasPrefix(segments[0], "_") {
+		index = segments[0]
+	}
+
+	for _, segment := range segments {
+		switch segment {
+		case "_bulk":
+			return OperationBulk, index
+		case "_search", "_msearch", "_count":
+			return OperationSearch, index
+		case "_update", "_update_by_query":
+			return OperationUpdate, index
+		case "_delete_by_query":
+			return OperationDelete, index
+		case "_doc", "_create", "_source":
+			switch method {
+			case http.MethodGet, http.MethodHead:
+				return OperationGet, index
+			case http.MethodDelete:
+				return OperationDelete, index
+			default:
+				return OperationIndex, index
+			}
+		}
+	}
+
+	if method == http.MethodDelete {
+		return OperationDelete, index
+	}
+	return OperationOther, index
+}
+
+// countBulkActions counts the actions in a bulk body. Every action except delete is
+// followed by a source line.
+func countBulkActions(body []byte) int {
+	actions := 0
+	expectSource := false
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if expectSource {
+			expectSource = false
+			continue
+		}
+		actions++
+		expectSource = !bytes.HasPrefix(bytes.TrimLeft(line[1:], " "), []byte(`"delete"`))
+	}
+	return actions
+}
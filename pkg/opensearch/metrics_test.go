@@ -0,0 +1,216 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yenonn/go-opensearch/pkg/opensearch/internal/fakeos"
+)
+
+// recordingCollector keeps every RequestMetrics it receives
+type recordingCollector struct {
+	mu       sync.Mutex
+	requests []RequestMetrics
+}
+
+func (c *recordingCollector) ObserveRequest(metrics RequestMetrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, metrics)
+}
+
+func (c *recordingCollector) last(t *testing.T) RequestMetrics {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.requests) == 0 {
+		t.Fatal("No request was reported")
+	}
+	return c.requests[len(c.requests)-1]
+}
+
+func TestClassifyRequest(t *testing.T) {
+	tests := []struct {
+		method        string
+		path          string
+		wantOperation string
+		wantIndex     string
+	}{
+		{method: "POST", path: "/my-index/_search", wantOperation: OperationSearch, wantIndex: "my-index"},
+		{method: "POST", path: "/_search/scroll", wantOperation: OperationSearch},
+		{method: "POST", path: "/logs-a,logs-b/_count", wantOperation: OperationSearch, wantIndex: "logs-a,logs-b"},
+		{method: "PUT", path: "/my-index/_doc/1", wantOperation: OperationIndex, wantIndex: "my-index"},
+		{method: "PUT", path: "/my-index/_create/1", wantOperation: OperationIndex, wantIndex: "my-index"},
+		{method: "GET", path: "/my-index/_doc/1", wantOperation: OperationGet, wantIndex: "my-index"},
+		{method: "DELETE", path: "/my-index/_doc/1", wantOperation: OperationDelete, wantIndex: "my-index"},
+		{method: "POST", path: "/my-index/_update/1", wantOperation: OperationUpdate, wantIndex: "my-index"},
+		{method: "POST", path: "/my-index/_delete_by_query", wantOperation: OperationDelete, wantIndex: "my-index"},
+		{method: "POST", path: "/_bulk", wantOperation: OperationBulk},
+		{method: "POST", path: "/my-index/_bulk", wantOperation: OperationBulk, wantIndex: "my-index"},
+		{method: "DELETE", path: "/my-index", wantOperation: OperationDelete, wantIndex: "my-index"},
+		{method: "GET", path: "/_cluster/health", wantOperation: OperationOther},
+		{method: "HEAD", path: "/", wantOperation: OperationOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			operation, index := classifyRequest(tt.method, tt.path)
+			if operation != tt.wantOperation || index != tt.wantIndex {
+				t.Errorf("classifyRequest() = %q, %q, want %q, %q", operation, index, tt.wantOperation, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestCountBulkActions(t *testing.T) {
+	body := `{"index":{"_index":"a","_id":"1"}}
+{"title":"one"}
+{"delete":{"_index":"a","_id":"2"}}
+{ "create":{"_index":"a","_id":"3"}}
+{"title":"three"}
+{"update":{"_index":"a","_id":"4"}}
+{"doc":{"title":"four"}}
+`
+	if got := countBulkActions([]byte(body)); got != 4 {
+		t.Errorf("countBulkActions() = %d, want 4", got)
+	}
+}
+
+func TestMetricsCollector(t *testing.T) {
+	collector := &recordingCollector{}
+	stub := &stubTransport{status: 200, body: `{"took":1,"errors":false,"items":[],"hits":{"hits":[]}}`}
+	client, err := newClient(Config{
+		Addresses:    []string{"http://stub:9200"},
+		DisableRetry: true,
+		Metrics:      collector,
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := client.SearchDocuments(ctx, "my-index", MatchAllQuery()); err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+	search := collector.last(t)
+	if search.Operation != OperationSearch || search.Index != "my-index" || search.StatusClass != "2xx" || search.RequestBytes == 0 {
+		t.Errorf("Search metrics = %+v, want a 2xx search of my-index with a body", search)
+	}
+
+	docs := []map[string]interface{}{{"_id": "1", "n": 1}, {"_id": "2", "n": 2}, {"_id": "3", "n": 3}}
+	if err := client.BulkCreate(ctx, "my-index", docs); err != nil {
+		t.Fatalf("BulkCreate() error = %v", err)
+	}
+	bulk := collector.last(t)
+	if bulk.Operation != OperationBulk || bulk.BulkDocuments != 3 || bulk.RequestBytes != int64(len(stub.sent)) {
+		t.Errorf("Bulk metrics = %+v, want 3 documents in %d bytes", bulk, len(stub.sent))
+	}
+
+	stub.status = 404
+	_ = client.DeleteDocument(ctx, "my-index", "missing")
+	deleted := collector.last(t)
+	if deleted.Operation != OperationDelete || deleted.Status != 404 || deleted.StatusClass != "4xx" {
+		t.Errorf("Delete metrics = %+v, want a 4xx delete", deleted)
+	}
+}
+
+// observation is a call reported to an Observer
+type observation struct {
+	op       string
+	duration time.Duration
+	err      error
+}
+
+// recordingObserver keeps every call it is told about
+type recordingObserver struct {
+	mu    sync.Mutex
+	calls []observation
+}
+
+func (o *recordingObserver) ObserveRequest(op string, duration time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, observation{op: op, duration: duration, err: err})
+}
+
+func (o *recordingObserver) take() []observation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	calls := o.calls
+	o.calls = nil
+	return calls
+}
+
+func TestObserver(t *testing.T) {
+	server := fakeos.New(t)
+	observer := &recordingObserver{}
+	client, err := NewClient(Config{
+		Addresses:    []string{server.URL},
+		DisableRetry: true,
+		Observer:     observer,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	t.Run("successful call", func(t *testing.T) {
+		if err := client.CreateDocument(ctx, "books", "1", map[string]interface{}{"title": "Go"}); err != nil {
+			t.Fatalf("CreateDocument() error = %v", err)
+		}
+		calls := observer.take()
+		if len(calls) != 1 {
+			t.Fatalf("observed %d calls, want 1: %+v", len(calls), calls)
+		}
+		if calls[0].op != "CreateDocument" || calls[0].err != nil || calls[0].duration <= 0 {
+			t.Errorf("observed %+v, want CreateDocument with a duration and no error", calls[0])
+		}
+	})
+
+	t.Run("failed call", func(t *testing.T) {
+		_, err := client.GetDocument(ctx, "books", "missing")
+		calls := observer.take()
+		if len(calls) != 1 || calls[0].op != "GetDocument" {
+			t.Fatalf("observed %+v, want one GetDocument call", calls)
+		}
+		if !errors.Is(calls[0].err, ErrDocumentNotFound) || calls[0].err != err {
+			t.Errorf("observed error %v, want the returned error %v", calls[0].err, err)
+		}
+	})
+
+	t.Run("error outside of a request", func(t *testing.T) {
+		server.SetReadOnly("books", true)
+		defer server.SetReadOnly("books", false)
+
+		err := client.BulkCreate(ctx, "books", []map[string]interface{}{{"title": "Rust"}})
+		if err == nil {
+			t.Fatal("BulkCreate() error = nil, want the failed bulk item")
+		}
+		calls := observer.take()
+		if len(calls) != 1 || calls[0].op != "BulkCreate" || calls[0].err == nil {
+			t.Errorf("observed %+v, want one BulkCreate call with the failed bulk item", calls)
+		}
+	})
+
+	t.Run("method built on others", func(t *testing.T) {
+		if _, err := client.EnsureIndex(ctx, "authors", nil); err != nil {
+			t.Fatalf("EnsureIndex() error = %v", err)
+		}
+		if err := client.DumpIndex(ctx, "books", io.Discard, DumpOptions{}); err != nil {
+			t.Fatalf("DumpIndex() error = %v", err)
+		}
+
+		var ops []string
+		for _, call := range observer.take() {
+			ops = append(ops, call.op)
+		}
+		if want := []string{"EnsureIndex", "DumpIndex"}; !reflect.DeepEqual(ops, want) {
+			t.Errorf("observed %v, want %v", ops, want)
+		}
+	})
+}
@@ -6,19 +6,43 @@ import (
 	"io"
 )
 
+// ClusterInfoResult holds the identifying details of the cluster returned by the root endpoint
+type ClusterInfoResult struct {
+	ClusterName   string
+	NodeName      string
+	VersionNumber string
+	Distribution  string
+}
+
 // GetResponse represents the response from a GET document request
 type GetResponse struct {
-	Index   string                 `json:"_index"`
-	ID      string                 `json:"_id"`
-	Version int                    `json:"_version"`
-	Found   bool                   `json:"found"`
-	Source  map[string]interface{} `json:"_source"`
+	Index       string                 `json:"_index"`
+	ID          string                 `json:"_id"`
+	Version     int64                  `json:"_version"`
+	SeqNo       int                    `json:"_seq_no"`
+	PrimaryTerm int                    `json:"_primary_term"`
+	Found       bool                   `json:"found"`
+	Source      map[string]interface{} `json:"_source"`
+}
+
+// DocumentMeta holds the version metadata of a document used for optimistic concurrency control
+type DocumentMeta struct {
+	Version     int64
+	SeqNo       int
+	PrimaryTerm int
 }
 
 // SearchResponse represents the response from a search request
 type SearchResponse struct {
-	Took int `json:"took"`
-	Hits struct {
+	ScrollID string `json:"_scroll_id,omitempty"`
+	Took     int    `json:"took"`
+	// TimedOut is true when the search hit its timeout (see WithQueryTimeout) and the
+	// hits are partial
+	TimedOut bool `json:"timed_out"`
+	// TerminatedEarly is true when a shard stopped collecting at terminate_after
+	// (see WithTerminateAfter)
+	TerminatedEarly bool `json:"terminated_early,omitempty"`
+	Hits            struct {
 		Total struct {
 			Value    int    `json:"value"`
 			Relation string `json:"relation"`
@@ -26,6 +50,80 @@ type SearchResponse struct {
 		MaxScore float64 `json:"max_score"`
 		Hits     []Hit   `json:"hits"`
 	} `json:"hits"`
+	// Profile is set for searches made with WithProfile
+	Profile *SearchProfile `json:"profile,omitempty"`
+	// Aggregations holds the result of each aggregation added with WithAggregation
+	Aggregations map[string]interface{} `json:"aggregations,omitempty"`
+}
+
+// SearchResult is the result of Search: the hits along with the metadata of the response
+type SearchResult struct {
+	Hits []Hit
+	// Total is the number of matching documents. When TotalRelation is "gte" it is a lower
+	// bound, as OpenSearch stops counting at 10,000 matches unless track_total_hits is set.
+	Total         int
+	TotalRelation string
+	// MaxScore is the highest score of the hits, or 0 when the hits are sorted by a field
+	MaxScore float64
+	// TookMs is how long OpenSearch spent on the search, in milliseconds
+	TookMs int
+}
+
+// SearchSpec is one search of a MultiSearch
+type SearchSpec struct {
+	Index string
+	Query map[string]interface{}
+}
+
+// MultiSearchResponse represents the response from a multi search operation
+type MultiSearchResponse struct {
+	Took      int `json:"took"`
+	Responses []struct {
+		SearchResponse
+		// Error is set, and Hits empty, for a search that failed
+		Error *struct {
+			Type      string       `json:"type"`
+			Reason    string       `json:"reason"`
+			Index     string       `json:"index"`
+			RootCause []ErrorCause `json:"root_cause"`
+		} `json:"error,omitempty"`
+		Status int `json:"status"`
+	} `json:"responses"`
+}
+
+// SearchProfile is the profile section of a search response, with the timings of each shard
+type SearchProfile struct {
+	Shards []ShardProfile `json:"shards"`
+}
+
+// ShardProfile holds the query and aggregation timings of one shard
+type ShardProfile struct {
+	// ID identifies the shard as [node][index][shard]
+	ID       string `json:"id"`
+	Searches []struct {
+		Query       []QueryProfile     `json:"query"`
+		RewriteTime int64              `json:"rewrite_time"`
+		Collector   []CollectorProfile `json:"collector"`
+	} `json:"searches"`
+	Aggregations []QueryProfile `json:"aggregations"`
+}
+
+// QueryProfile is the timing of a Lucene query (or aggregation) and its children
+type QueryProfile struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	TimeInNanos int64  `json:"time_in_nanos"`
+	// Breakdown splits the time into its low-level steps, e.g. "create_weight" or "next_doc"
+	Breakdown map[string]int64 `json:"breakdown"`
+	Children  []QueryProfile   `json:"children,omitempty"`
+}
+
+// CollectorProfile is the timing of a collector and its children
+type CollectorProfile struct {
+	Name        string             `json:"name"`
+	Reason      string             `json:"reason"`
+	TimeInNanos int64              `json:"time_in_nanos"`
+	Children    []CollectorProfile `json:"children,omitempty"`
 }
 
 // Hit represents a single search result
@@ -34,6 +132,32 @@ type Hit struct {
 	ID     string                 `json:"_id"`
 	Score  float64                `json:"_score"`
 	Source map[string]interface{} `json:"_source"`
+	Sort   []interface{}          `json:"sort,omitempty"`
+	// MatchedQueries lists the names of the clauses (see NamedQuery) that matched this hit
+	MatchedQueries []string `json:"matched_queries,omitempty"`
+}
+
+// RawSearchResponse represents a search response whose hit sources are kept as raw JSON
+type RawSearchResponse struct {
+	Took int `json:"took"`
+	Hits struct {
+		Total struct {
+			Value    int    `json:"value"`
+			Relation string `json:"relation"`
+		} `json:"total"`
+		MaxScore float64  `json:"max_score"`
+		Hits     []RawHit `json:"hits"`
+	} `json:"hits"`
+}
+
+// RawHit represents a single search result with an undecoded _source
+type RawHit struct {
+	Index  string          `json:"_index"`
+	ID     string          `json:"_id"`
+	Score  float64         `json:"_score"`
+	Source json.RawMessage `json:"_source"`
+	// MatchedQueries lists the names of the clauses (see NamedQuery) that matched this hit
+	MatchedQueries []string `json:"matched_queries,omitempty"`
 }
 
 // BulkResponse represents the response from a bulk request
@@ -47,7 +171,7 @@ type BulkResponse struct {
 type BulkItem struct {
 	Index   string `json:"_index"`
 	ID      string `json:"_id"`
-	Version int    `json:"_version"`
+	Version int64  `json:"_version"`
 	Result  string `json:"result"`
 	Status  int    `json:"status"`
 	Error   struct {
@@ -60,7 +184,7 @@ type BulkItem struct {
 type IndexResponse struct {
 	Index   string `json:"_index"`
 	ID      string `json:"_id"`
-	Version int    `json:"_version"`
+	Version int64  `json:"_version"`
 	Result  string `json:"result"`
 }
 
@@ -68,7 +192,7 @@ type IndexResponse struct {
 type DeleteResponse struct {
 	Index   string `json:"_index"`
 	ID      string `json:"_id"`
-	Version int    `json:"_version"`
+	Version int64  `json:"_version"`
 	Result  string `json:"result"`
 }
 
@@ -76,22 +200,130 @@ type DeleteResponse struct {
 type UpdateResponse struct {
 	Index   string `json:"_index"`
 	ID      string `json:"_id"`
-	Version int    `json:"_version"`
+	Version int64  `json:"_version"`
 	Result  string `json:"result"`
 }
 
+// ValidateQueryResponse represents the response from a validate query request
+type ValidateQueryResponse struct {
+	Valid        bool   `json:"valid"`
+	Error        string `json:"error"`
+	Explanations []struct {
+		Index       string `json:"index"`
+		Valid       bool   `json:"valid"`
+		Explanation string `json:"explanation"`
+		Error       string `json:"error"`
+	} `json:"explanations"`
+}
+
+// ShardsInfo summarises how many shards an operation ran on and how many succeeded
+type ShardsInfo struct {
+	Total      int `json:"total"`
+	Successful int `json:"successful"`
+	Failed     int `json:"failed"`
+}
+
+// ShardsResponse represents a response that only carries a _shards summary
+type ShardsResponse struct {
+	Shards ShardsInfo `json:"_shards"`
+}
+
+// TaskResponse represents the response of a request submitted with wait_for_completion=false
+type TaskResponse struct {
+	Task string `json:"task"`
+}
+
+// IndexStatsResult holds the commonly used totals from the index stats API.
+// Raw keeps the complete "total" section for anything not parsed here.
+type IndexStatsResult struct {
+	DocsCount        int64
+	DocsDeleted      int64
+	StoreSizeBytes   int64
+	IndexingTotal    int64
+	SearchQueryTotal int64
+	Raw              json.RawMessage
+}
+
+// indexStatsSection is the part of the stats response parsed into IndexStatsResult
+type indexStatsSection struct {
+	Docs struct {
+		Count   int64 `json:"count"`
+		Deleted int64 `json:"deleted"`
+	} `json:"docs"`
+	Store struct {
+		SizeInBytes int64 `json:"size_in_bytes"`
+	} `json:"store"`
+	Indexing struct {
+		IndexTotal int64 `json:"index_total"`
+	} `json:"indexing"`
+	Search struct {
+		QueryTotal int64 `json:"query_total"`
+	} `json:"search"`
+}
+
+// IndexInfo describes an index as listed by the cat indices API
+type IndexInfo struct {
+	Index     string
+	Health    string
+	Status    string
+	DocsCount int64
+	StoreSize int64 // bytes
+}
+
+// IndexSummary is the summary of an index returned by CatIndices
+type IndexSummary = IndexInfo
+
 // ErrorResponse represents an error response from OpenSearch
 type ErrorResponse struct {
 	Error struct {
-		Type   string `json:"type"`
-		Reason string `json:"reason"`
+		Type      string       `json:"type"`
+		Reason    string       `json:"reason"`
+		Index     string       `json:"index"`
+		RootCause []ErrorCause `json:"root_cause"`
 	} `json:"error"`
 	Status int `json:"status"`
 }
 
-// parseResponse is a helper function to parse JSON responses
-func parseResponse(body io.Reader, v interface{}) error {
-	if err := json.NewDecoder(body).Decode(v); err != nil {
+// ErrorCause is one of the root causes of an error response
+type ErrorCause struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+	Index  string `json:"index,omitempty"`
+}
+
+// DocumentOption configures optional parameters of a single-document request
+type DocumentOption func(*documentOptions)
+
+// documentOptions holds the optional parameters collected from DocumentOption values
+type documentOptions struct {
+	routing string
+}
+
+// WithRouting sets the custom routing value used to locate the document's shard.
+// Documents written with a routing value must be read, updated and deleted with the same value.
+func WithRouting(routing string) DocumentOption {
+	return func(o *documentOptions) {
+		o.routing = routing
+	}
+}
+
+// applyDocumentOptions collects the given options into a documentOptions value
+func applyDocumentOptions(opts []DocumentOption) documentOptions {
+	var options documentOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// parseResponse is a helper function to parse JSON responses, decoding numbers as
+// json.Number when the client was configured with UseJSONNumber
+func (c *Client) parseResponse(body io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(body)
+	if c.useNumber {
+		decoder.UseNumber()
+	}
+	if err := decoder.Decode(v); err != nil {
 		return fmt.Errorf("failed to parse response: %w", err)
 	}
 	return nil
@@ -110,9 +342,64 @@ func MatchAllQuery() map[string]interface{} {
 
 // MatchQuery creates a match query for a specific field
 func MatchQuery(field, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"query": MatchClause(field, value),
+	}
+}
+
+// MatchClause creates a match clause for a specific field, for use in a QueryBuilder or
+// BoolBuilder. MatchQuery returns the same clause as a complete search body.
+func MatchClause(field, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"match": map[string]interface{}{
+			field: value,
+		},
+	}
+}
+
+// MatchOptions holds the optional parameters of a match query. Empty fields are omitted.
+type MatchOptions struct {
+	// Operator is "or" (the default) or "and" to require every term to match
+	Operator string
+	// Fuzziness allows per-term edit distance, e.g. "AUTO" or "1"
+	Fuzziness string
+	// MinimumShouldMatch is a count or percentage of terms that must match, e.g. "2" or "75%"
+	MinimumShouldMatch string
+}
+
+// MatchQueryOpts creates a match query for a specific field with operator, fuzziness
+// and minimum_should_match options
+func MatchQueryOpts(field, value string, opts MatchOptions) map[string]interface{} {
+	params := map[string]interface{}{
+		"query": value,
+	}
+	if opts.Operator != "" {
+		params["operator"] = opts.Operator
+	}
+	if opts.Fuzziness != "" {
+		params["fuzziness"] = opts.Fuzziness
+	}
+	if opts.MinimumShouldMatch != "" {
+		params["minimum_should_match"] = opts.MinimumShouldMatch
+	}
+
 	return map[string]interface{}{
 		"query": map[string]interface{}{
 			"match": map[string]interface{}{
+				field: params,
+			},
+		},
+	}
+}
+
+// MatchBoolPrefixQuery creates a match_bool_prefix query for search-as-you-type: the terms
+// of value are combined in a bool should, so any of them can match, with the last one
+// matched as a prefix. "quick br" matches "quick brown fox", and also "brown fox" or
+// "quick fox". Use it on a search_as_you_type or text field.
+func MatchBoolPrefixQuery(field, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"query": map[string]interface{}{
+			"match_bool_prefix": map[string]interface{}{
 				field: value,
 			},
 		},
@@ -180,10 +467,32 @@ func NotMatchMapQuery(fieldValues map[string]interface{}) map[string]interface{}
 
 // TermQuery creates a term query for exact matching
 func TermQuery(field string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"query": TermClause(field, value),
+	}
+}
+
+// TermClause creates a term clause for exact matching, for use in a QueryBuilder or
+// BoolBuilder. TermQuery returns the same clause as a complete search body.
+func TermClause(field string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"term": map[string]interface{}{
+			field: value,
+		},
+	}
+}
+
+// TermsLookupQuery creates a terms query whose values are read from the lookupPath
+// field of the lookupID document in lookupIndex
+func TermsLookupQuery(field, lookupIndex, lookupID, lookupPath string) map[string]interface{} {
 	return map[string]interface{}{
 		"query": map[string]interface{}{
-			"term": map[string]interface{}{
-				field: value,
+			"terms": map[string]interface{}{
+				field: map[string]interface{}{
+					"index": lookupIndex,
+					"id":    lookupID,
+					"path":  lookupPath,
+				},
 			},
 		},
 	}
@@ -208,6 +517,15 @@ func NotTermQuery(field string, value interface{}) map[string]interface{} {
 
 // RangeQuery creates a range query
 func RangeQuery(field string, gte, lte interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"query": RangeClause(field, gte, lte),
+	}
+}
+
+// RangeClause creates a range clause, for use in a QueryBuilder or BoolBuilder. A nil gte
+// or lte leaves that end of the range open. RangeQuery returns the same clause as a
+// complete search body.
+func RangeClause(field string, gte, lte interface{}) map[string]interface{} {
 	rangeCondition := make(map[string]interface{})
 	if gte != nil {
 		rangeCondition["gte"] = gte
@@ -216,10 +534,85 @@ func RangeQuery(field string, gte, lte interface{}) map[string]interface{} {
 		rangeCondition["lte"] = lte
 	}
 
+	return map[string]interface{}{
+		"range": map[string]interface{}{
+			field: rangeCondition,
+		},
+	}
+}
+
+// GeoBoundingBoxQuery creates a geo_bounding_box query matching documents whose geo_point
+// field lies within the box spanned by the top-left and bottom-right corners
+func GeoBoundingBoxQuery(field string, topLeftLat, topLeftLon, bottomRightLat, bottomRightLon float64) map[string]interface{} {
+	return map[string]interface{}{
+		"query": map[string]interface{}{
+			"geo_bounding_box": map[string]interface{}{
+				field: map[string]interface{}{
+					"top_left": map[string]interface{}{
+						"lat": topLeftLat,
+						"lon": topLeftLon,
+					},
+					"bottom_right": map[string]interface{}{
+						"lat": bottomRightLat,
+						"lon": bottomRightLon,
+					},
+				},
+			},
+		},
+	}
+}
+
+// GeoPolygonQuery creates a geo_polygon query matching documents whose geo_point field lies
+// within the polygon through points, each given as {lat, lon} (not the GeoJSON lon, lat order)
+func GeoPolygonQuery(field string, points [][2]float64) map[string]interface{} {
+	polygon := make([]interface{}, 0, len(points))
+	for _, point := range points {
+		polygon = append(polygon, map[string]interface{}{
+			"lat": point[0],
+			"lon": point[1],
+		})
+	}
+
+	return map[string]interface{}{
+		"query": map[string]interface{}{
+			"geo_polygon": map[string]interface{}{
+				field: map[string]interface{}{
+					"points": polygon,
+				},
+			},
+		},
+	}
+}
+
+// ScriptQuery creates a script query filtering documents with a Painless script that
+// returns true for matches, e.g. "doc['price'].value > params.min". Pass values through
+// params rather than formatting them into the source, so the compiled script is reused.
+func ScriptQuery(source string, params map[string]interface{}) map[string]interface{} {
+	script := map[string]interface{}{
+		"source": source,
+		"lang":   "painless",
+	}
+	if params != nil {
+		script["params"] = params
+	}
+
 	return map[string]interface{}{
 		"query": map[string]interface{}{
-			"range": map[string]interface{}{
-				field: rangeCondition,
+			"script": map[string]interface{}{
+				"script": script,
+			},
+		},
+	}
+}
+
+// PercolateQuery creates a percolate query matching the queries stored in the percolator
+// field that match document
+func PercolateQuery(field string, document map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"query": map[string]interface{}{
+			"percolate": map[string]interface{}{
+				"field":    field,
+				"document": document,
 			},
 		},
 	}
@@ -246,6 +639,77 @@ func BoolQuery(must, should, mustNot []map[string]interface{}) map[string]interf
 	}
 }
 
+// namedQueryShortForms maps field-level query types to the parameter that holds the
+// value in their short form, e.g. {"term": {"status": "active"}}
+var namedQueryShortForms = map[string]string{
+	"match":               "query",
+	"match_phrase":        "query",
+	"match_phrase_prefix": "query",
+	"match_bool_prefix":   "query",
+	"term":                "value",
+	"prefix":              "value",
+	"wildcard":            "value",
+	"regexp":              "value",
+	"fuzzy":               "value",
+}
+
+// NamedQuery names a clause so that hits it matches report the name in their matched_queries.
+// The clause may be a complete search body from one of the builders above (e.g. MatchQuery),
+// in which case the inner clause is named; the input is not modified.
+func NamedQuery(name string, clause map[string]interface{}) map[string]interface{} {
+	inner := unwrapQuery(clause)
+	if len(inner) != 1 {
+		return clause
+	}
+
+	named := make(map[string]interface{}, 1)
+	for queryType, body := range inner {
+		params, ok := body.(map[string]interface{})
+		if !ok {
+			return clause
+		}
+		named[queryType] = nameQueryParams(name, queryType, params)
+	}
+
+	if len(clause) == 1 && clause["query"] != nil {
+		return map[string]interface{}{"query": named}
+	}
+	return named
+}
+
+// nameQueryParams returns a copy of a query's parameters with _name set. Field-level queries
+// take the name next to the field's parameters rather than at the top level.
+func nameQueryParams(name, queryType string, params map[string]interface{}) map[string]interface{} {
+	named := make(map[string]interface{}, len(params)+1)
+	for key, value := range params {
+		named[key] = value
+	}
+
+	valueKey, fieldLevel := namedQueryShortForms[queryType]
+	if queryType == "range" {
+		fieldLevel = true
+	}
+	if !fieldLevel || len(params) != 1 {
+		named["_name"] = name
+		return named
+	}
+
+	for field, value := range params {
+		fieldParams, ok := value.(map[string]interface{})
+		if !ok {
+			named[field] = map[string]interface{}{valueKey: value, "_name": name}
+			continue
+		}
+		copied := make(map[string]interface{}, len(fieldParams)+1)
+		for key, v := range fieldParams {
+			copied[key] = v
+		}
+		copied["_name"] = name
+		named[field] = copied
+	}
+	return named
+}
+
 // WithSize adds a size parameter to a query
 func WithSize(query map[string]interface{}, size int) map[string]interface{} {
 	query["size"] = size
@@ -269,3 +733,44 @@ func WithSort(query map[string]interface{}, field, order string) map[string]inte
 	}
 	return query
 }
+
+// WithCollapse collapses search results so only the top hit per distinct value of field is returned
+func WithCollapse(query map[string]interface{}, field string) map[string]interface{} {
+	query["collapse"] = map[string]interface{}{
+		"field": field,
+	}
+	return query
+}
+
+// WithQueryTimeout sets a server-side time budget for the search, e.g. "500ms". A search
+// that runs out of time returns the hits collected so far with timed_out set, rather than
+// failing.
+func WithQueryTimeout(query map[string]interface{}, timeout string) map[string]interface{} {
+	query["timeout"] = timeout
+	return query
+}
+
+// WithTerminateAfter stops each shard after it has collected n matching documents, for
+// cheap existence checks. Hit counts then only cover the documents collected.
+func WithTerminateAfter(query map[string]interface{}, n int) map[string]interface{} {
+	query["terminate_after"] = n
+	return query
+}
+
+// WithProfile asks OpenSearch to time each part of the search (see SearchWithProfile)
+func WithProfile(query map[string]interface{}) map[string]interface{} {
+	query["profile"] = true
+	return query
+}
+
+// WithAggregation adds an aggregation called name to a query, e.g. a
+// DateHistogramAggregation (see SearchWithAggregations)
+func WithAggregation(query map[string]interface{}, name string, agg map[string]interface{}) map[string]interface{} {
+	aggs, ok := query["aggs"].(map[string]interface{})
+	if !ok {
+		aggs = make(map[string]interface{})
+		query["aggs"] = aggs
+	}
+	aggs[name] = agg
+	return query
+}
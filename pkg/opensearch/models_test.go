@@ -2,20 +2,26 @@ package opensearch
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
 )
 
 // TestParseResponse tests the parseResponse helper function
 func TestParseResponse(t *testing.T) {
 	tests := []struct {
-		name    string
-		input   string
-		target  interface{}
-		want    interface{}
-		wantErr bool
+		name      string
+		input     string
+		useNumber bool
+		target    interface{}
+		want      interface{}
+		wantErr   bool
 	}{
 		{
 			name:  "valid JSON - GetResponse",
@@ -42,6 +48,20 @@ func TestParseResponse(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:      "UseJSONNumber keeps integers exact",
+			input:     `{"_index":"test","_id":"1","_version":1,"found":true,"_source":{"id":9007199254740993,"price":9.99}}`,
+			useNumber: true,
+			target:    &GetResponse{},
+			want: &GetResponse{
+				Index:   "test",
+				ID:      "1",
+				Version: 1,
+				Found:   true,
+				Source:  map[string]interface{}{"id": json.Number("9007199254740993"), "price": json.Number("9.99")},
+			},
+			wantErr: false,
+		},
 		{
 			name:    "invalid JSON",
 			input:   `{"invalid json`,
@@ -61,7 +81,8 @@ func TestParseResponse(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			reader := strings.NewReader(tt.input)
-			err := parseResponse(reader, tt.target)
+			client := &Client{useNumber: tt.useNumber}
+			err := client.parseResponse(reader, tt.target)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseResponse() error = %v, wantErr %v", err, tt.wantErr)
@@ -101,6 +122,34 @@ func TestMatchAllQuery(t *testing.T) {
 	}
 }
 
+// TestMatchBoolPrefixQuery tests the match_bool_prefix structure
+func TestMatchBoolPrefixQuery(t *testing.T) {
+	result := MatchBoolPrefixQuery("title", "quick br")
+
+	want := map[string]interface{}{
+		"query": map[string]interface{}{
+			"match_bool_prefix": map[string]interface{}{
+				"title": "quick br",
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("MatchBoolPrefixQuery() = %v, want %v", result, want)
+	}
+
+	named := NamedQuery("typeahead", result)
+	wantNamed := map[string]interface{}{
+		"query": map[string]interface{}{
+			"match_bool_prefix": map[string]interface{}{
+				"title": map[string]interface{}{"query": "quick br", "_name": "typeahead"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(named, wantNamed) {
+		t.Errorf("NamedQuery(MatchBoolPrefixQuery()) = %v, want %v", named, wantNamed)
+	}
+}
+
 // TestMatchQuery tests the MatchQuery builder
 func TestMatchQuery(t *testing.T) {
 	tests := []struct {
@@ -145,6 +194,56 @@ func TestMatchQuery(t *testing.T) {
 	}
 }
 
+func TestMatchQueryOpts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts MatchOptions
+		want map[string]interface{}
+	}{
+		{
+			name: "AND operator",
+			opts: MatchOptions{Operator: "and"},
+			want: map[string]interface{}{"query": "quick brown fox", "operator": "and"},
+		},
+		{
+			name: "fuzziness",
+			opts: MatchOptions{Fuzziness: "AUTO"},
+			want: map[string]interface{}{"query": "quick brown fox", "fuzziness": "AUTO"},
+		},
+		{
+			name: "all options",
+			opts: MatchOptions{Operator: "or", Fuzziness: "1", MinimumShouldMatch: "75%"},
+			want: map[string]interface{}{
+				"query":                "quick brown fox",
+				"operator":             "or",
+				"fuzziness":            "1",
+				"minimum_should_match": "75%",
+			},
+		},
+		{
+			name: "no options",
+			opts: MatchOptions{},
+			want: map[string]interface{}{"query": "quick brown fox"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MatchQueryOpts("title", "quick brown fox", tt.opts)
+			want := map[string]interface{}{
+				"query": map[string]interface{}{
+					"match": map[string]interface{}{
+						"title": tt.want,
+					},
+				},
+			}
+			if !reflect.DeepEqual(result, want) {
+				t.Errorf("MatchQueryOpts() = %v, want %v", result, want)
+			}
+		})
+	}
+}
+
 // TestNotMatchQuery tests the NotMatchQuery builder
 func TestNotMatchQuery(t *testing.T) {
 	result := NotMatchQuery("status", "inactive")
@@ -320,6 +419,100 @@ func TestTermQuery(t *testing.T) {
 	}
 }
 
+func TestTermsLookupQuery(t *testing.T) {
+	result := TermsLookupQuery("user_id", "allow-lists", "list-1", "allowed_ids")
+
+	want := map[string]interface{}{
+		"query": map[string]interface{}{
+			"terms": map[string]interface{}{
+				"user_id": map[string]interface{}{
+					"index": "allow-lists",
+					"id":    "list-1",
+					"path":  "allowed_ids",
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("TermsLookupQuery() = %v, want %v", result, want)
+	}
+}
+
+func TestNamedQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		clause map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{
+			name:   "Match short form",
+			clause: MatchQuery("title", "golang"),
+			want: map[string]interface{}{
+				"query": map[string]interface{}{
+					"match": map[string]interface{}{
+						"title": map[string]interface{}{"query": "golang", "_name": "q"},
+					},
+				},
+			},
+		},
+		{
+			name:   "Term short form clause",
+			clause: map[string]interface{}{"term": map[string]interface{}{"status": "active"}},
+			want: map[string]interface{}{
+				"term": map[string]interface{}{
+					"status": map[string]interface{}{"value": "active", "_name": "q"},
+				},
+			},
+		},
+		{
+			name:   "Match with options",
+			clause: MatchQueryOpts("title", "golang", MatchOptions{Operator: "and"}),
+			want: map[string]interface{}{
+				"query": map[string]interface{}{
+					"match": map[string]interface{}{
+						"title": map[string]interface{}{"query": "golang", "operator": "and", "_name": "q"},
+					},
+				},
+			},
+		},
+		{
+			name:   "Range",
+			clause: RangeQuery("views", 10, nil),
+			want: map[string]interface{}{
+				"query": map[string]interface{}{
+					"range": map[string]interface{}{
+						"views": map[string]interface{}{"gte": 10, "_name": "q"},
+					},
+				},
+			},
+		},
+		{
+			name:   "Bool",
+			clause: NewBoolBuilder().Must(MatchAllQuery()).Build(),
+			want: map[string]interface{}{
+				"bool": map[string]interface{}{
+					"must":  []map[string]interface{}{{"match_all": map[string]interface{}{}}},
+					"_name": "q",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := prettyPrint(tt.clause)
+			result := NamedQuery("q", tt.clause)
+
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("NamedQuery() = %s, want %s", prettyPrint(result), prettyPrint(tt.want))
+			}
+			if prettyPrint(tt.clause) != original {
+				t.Errorf("NamedQuery() modified its input: %s", prettyPrint(tt.clause))
+			}
+		})
+	}
+}
+
 // TestNotTermQuery tests the NotTermQuery builder
 func TestNotTermQuery(t *testing.T) {
 	result := NotTermQuery("status.keyword", "deleted")
@@ -430,6 +623,104 @@ func TestRangeQuery(t *testing.T) {
 	}
 }
 
+// TestGeoBoundingBoxQuery tests that the corners land in top_left and bottom_right
+func TestGeoBoundingBoxQuery(t *testing.T) {
+	result := GeoBoundingBoxQuery("location", 40.73, -74.1, 40.01, -71.12)
+
+	want := map[string]interface{}{
+		"query": map[string]interface{}{
+			"geo_bounding_box": map[string]interface{}{
+				"location": map[string]interface{}{
+					"top_left":     map[string]interface{}{"lat": 40.73, "lon": -74.1},
+					"bottom_right": map[string]interface{}{"lat": 40.01, "lon": -71.12},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("GeoBoundingBoxQuery() = %v, want %v", result, want)
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	wantJSON := `{"query":{"geo_bounding_box":{"location":{"bottom_right":{"lat":40.01,"lon":-71.12},"top_left":{"lat":40.73,"lon":-74.1}}}}}`
+	if string(body) != wantJSON {
+		t.Errorf("GeoBoundingBoxQuery() JSON = %s, want %s", body, wantJSON)
+	}
+}
+
+// TestGeoPolygonQuery tests that each point becomes a lat/lon pair, in order
+func TestGeoPolygonQuery(t *testing.T) {
+	t.Run("triangle", func(t *testing.T) {
+		result := GeoPolygonQuery("location", [][2]float64{{40, -70}, {30, -80}, {20, -90}})
+
+		body, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		want := `{"query":{"geo_polygon":{"location":{"points":[{"lat":40,"lon":-70},{"lat":30,"lon":-80},{"lat":20,"lon":-90}]}}}}`
+		if string(body) != want {
+			t.Errorf("GeoPolygonQuery() = %s, want %s", body, want)
+		}
+	})
+
+	t.Run("no points", func(t *testing.T) {
+		body, err := json.Marshal(GeoPolygonQuery("location", nil))
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		want := `{"query":{"geo_polygon":{"location":{"points":[]}}}}`
+		if string(body) != want {
+			t.Errorf("GeoPolygonQuery() = %s, want %s", body, want)
+		}
+	})
+}
+
+// TestScriptQuery tests the script structure with and without params
+func TestScriptQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{
+			name:   "with params",
+			params: map[string]interface{}{"min": 10},
+			want: map[string]interface{}{
+				"source": "doc['price'].value > params.min",
+				"lang":   "painless",
+				"params": map[string]interface{}{"min": 10},
+			},
+		},
+		{
+			name: "nil params omitted",
+			want: map[string]interface{}{
+				"source": "doc['price'].value > params.min",
+				"lang":   "painless",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ScriptQuery("doc['price'].value > params.min", tt.params)
+
+			want := map[string]interface{}{
+				"query": map[string]interface{}{
+					"script": map[string]interface{}{
+						"script": tt.want,
+					},
+				},
+			}
+			if !reflect.DeepEqual(result, want) {
+				t.Errorf("ScriptQuery() = %v, want %v", result, want)
+			}
+		})
+	}
+}
+
 // TestBoolQuery tests the BoolQuery builder
 func TestBoolQuery(t *testing.T) {
 	tests := []struct {
@@ -600,6 +891,77 @@ func TestWithSort(t *testing.T) {
 	}
 }
 
+// TestWithCollapse tests the WithCollapse modifier
+func TestWithCollapse(t *testing.T) {
+	query := MatchAllQuery()
+	result := WithCollapse(query, "author.keyword")
+
+	want := map[string]interface{}{"field": "author.keyword"}
+	if !reflect.DeepEqual(result["collapse"], want) {
+		t.Errorf("collapse = %v, want %v", result["collapse"], want)
+	}
+
+	// Verify query is still intact
+	if _, exists := result["query"]; !exists {
+		t.Error("query should still exist after adding collapse")
+	}
+}
+
+// TestWithQueryTimeout tests the WithQueryTimeout modifier
+func TestWithQueryTimeout(t *testing.T) {
+	query := WithSize(MatchQuery("title", "golang"), 20)
+	result := WithQueryTimeout(query, "500ms")
+
+	if result["timeout"] != "500ms" {
+		t.Errorf("timeout = %v, want 500ms", result["timeout"])
+	}
+
+	// The timeout sits at the top level, beside the query it bounds
+	body, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Failed to marshal query: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("Query body is not valid JSON: %v", err)
+	}
+	want := map[string]interface{}{
+		"query":   map[string]interface{}{"match": map[string]interface{}{"title": "golang"}},
+		"size":    float64(20),
+		"timeout": "500ms",
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("Query body = %s, want %v", body, want)
+	}
+}
+
+// TestWithTerminateAfter tests the WithTerminateAfter modifier
+func TestWithTerminateAfter(t *testing.T) {
+	query := MatchAllQuery()
+	result := WithTerminateAfter(query, 5)
+
+	if result["terminate_after"] != 5 {
+		t.Errorf("terminate_after = %v, want 5", result["terminate_after"])
+	}
+
+	// Verify query is still intact
+	if _, exists := result["query"]; !exists {
+		t.Error("query should still exist after adding terminate_after")
+	}
+}
+
+// TestWithProfile tests the WithProfile modifier
+func TestWithProfile(t *testing.T) {
+	result := WithProfile(MatchQuery("title", "golang"))
+
+	if result["profile"] != true {
+		t.Errorf("profile = %v, want true", result["profile"])
+	}
+	if _, exists := result["query"]; !exists {
+		t.Error("query should still exist after adding profile")
+	}
+}
+
 // TestQueryChaining tests chaining multiple modifiers
 func TestQueryChaining(t *testing.T) {
 	query := MatchQuery("title", "golang")
@@ -694,7 +1056,7 @@ func TestParseResponseWithDifferentTypes(t *testing.T) {
 		}`
 
 		var response SearchResponse
-		err := parseResponse(bytes.NewReader([]byte(input)), &response)
+		err := (&Client{}).parseResponse(bytes.NewReader([]byte(input)), &response)
 		if err != nil {
 			t.Fatalf("parseResponse failed: %v", err)
 		}
@@ -722,7 +1084,7 @@ func TestParseResponseWithDifferentTypes(t *testing.T) {
 		}`
 
 		var response ErrorResponse
-		err := parseResponse(bytes.NewReader([]byte(input)), &response)
+		err := (&Client{}).parseResponse(bytes.NewReader([]byte(input)), &response)
 		if err != nil {
 			t.Fatalf("parseResponse failed: %v", err)
 		}
@@ -735,4 +1097,232 @@ func TestParseResponseWithDifferentTypes(t *testing.T) {
 			t.Errorf("error type = %s, want 'index_not_found_exception'", response.Error.Type)
 		}
 	})
-}
\ No newline at end of file
+}
+
+// rawSearchPayload is a search response body shared by the RawHit tests and benchmarks
+const rawSearchPayload = `{
+	"took": 3,
+	"hits": {
+		"total": {"value": 2, "relation": "eq"},
+		"max_score": 1.0,
+		"hits": [
+			{"_index": "test", "_id": "1", "_score": 1.0, "_source": {"title": "First", "views": 150, "tags": ["a", "b"]}},
+			{"_index": "test", "_id": "2", "_score": 0.5, "_source": {"title": "Second", "views": 200, "tags": ["c"]}}
+		]
+	}
+}`
+
+// TestRawHitDecode tests decoding a RawHit's source into a caller-defined struct
+func TestRawHitDecode(t *testing.T) {
+	var response RawSearchResponse
+	if err := (&Client{}).parseResponse(strings.NewReader(rawSearchPayload), &response); err != nil {
+		t.Fatalf("parseResponse failed: %v", err)
+	}
+
+	if len(response.Hits.Hits) != 2 {
+		t.Fatalf("hits length = %d, want 2", len(response.Hits.Hits))
+	}
+
+	hit := response.Hits.Hits[1]
+	if hit.ID != "2" || hit.Score != 0.5 {
+		t.Errorf("hit = {ID: %s, Score: %v}, want {ID: 2, Score: 0.5}", hit.ID, hit.Score)
+	}
+
+	var article struct {
+		Title string   `json:"title"`
+		Views int      `json:"views"`
+		Tags  []string `json:"tags"`
+	}
+	if err := json.Unmarshal(hit.Source, &article); err != nil {
+		t.Fatalf("Failed to decode raw source: %v", err)
+	}
+
+	if article.Title != "Second" || article.Views != 200 || len(article.Tags) != 1 {
+		t.Errorf("decoded source = %+v, want {Title: Second, Views: 200, Tags: [c]}", article)
+	}
+}
+
+// BenchmarkSearchResponseDecode compares map-based hit decoding against raw hits
+func BenchmarkSearchResponseDecode(b *testing.B) {
+	type article struct {
+		Title string   `json:"title"`
+		Views int      `json:"views"`
+		Tags  []string `json:"tags"`
+	}
+
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var response SearchResponse
+			if err := (&Client{}).parseResponse(strings.NewReader(rawSearchPayload), &response); err != nil {
+				b.Fatal(err)
+			}
+			for _, hit := range response.Hits.Hits {
+				// Mirror the map -> struct round trip callers do with SearchDocuments
+				data, err := json.Marshal(hit.Source)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var a article
+				if err := json.Unmarshal(data, &a); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("raw", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var response RawSearchResponse
+			if err := (&Client{}).parseResponse(strings.NewReader(rawSearchPayload), &response); err != nil {
+				b.Fatal(err)
+			}
+			for _, hit := range response.Hits.Hits {
+				var a article
+				if err := json.Unmarshal(hit.Source, &a); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+// TestApplyDocumentOptions tests collecting DocumentOption values
+func TestApplyDocumentOptions(t *testing.T) {
+	if got := applyDocumentOptions(nil); got.routing != "" {
+		t.Errorf("routing with no options = %q, want empty", got.routing)
+	}
+
+	got := applyDocumentOptions([]DocumentOption{WithRouting("a"), WithRouting("b")})
+	if got.routing != "b" {
+		t.Errorf("routing = %q, want last value %q", got.routing, "b")
+	}
+}
+
+// TestRequestError tests that server reasons are surfaced in request errors
+func TestRequestError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "JSON error body",
+			body: `{"error":{"type":"invalid_index_template_exception","reason":"component template [x] does not exist"},"status":400}`,
+			want: "put index template request failed with status: 400 Bad Request: component template [x] does not exist",
+		},
+		{
+			name: "non-JSON body",
+			body: "upstream rejected the request\n",
+			want: "put index template request failed with status: 400 Bad Request: upstream rejected the request",
+		},
+		{
+			name: "empty body",
+			body: ``,
+			want: "put index template request failed with status: 400 Bad Request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &opensearchapi.Response{
+				StatusCode: 400,
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+			err := requestError("put index template", res)
+			if err.Error() != tt.want {
+				t.Errorf("requestError() = %q, want %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+// TestOpenSearchError tests that error responses are returned as an *OpenSearchError
+func TestOpenSearchError(t *testing.T) {
+	ctx := context.Background()
+	doc := map[string]interface{}{"title": "Golang"}
+
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		call      func(client *Client) error
+		want      OpenSearchError
+		wantCause error
+	}{
+		{
+			name:   "Mapping parse error",
+			status: 400,
+			body: `{"error":{"root_cause":[{"type":"mapper_parsing_exception","reason":"failed to parse field [views] of type [long]"}],
+				"type":"mapper_parsing_exception","reason":"failed to parse field [views] of type [long] in document with id '1'",
+				"caused_by":{"type":"illegal_argument_exception","reason":"For input string: \"many\""}},"status":400}`,
+			call: func(client *Client) error { return client.CreateDocument(ctx, "books", "1", doc) },
+			want: OpenSearchError{
+				StatusCode: 400,
+				Type:       "mapper_parsing_exception",
+				Reason:     "failed to parse field [views] of type [long] in document with id '1'",
+				RootCause:  []ErrorCause{{Type: "mapper_parsing_exception", Reason: "failed to parse field [views] of type [long]"}},
+			},
+		},
+		{
+			name:   "Index not found",
+			status: 404,
+			body: `{"error":{"root_cause":[{"type":"index_not_found_exception","reason":"no such index [books]","index":"books"}],
+				"type":"index_not_found_exception","reason":"no such index [books]","index_uuid":"_na_","index":"books"},"status":404}`,
+			call: func(client *Client) error { return client.DeleteIndex(ctx, "books") },
+			want: OpenSearchError{
+				StatusCode: 404,
+				Type:       "index_not_found_exception",
+				Reason:     "no such index [books]",
+				RootCause:  []ErrorCause{{Type: "index_not_found_exception", Reason: "no such index [books]", Index: "books"}},
+				Index:      "books",
+			},
+		},
+		{
+			name:   "Version conflict",
+			status: 409,
+			body: `{"error":{"root_cause":[{"type":"version_conflict_engine_exception","reason":"[1]: version conflict, document already exists (current version [1])","index":"books"}],
+				"type":"version_conflict_engine_exception","reason":"[1]: version conflict, document already exists (current version [1])","index":"books"},"status":409}`,
+			call: func(client *Client) error { return client.CreateDocumentStrict(ctx, "books", "1", doc) },
+			want: OpenSearchError{
+				StatusCode: 409,
+				Type:       "version_conflict_engine_exception",
+				Reason:     "[1]: version conflict, document already exists (current version [1])",
+				RootCause:  []ErrorCause{{Type: "version_conflict_engine_exception", Reason: "[1]: version conflict, document already exists (current version [1])", Index: "books"}},
+				Index:      "books",
+			},
+			wantCause: ErrVersionConflict,
+		},
+		{
+			name:   "Non-JSON body",
+			status: 502,
+			body:   `<html><body>Bad Gateway</body></html>`,
+			call:   func(client *Client) error { return client.RefreshIndex(ctx, "books") },
+			want: OpenSearchError{
+				StatusCode: 502,
+				Reason:     "<html><body>Bad Gateway</body></html>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStubClient(t, &stubTransport{status: tt.status, body: tt.body})
+
+			err := tt.call(client)
+			var osErr *OpenSearchError
+			if !errors.As(err, &osErr) {
+				t.Fatalf("error = %v (%T), want an *OpenSearchError", err, err)
+			}
+			got := *osErr
+			got.message, got.cause = "", nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OpenSearchError = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantCause != nil && !errors.Is(err, tt.wantCause) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", err, tt.wantCause)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// ClusterStatsResult holds the commonly used cluster-wide totals from the cluster stats API.
+// Raw keeps the complete response for anything not parsed here.
+type ClusterStatsResult struct {
+	ClusterName      string
+	Status           string
+	NodeCount        int
+	Versions         []string
+	IndexCount       int
+	DocsCount        int64
+	StoreSizeBytes   int64
+	HeapUsedBytes    int64
+	HeapMaxBytes     int64
+	FSTotalBytes     int64
+	FSAvailableBytes int64
+	Raw              map[string]interface{}
+}
+
+// NodeStats holds the commonly used per-node figures from the nodes stats API.
+// Fields belonging to metrics that were not requested are left zero.
+// Raw keeps the complete entry for the node.
+type NodeStats struct {
+	Name              string
+	Host              string
+	HeapUsedBytes     int64
+	HeapMaxBytes      int64
+	HeapUsedPercent   int
+	FSTotalBytes      int64
+	FSAvailableBytes  int64
+	OSCPUPercent      int
+	ProcessCPUPercent int
+	Raw               map[string]interface{}
+}
+
+// NodeInfo describes a node's identity, version and roles
+type NodeInfo struct {
+	Name    string   `json:"name"`
+	Host    string   `json:"host"`
+	IP      string   `json:"ip"`
+	Version string   `json:"version"`
+	Roles   []string `json:"roles"`
+}
+
+// ClusterStats returns cluster-wide index, JVM heap and disk totals
+func (c *Client) ClusterStats(ctx context.Context) (_ *ClusterStatsResult, err error) {
+	defer c.observe("ClusterStats", time.Now(), &err)
+
+	req := opensearchapi.ClusterStatsRequest{}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster stats: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("cluster stats", res)
+	}
+
+	var raw json.RawMessage
+	if err := c.parseResponse(res.Body, &raw); err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		ClusterName string `json:"cluster_name"`
+		Status      string `json:"status"`
+		Indices     struct {
+			Count int `json:"count"`
+			Docs  struct {
+				Count int64 `json:"count"`
+			} `json:"docs"`
+			Store struct {
+				SizeInBytes int64 `json:"size_in_bytes"`
+			} `json:"store"`
+		} `json:"indices"`
+		Nodes struct {
+			Count struct {
+				Total int `json:"total"`
+			} `json:"count"`
+			Versions []string `json:"versions"`
+			JVM      struct {
+				Mem struct {
+					HeapUsedInBytes int64 `json:"heap_used_in_bytes"`
+					HeapMaxInBytes  int64 `json:"heap_max_in_bytes"`
+				} `json:"mem"`
+			} `json:"jvm"`
+			FS struct {
+				TotalInBytes     int64 `json:"total_in_bytes"`
+				AvailableInBytes int64 `json:"available_in_bytes"`
+			} `json:"fs"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster stats: %w", err)
+	}
+
+	result := &ClusterStatsResult{
+		ClusterName:      response.ClusterName,
+		Status:           response.Status,
+		NodeCount:        response.Nodes.Count.Total,
+		Versions:         response.Nodes.Versions,
+		IndexCount:       response.Indices.Count,
+		DocsCount:        response.Indices.Docs.Count,
+		StoreSizeBytes:   response.Indices.Store.SizeInBytes,
+		HeapUsedBytes:    response.Nodes.JVM.Mem.HeapUsedInBytes,
+		HeapMaxBytes:     response.Nodes.JVM.Mem.HeapMaxInBytes,
+		FSTotalBytes:     response.Nodes.FS.TotalInBytes,
+		FSAvailableBytes: response.Nodes.FS.AvailableInBytes,
+	}
+	if err := c.parseResponse(bytes.NewReader(raw), &result.Raw); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster stats: %w", err)
+	}
+
+	return result, nil
+}
+
+// NodesStats returns per-node statistics keyed by node ID. metrics limits the
+// stats gathered (e.g. "jvm", "fs", "os", "process"); all metrics when empty.
+func (c *Client) NodesStats(ctx context.Context, metrics []string) (_ map[string]NodeStats, err error) {
+	defer c.observe("NodesStats", time.Now(), &err)
+
+	req := opensearchapi.NodesStatsRequest{
+		Metric: metrics,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes stats: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("nodes stats", res)
+	}
+
+	var response struct {
+		Nodes map[string]json.RawMessage `json:"nodes"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]NodeStats, len(response.Nodes))
+	for id, raw := range response.Nodes {
+		var node struct {
+			Name string `json:"name"`
+			Host string `json:"host"`
+			JVM  struct {
+				Mem struct {
+					HeapUsedInBytes int64 `json:"heap_used_in_bytes"`
+					HeapMaxInBytes  int64 `json:"heap_max_in_bytes"`
+					HeapUsedPercent int   `json:"heap_used_percent"`
+				} `json:"mem"`
+			} `json:"jvm"`
+			FS struct {
+				Total struct {
+					TotalInBytes     int64 `json:"total_in_bytes"`
+					AvailableInBytes int64 `json:"available_in_bytes"`
+				} `json:"total"`
+			} `json:"fs"`
+			OS struct {
+				CPU struct {
+					Percent int `json:"percent"`
+				} `json:"cpu"`
+			} `json:"os"`
+			Process struct {
+				CPU struct {
+					Percent int `json:"percent"`
+				} `json:"cpu"`
+			} `json:"process"`
+		}
+		if err := json.Unmarshal(raw, &node); err != nil {
+			return nil, fmt.Errorf("failed to parse stats for node %s: %w", id, err)
+		}
+
+		s := NodeStats{
+			Name:              node.Name,
+			Host:              node.Host,
+			HeapUsedBytes:     node.JVM.Mem.HeapUsedInBytes,
+			HeapMaxBytes:      node.JVM.Mem.HeapMaxInBytes,
+			HeapUsedPercent:   node.JVM.Mem.HeapUsedPercent,
+			FSTotalBytes:      node.FS.Total.TotalInBytes,
+			FSAvailableBytes:  node.FS.Total.AvailableInBytes,
+			OSCPUPercent:      node.OS.CPU.Percent,
+			ProcessCPUPercent: node.Process.CPU.Percent,
+		}
+		if err := c.parseResponse(bytes.NewReader(raw), &s.Raw); err != nil {
+			return nil, fmt.Errorf("failed to parse stats for node %s: %w", id, err)
+		}
+		stats[id] = s
+	}
+
+	return stats, nil
+}
+
+// NodesInfo returns the name, address, version and roles of every node keyed by node ID
+func (c *Client) NodesInfo(ctx context.Context) (_ map[string]NodeInfo, err error) {
+	defer c.observe("NodesInfo", time.Now(), &err)
+
+	req := opensearchapi.NodesInfoRequest{
+		FilterPath: []string{"nodes.*.name", "nodes.*.host", "nodes.*.ip", "nodes.*.version", "nodes.*.roles"},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes info: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("nodes info", res)
+	}
+
+	var response struct {
+		Nodes map[string]NodeInfo `json:"nodes"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Nodes, nil
+}
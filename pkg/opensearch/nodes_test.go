@@ -0,0 +1,67 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClusterStats(t *testing.T) {
+	client := setupTestClient(t)
+
+	stats, err := client.ClusterStats(context.Background())
+	if err != nil {
+		t.Fatalf("ClusterStats() error = %v", err)
+	}
+
+	if stats.NodeCount < 1 {
+		t.Errorf("NodeCount = %d, want at least 1", stats.NodeCount)
+	}
+	if stats.HeapMaxBytes <= 0 {
+		t.Errorf("HeapMaxBytes = %d, want > 0", stats.HeapMaxBytes)
+	}
+	if len(stats.Versions) == 0 {
+		t.Error("Versions is empty")
+	}
+	if _, ok := stats.Raw["nodes"]; !ok {
+		t.Error("Raw stats missing 'nodes'")
+	}
+}
+
+func TestNodesStats(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	info, err := client.NodesInfo(ctx)
+	if err != nil {
+		t.Fatalf("NodesInfo() error = %v", err)
+	}
+
+	stats, err := client.NodesStats(ctx, []string{"jvm", "fs", "os", "process"})
+	if err != nil {
+		t.Fatalf("NodesStats() error = %v", err)
+	}
+
+	if len(stats) != len(info) {
+		t.Errorf("NodesStats() returned %d nodes, NodesInfo() returned %d", len(stats), len(info))
+	}
+	for id, node := range stats {
+		if node.HeapMaxBytes <= 0 {
+			t.Errorf("Node %s HeapMaxBytes = %d, want > 0", id, node.HeapMaxBytes)
+		}
+		if node.FSTotalBytes <= 0 {
+			t.Errorf("Node %s FSTotalBytes = %d, want > 0", id, node.FSTotalBytes)
+		}
+		if _, ok := info[id]; !ok {
+			t.Errorf("Node %s missing from NodesInfo()", id)
+		}
+	}
+
+	for id, node := range info {
+		if node.Version == "" {
+			t.Errorf("Node %s has no version", id)
+		}
+		if len(node.Roles) == 0 {
+			t.Errorf("Node %s has no roles", id)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package opensearchtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yenonn/go-opensearch/pkg/opensearch"
+	"github.com/yenonn/go-opensearch/pkg/opensearch/opensearchtest"
+)
+
+// UserStore is an example service that depends on the smallest interface it needs
+type UserStore struct {
+	docs opensearch.DocumentAPI
+}
+
+// Name returns the name of the user, or "unknown" when there is no such user
+func (s *UserStore) Name(ctx context.Context, id string) (string, error) {
+	doc, err := s.docs.GetDocument(ctx, "users", id)
+	if errors.Is(err, opensearch.ErrDocumentNotFound) {
+		return "unknown", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	name, _ := doc["name"].(string)
+	return name, nil
+}
+
+func ExampleMockClient() {
+	mock := &opensearchtest.MockClient{
+		GetDocumentFunc: func(ctx context.Context, index, id string, opts ...opensearch.DocumentOption) (map[string]interface{}, error) {
+			if id != "1" {
+				return nil, opensearch.ErrDocumentNotFound
+			}
+			return map[string]interface{}{"name": "Ada"}, nil
+		},
+	}
+	store := &UserStore{docs: mock}
+
+	ctx := context.Background()
+	for _, id := range []string{"1", "2"} {
+		name, err := store.Name(ctx, id)
+		if err != nil {
+			fmt.Println("error:", err)
+			continue
+		}
+		fmt.Println(id, name)
+	}
+	fmt.Println(len(mock.CallsTo("GetDocument")), "lookups")
+
+	// Output:
+	// 1 Ada
+	// 2 unknown
+	// 2 lookups
+}
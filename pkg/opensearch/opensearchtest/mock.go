@@ -0,0 +1,250 @@
+// Package opensearchtest provides a mock of the opensearch client for unit tests of code
+// that depends on the interfaces in package opensearch.
+package opensearchtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yenonn/go-opensearch/pkg/opensearch"
+)
+
+// Call is a recorded call to a MockClient method
+type Call struct {
+	Method string
+	// Args are the arguments of the call after the context, in order
+	Args []interface{}
+}
+
+// MockClient implements opensearch.ClientAPI. Each method calls the function in the
+// matching field, or returns zero values when the field is nil, and records the call.
+// It is safe for concurrent use as long as the fields are not changed while it is in use.
+type MockClient struct {
+	PingFunc func(ctx context.Context) error
+	InfoFunc func(ctx context.Context) (map[string]interface{}, error)
+
+	CreateDocumentFunc func(ctx context.Context, index, id string, document interface{}, opts ...opensearch.DocumentOption) error
+	GetDocumentFunc    func(ctx context.Context, index, id string, opts ...opensearch.DocumentOption) (map[string]interface{}, error)
+	UpdateDocumentFunc func(ctx context.Context, index, id string, updates interface{}, opts ...opensearch.DocumentOption) error
+	DeleteDocumentFunc func(ctx context.Context, index, id string, opts ...opensearch.DocumentOption) error
+
+	SearchDocumentsFunc        func(ctx context.Context, index string, query map[string]interface{}) ([]map[string]interface{}, error)
+	SearchAllFunc              func(ctx context.Context, index string) ([]map[string]interface{}, error)
+	MultiSearchFunc            func(ctx context.Context, searches []opensearch.SearchSpec) ([][]map[string]interface{}, error)
+	SearchWithAggregationsFunc func(ctx context.Context, index string, query map[string]interface{}) ([]map[string]interface{}, map[string]interface{}, error)
+
+	BulkCreateFunc            func(ctx context.Context, index string, documents []map[string]interface{}) error
+	BulkCreateWithRefreshFunc func(ctx context.Context, index string, documents []map[string]interface{}, refresh string) error
+
+	CreateIndexFunc         func(ctx context.Context, index string, body map[string]interface{}) error
+	EnsureIndexFunc         func(ctx context.Context, index string, body map[string]interface{}) (bool, error)
+	DeleteIndexFunc         func(ctx context.Context, index string) error
+	DeleteIndexIfExistsFunc func(ctx context.Context, index string) (bool, error)
+	IndexExistsFunc         func(ctx context.Context, index string) (bool, error)
+	RefreshIndexFunc        func(ctx context.Context, indices ...string) error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// MockClient implements ClientAPI
+var _ opensearch.ClientAPI = (*MockClient)(nil)
+
+// Calls returns the calls made so far, in order
+func (m *MockClient) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls made so far to method, in order
+func (m *MockClient) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls
+func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *MockClient) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+// Ping records the call and calls PingFunc
+func (m *MockClient) Ping(ctx context.Context) error {
+	m.record("Ping")
+	if m.PingFunc == nil {
+		return nil
+	}
+	return m.PingFunc(ctx)
+}
+
+// Info records the call and calls InfoFunc
+func (m *MockClient) Info(ctx context.Context) (map[string]interface{}, error) {
+	m.record("Info")
+	if m.InfoFunc == nil {
+		return nil, nil
+	}
+	return m.InfoFunc(ctx)
+}
+
+// CreateDocument records the call and calls CreateDocumentFunc
+func (m *MockClient) CreateDocument(ctx context.Context, index, id string, document interface{}, opts ...opensearch.DocumentOption) error {
+	m.record("CreateDocument", index, id, document)
+	if m.CreateDocumentFunc == nil {
+		return nil
+	}
+	return m.CreateDocumentFunc(ctx, index, id, document, opts...)
+}
+
+// GetDocument records the call and calls GetDocumentFunc
+func (m *MockClient) GetDocument(ctx context.Context, index, id string, opts ...opensearch.DocumentOption) (map[string]interface{}, error) {
+	m.record("GetDocument", index, id)
+	if m.GetDocumentFunc == nil {
+		return nil, nil
+	}
+	return m.GetDocumentFunc(ctx, index, id, opts...)
+}
+
+// UpdateDocument records the call and calls UpdateDocumentFunc
+func (m *MockClient) UpdateDocument(ctx context.Context, index, id string, updates interface{}, opts ...opensearch.DocumentOption) error {
+	m.record("UpdateDocument", index, id, updates)
+	if m.UpdateDocumentFunc == nil {
+		return nil
+	}
+	return m.UpdateDocumentFunc(ctx, index, id, updates, opts...)
+}
+
+// DeleteDocument records the call and calls DeleteDocumentFunc
+func (m *MockClient) DeleteDocument(ctx context.Context, index, id string, opts ...opensearch.DocumentOption) error {
+	m.record("DeleteDocument", index, id)
+	if m.DeleteDocumentFunc == nil {
+		return nil
+	}
+	return m.DeleteDocumentFunc(ctx, index, id, opts...)
+}
+
+// SearchDocuments records the call and calls SearchDocumentsFunc
+func (m *MockClient) SearchDocuments(ctx context.Context, index string, query map[string]interface{}) ([]map[string]interface{}, error) {
+	m.record("SearchDocuments", index, query)
+	if m.SearchDocumentsFunc == nil {
+		return nil, nil
+	}
+	return m.SearchDocumentsFunc(ctx, index, query)
+}
+
+// SearchAll records the call and calls SearchAllFunc
+func (m *MockClient) SearchAll(ctx context.Context, index string) ([]map[string]interface{}, error) {
+	m.record("SearchAll", index)
+	if m.SearchAllFunc == nil {
+		return nil, nil
+	}
+	return m.SearchAllFunc(ctx, index)
+}
+
+// MultiSearch records the call and calls MultiSearchFunc
+func (m *MockClient) MultiSearch(ctx context.Context, searches []opensearch.SearchSpec) ([][]map[string]interface{}, error) {
+	m.record("MultiSearch", searches)
+	if m.MultiSearchFunc == nil {
+		return nil, nil
+	}
+	return m.MultiSearchFunc(ctx, searches)
+}
+
+// SearchWithAggregations records the call and calls SearchWithAggregationsFunc
+func (m *MockClient) SearchWithAggregations(ctx context.Context, index string, query map[string]interface{}) ([]map[string]interface{}, map[string]interface{}, error) {
+	m.record("SearchWithAggregations", index, query)
+	if m.SearchWithAggregationsFunc == nil {
+		return nil, nil, nil
+	}
+	return m.SearchWithAggregationsFunc(ctx, index, query)
+}
+
+// BulkCreate records the call and calls BulkCreateFunc
+func (m *MockClient) BulkCreate(ctx context.Context, index string, documents []map[string]interface{}) error {
+	m.record("BulkCreate", index, documents)
+	if m.BulkCreateFunc == nil {
+		return nil
+	}
+	return m.BulkCreateFunc(ctx, index, documents)
+}
+
+// BulkCreateWithRefresh records the call and calls BulkCreateWithRefreshFunc
+func (m *MockClient) BulkCreateWithRefresh(ctx context.Context, index string, documents []map[string]interface{}, refresh string) error {
+	m.record("BulkCreateWithRefresh", index, documents, refresh)
+	if m.BulkCreateWithRefreshFunc == nil {
+		return nil
+	}
+	return m.BulkCreateWithRefreshFunc(ctx, index, documents, refresh)
+}
+
+// CreateIndex records the call and calls CreateIndexFunc
+func (m *MockClient) CreateIndex(ctx context.Context, index string, body map[string]interface{}) error {
+	m.record("CreateIndex", index, body)
+	if m.CreateIndexFunc == nil {
+		return nil
+	}
+	return m.CreateIndexFunc(ctx, index, body)
+}
+
+// EnsureIndex records the call and calls EnsureIndexFunc
+func (m *MockClient) EnsureIndex(ctx context.Context, index string, body map[string]interface{}) (bool, error) {
+	m.record("EnsureIndex", index, body)
+	if m.EnsureIndexFunc == nil {
+		return false, nil
+	}
+	return m.EnsureIndexFunc(ctx, index, body)
+}
+
+// DeleteIndex records the call and calls DeleteIndexFunc
+func (m *MockClient) DeleteIndex(ctx context.Context, index string) error {
+	m.record("DeleteIndex", index)
+	if m.DeleteIndexFunc == nil {
+		return nil
+	}
+	return m.DeleteIndexFunc(ctx, index)
+}
+
+// DeleteIndexIfExists records the call and calls DeleteIndexIfExistsFunc
+func (m *MockClient) DeleteIndexIfExists(ctx context.Context, index string) (bool, error) {
+	m.record("DeleteIndexIfExists", index)
+	if m.DeleteIndexIfExistsFunc == nil {
+		return false, nil
+	}
+	return m.DeleteIndexIfExistsFunc(ctx, index)
+}
+
+// IndexExists records the call and calls IndexExistsFunc
+func (m *MockClient) IndexExists(ctx context.Context, index string) (bool, error) {
+	m.record("IndexExists", index)
+	if m.IndexExistsFunc == nil {
+		return false, nil
+	}
+	return m.IndexExistsFunc(ctx, index)
+}
+
+// RefreshIndex records the call and calls RefreshIndexFunc
+func (m *MockClient) RefreshIndex(ctx context.Context, indices ...string) error {
+	args := make([]interface{}, len(indices))
+	for i, index := range indices {
+		args[i] = index
+	}
+	m.record("RefreshIndex", args...)
+	if m.RefreshIndexFunc == nil {
+		return nil
+	}
+	return m.RefreshIndexFunc(ctx, indices...)
+}
@@ -0,0 +1,96 @@
+package opensearchtest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/yenonn/go-opensearch/pkg/opensearch"
+)
+
+// TestMockClient tests programmed responses, zero values and call recording
+func TestMockClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("zero values when unprogrammed", func(t *testing.T) {
+		mock := &MockClient{}
+		if err := mock.Ping(ctx); err != nil {
+			t.Errorf("Ping() error = %v", err)
+		}
+		doc, err := mock.GetDocument(ctx, "idx", "1")
+		if err != nil || doc != nil {
+			t.Errorf("GetDocument() = %v, %v, want nil, nil", doc, err)
+		}
+		exists, err := mock.IndexExists(ctx, "idx")
+		if err != nil || exists {
+			t.Errorf("IndexExists() = %v, %v, want false, nil", exists, err)
+		}
+	})
+
+	t.Run("programmed responses", func(t *testing.T) {
+		mock := &MockClient{
+			GetDocumentFunc: func(ctx context.Context, index, id string, opts ...opensearch.DocumentOption) (map[string]interface{}, error) {
+				if id == "missing" {
+					return nil, opensearch.ErrDocumentNotFound
+				}
+				return map[string]interface{}{"id": id}, nil
+			},
+		}
+
+		doc, err := mock.GetDocument(ctx, "idx", "1")
+		if err != nil {
+			t.Fatalf("GetDocument() error = %v", err)
+		}
+		if doc["id"] != "1" {
+			t.Errorf("GetDocument() = %v, want id 1", doc)
+		}
+		if _, err := mock.GetDocument(ctx, "idx", "missing"); !errors.Is(err, opensearch.ErrDocumentNotFound) {
+			t.Errorf("GetDocument() error = %v, want ErrDocumentNotFound", err)
+		}
+	})
+
+	t.Run("records calls", func(t *testing.T) {
+		mock := &MockClient{}
+		_ = mock.CreateDocument(ctx, "idx", "1", map[string]interface{}{"a": 1})
+		_ = mock.DeleteDocument(ctx, "idx", "1")
+		_ = mock.RefreshIndex(ctx, "idx", "other")
+		_ = mock.DeleteDocument(ctx, "idx", "2")
+
+		calls := mock.Calls()
+		if len(calls) != 4 {
+			t.Fatalf("Calls() returned %d calls, want 4", len(calls))
+		}
+		want := Call{Method: "RefreshIndex", Args: []interface{}{"idx", "other"}}
+		if !reflect.DeepEqual(calls[2], want) {
+			t.Errorf("Calls()[2] = %+v, want %+v", calls[2], want)
+		}
+
+		deletes := mock.CallsTo("DeleteDocument")
+		if len(deletes) != 2 || deletes[1].Args[1] != "2" {
+			t.Errorf("CallsTo(DeleteDocument) = %+v, want two calls ending with id 2", deletes)
+		}
+
+		mock.Reset()
+		if calls := mock.Calls(); len(calls) != 0 {
+			t.Errorf("Calls() after Reset() = %+v, want none", calls)
+		}
+	})
+
+	t.Run("concurrent calls", func(t *testing.T) {
+		mock := &MockClient{}
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = mock.Ping(ctx)
+			}()
+		}
+		wg.Wait()
+		if got := len(mock.CallsTo("Ping")); got != 20 {
+			t.Errorf("CallsTo(Ping) returned %d calls, want 20", got)
+		}
+	})
+}
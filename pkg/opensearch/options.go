@@ -0,0 +1,51 @@
+package opensearch
+
+import "time"
+
+// Option sets a field of the Config built by NewClientWithOptions
+type Option func(*Config)
+
+// NewClientWithOptions creates a client for the given addresses, configured by opts.
+// It is equivalent to calling NewClient with the Config the options describe.
+func NewClientWithOptions(addresses []string, opts ...Option) (*Client, error) {
+	return NewClient(newConfig(addresses, opts))
+}
+
+// newConfig builds the Config described by the addresses and options
+func newConfig(addresses []string, opts []Option) Config {
+	config := Config{Addresses: addresses}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// WithBasicAuth authenticates every request with a username and password
+func WithBasicAuth(username, password string) Option {
+	return func(c *Config) {
+		c.Username = username
+		c.Password = password
+	}
+}
+
+// WithInsecureTLS skips TLS certificate verification (use for development only)
+func WithInsecureTLS() Option {
+	return func(c *Config) {
+		c.InsecureSkipVerify = true
+	}
+}
+
+// WithRetries sets how many times a failed request is retried; 0 turns retries off
+func WithRetries(maxRetries int) Option {
+	return func(c *Config) {
+		c.MaxRetries = maxRetries
+		c.DisableRetry = maxRetries <= 0
+	}
+}
+
+// WithTimeout bounds each attempt of a request, as Config.RequestTimeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.RequestTimeout = timeout
+	}
+}
@@ -0,0 +1,74 @@
+package opensearch
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewConfig(t *testing.T) {
+	addresses := []string{"http://localhost:9200"}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want Config
+	}{
+		{
+			name: "No options",
+			want: Config{Addresses: addresses},
+		},
+		{
+			name: "Basic auth",
+			opts: []Option{WithBasicAuth("admin", "secret")},
+			want: Config{Addresses: addresses, Username: "admin", Password: "secret"},
+		},
+		{
+			name: "Insecure TLS",
+			opts: []Option{WithInsecureTLS()},
+			want: Config{Addresses: addresses, InsecureSkipVerify: true},
+		},
+		{
+			name: "Retries",
+			opts: []Option{WithRetries(5)},
+			want: Config{Addresses: addresses, MaxRetries: 5},
+		},
+		{
+			name: "No retries",
+			opts: []Option{WithRetries(0)},
+			want: Config{Addresses: addresses, DisableRetry: true},
+		},
+		{
+			name: "Timeout",
+			opts: []Option{WithTimeout(3 * time.Second)},
+			want: Config{Addresses: addresses, RequestTimeout: 3 * time.Second},
+		},
+		{
+			name: "Later options win",
+			opts: []Option{WithRetries(0), WithTimeout(time.Second), WithRetries(2)},
+			want: Config{Addresses: addresses, MaxRetries: 2, RequestTimeout: time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newConfig(addresses, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	client, err := NewClientWithOptions([]string{"http://localhost:9200"}, WithBasicAuth("admin", "secret"), WithRetries(1))
+	if err != nil {
+		t.Fatalf("NewClientWithOptions() error = %v", err)
+	}
+	if client == nil {
+		t.Fatal("NewClientWithOptions() returned nil client")
+	}
+
+	if _, err := NewClientWithOptions(nil); err == nil || err.Error() != "at least one address is required" {
+		t.Errorf("NewClientWithOptions() without addresses error = %v, want address validation error", err)
+	}
+}
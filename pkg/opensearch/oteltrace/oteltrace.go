@@ -0,0 +1,79 @@
+// Package oteltrace traces the requests of an opensearch.Client with OpenTelemetry.
+// It is a separate module so that the client itself does not depend on the OpenTelemetry
+// libraries.
+package oteltrace
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/yenonn/go-opensearch/pkg/opensearch"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans started by this package
+const instrumentationName = "github.com/yenonn/go-opensearch/pkg/opensearch/oteltrace"
+
+// Tracer is an opensearch.Tracer. Set it as Config.Tracer to start a client span named
+// "opensearch.<operation>" (e.g. "opensearch.search") for every request, with the
+// attributes:
+//
+//   - db.system ("opensearch"), db.operation and http.request.method
+//   - opensearch.index and opensearch.document_id, when the request has them
+//   - http.response.status_code, once a response is received
+//   - opensearch.hits, the total hit count of a search
+//
+// A request that fails or gets a 4xx or 5xx response sets the span status to Error.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer creates a Tracer that starts spans from provider, or from the global
+// TracerProvider when provider is nil
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: provider.Tracer(instrumentationName)}
+}
+
+// StartSpan implements opensearch.Tracer
+func (t *Tracer) StartSpan(ctx context.Context, start opensearch.SpanStart) (context.Context, func(opensearch.SpanEnd)) {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "opensearch"),
+		attribute.String("db.operation", start.Operation),
+		attribute.String("http.request.method", start.Method),
+	}
+	if start.Index != "" {
+		attrs = append(attrs, attribute.String("opensearch.index", start.Index))
+	}
+	if start.DocumentID != "" {
+		attrs = append(attrs, attribute.String("opensearch.document_id", start.DocumentID))
+	}
+
+	ctx, span := t.tracer.Start(ctx, "opensearch."+start.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, func(end opensearch.SpanEnd) {
+		if end.Status != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", end.Status))
+		}
+		if end.Hits >= 0 {
+			span.SetAttributes(attribute.Int64("opensearch.hits", end.Hits))
+		}
+
+		switch {
+		case end.Err != nil:
+			span.RecordError(end.Err)
+			span.SetStatus(codes.Error, end.Err.Error())
+		case end.Status >= 400:
+			span.SetStatus(codes.Error, http.StatusText(end.Status))
+		}
+		span.End()
+	}
+}
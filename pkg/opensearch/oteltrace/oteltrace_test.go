@@ -0,0 +1,156 @@
+package oteltrace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yenonn/go-opensearch/pkg/opensearch"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestClient creates a client for a stub cluster, traced into the returned recorder.
+// Searches find 7 hits and requests for missing documents get a 404.
+func newTestClient(t *testing.T) (*opensearch.Client, *sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/missing"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"found":false}`))
+		case strings.HasSuffix(r.URL.Path, "/_search"):
+			_, _ = w.Write([]byte(`{"took":1,"hits":{"total":{"value":7},"hits":[]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"_id":"1","found":true,"_source":{"title":"one"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client, err := opensearch.NewClient(opensearch.Config{
+		Addresses:    []string{server.URL},
+		DisableRetry: true,
+		Tracer:       NewTracer(provider),
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client, provider, recorder
+}
+
+// spanAttributes returns the attributes of a span by key
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracer_Search(t *testing.T) {
+	client, provider, recorder := newTestClient(t)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "handler")
+	if _, err := client.SearchDocuments(ctx, "my-index", opensearch.MatchAllQuery()); err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Recorded %d spans, want the search and its parent", len(spans))
+	}
+	span := spans[0]
+
+	if span.Name() != "opensearch.search" {
+		t.Errorf("Span name = %q, want opensearch.search", span.Name())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() || span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Error("Search span is not a child of the caller's span")
+	}
+
+	attrs := spanAttributes(span)
+	if got := attrs["opensearch.index"].AsString(); got != "my-index" {
+		t.Errorf("opensearch.index = %q, want my-index", got)
+	}
+	if got := attrs["opensearch.hits"].AsInt64(); got != 7 {
+		t.Errorf("opensearch.hits = %d, want 7", got)
+	}
+	if got := attrs["http.response.status_code"].AsInt64(); got != 200 {
+		t.Errorf("http.response.status_code = %d, want 200", got)
+	}
+	if span.Status().Code != codes.Unset {
+		t.Errorf("Span status = %v, want unset", span.Status())
+	}
+}
+
+func TestTracer_Document(t *testing.T) {
+	client, _, recorder := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.GetDocument(ctx, "my-index", "1"); err != nil {
+		t.Fatalf("GetDocument() error = %v", err)
+	}
+	if err := client.DeleteDocument(ctx, "my-index", "missing"); err == nil {
+		t.Fatal("DeleteDocument() of a missing document succeeded")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Recorded %d spans, want 2", len(spans))
+	}
+
+	get := spans[0]
+	if get.Name() != "opensearch.get" {
+		t.Errorf("Span name = %q, want opensearch.get", get.Name())
+	}
+	if got := spanAttributes(get)["opensearch.document_id"].AsString(); got != "1" {
+		t.Errorf("opensearch.document_id = %q, want 1", got)
+	}
+
+	deleted := spans[1]
+	if deleted.Name() != "opensearch.delete" {
+		t.Errorf("Span name = %q, want opensearch.delete", deleted.Name())
+	}
+	if got := spanAttributes(deleted)["http.response.status_code"].AsInt64(); got != 404 {
+		t.Errorf("http.response.status_code = %d, want 404", got)
+	}
+	if deleted.Status().Code != codes.Error {
+		t.Errorf("Span status = %v, want error", deleted.Status())
+	}
+}
+
+func TestTracer_TransportError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client, err := opensearch.NewClient(opensearch.Config{
+		Addresses:    []string{"http://127.0.0.1:1"},
+		DisableRetry: true,
+		Tracer:       NewTracer(provider),
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, err := client.GetDocument(context.Background(), "my-index", "1"); err == nil {
+		t.Fatal("GetDocument() against a closed port succeeded")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Recorded %d spans, want 1", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error || len(spans[0].Events()) == 0 {
+		t.Errorf("Span status = %v with %d events, want an error with the recorded exception", spans[0].Status(), len(spans[0].Events()))
+	}
+}
@@ -0,0 +1,408 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxResultWindow is OpenSearch's default index.max_result_window, the deepest
+// from+size a regular search may request
+const maxResultWindow = 10000
+
+// defaultIteratorKeepAlive is how long a SearchIterator keeps its point in time alive
+// between pages
+const defaultIteratorKeepAlive = time.Minute
+
+// ResumableExport returns one batch of documents matching the query plus the
+// search_after cursor of the last hit. Persist nextAfter and pass it back as
+// after to continue the export, even across process restarts; a nil after
+// starts from the beginning and a nil nextAfter means the export is complete.
+//
+// When the query has no sort, hits are sorted by _doc. For indices with more than
+// one shard, supply a sort ending in a unique field so ties cannot be skipped.
+func (c *Client) ResumableExport(ctx context.Context, index string, query map[string]interface{}, after []interface{}, batchSize int) (docs []map[string]interface{}, nextAfter []interface{}, err error) {
+	defer c.observe("ResumableExport", time.Now(), &err)
+
+	if batchSize <= 0 {
+		return nil, nil, fmt.Errorf("batch size must be positive")
+	}
+
+	var response SearchResponse
+	if err := c.search(ctx, index, searchAfterBody(query, after, batchSize), &response); err != nil {
+		return nil, nil, err
+	}
+
+	hits := response.Hits.Hits
+	docs = hitsToDocuments(hits)
+	if len(hits) == batchSize {
+		nextAfter = hits[len(hits)-1].Sort
+	}
+
+	return docs, nextAfter, nil
+}
+
+// SearchAllMatching returns every document matching the query by paging with
+// from/size until a page comes back with fewer than pageSize hits. Paging past
+// the 10,000-hit result window continues with search_after when the query has a
+// sort; without one, exceeding the window returns an error.
+func (c *Client) SearchAllMatching(ctx context.Context, index string, query map[string]interface{}, pageSize int) (_ []map[string]interface{}, err error) {
+	defer c.observe("SearchAllMatching", time.Now(), &err)
+
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("page size must be positive")
+	}
+
+	_, sorted := query["sort"]
+	var results []map[string]interface{}
+	var after []interface{}
+
+	for from := 0; ; from += pageSize {
+		var body map[string]interface{}
+		if from+pageSize <= maxResultWindow {
+			body = pageBody(query, from, pageSize)
+		} else {
+			if !sorted || after == nil {
+				return nil, fmt.Errorf("more than %d matching documents: add a sort to the query to page beyond the result window", maxResultWindow)
+			}
+			body = searchAfterBody(query, after, pageSize)
+		}
+
+		var response SearchResponse
+		if err := c.search(ctx, index, body, &response); err != nil {
+			return nil, err
+		}
+
+		hits := response.Hits.Hits
+		results = append(results, hitsToDocuments(hits)...)
+		if len(hits) < pageSize {
+			return results, nil
+		}
+		after = hits[len(hits)-1].Sort
+	}
+}
+
+// pageBody returns a copy of the query with from and size applied
+func pageBody(query map[string]interface{}, from, size int) map[string]interface{} {
+	body := make(map[string]interface{}, len(query)+2)
+	for k, v := range query {
+		body[k] = v
+	}
+	body["from"] = from
+	body["size"] = size
+	return body
+}
+
+// searchAfterBody returns a copy of the query with size, a default sort and the
+// search_after cursor applied, leaving the caller's query untouched
+func searchAfterBody(query map[string]interface{}, after []interface{}, size int) map[string]interface{} {
+	body := make(map[string]interface{}, len(query)+3)
+	for k, v := range query {
+		body[k] = v
+	}
+	if _, ok := body["query"]; !ok {
+		body["query"] = map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+	if _, ok := body["sort"]; !ok {
+		body["sort"] = []interface{}{"_doc"}
+	}
+	body["size"] = size
+	delete(body, "from")
+	if len(after) > 0 {
+		body["search_after"] = after
+	} else {
+		delete(body, "search_after")
+	}
+	return body
+}
+
+// SearchIterator iterates over every document matching a query, fetching it a page at a
+// time. Create one with Client.SearchIterator and read it with:
+//
+//	it := client.SearchIterator(ctx, index, query, 100)
+//	defer it.Close()
+//	for it.Next() {
+//		doc := it.Doc()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Pages are read with search_after within a point in time, so documents added or removed
+// during the iteration neither show up nor cause duplicates. On clusters without point in
+// time support it falls back to search_after on the live index.
+type SearchIterator struct {
+	client   *Client
+	ctx      context.Context
+	index    string
+	query    map[string]interface{}
+	pageSize int
+
+	pitID   string
+	after   []interface{}
+	page    []iteratorHit
+	raw     RawHit
+	hit     Hit
+	doc     map[string]interface{}
+	total   int
+	started bool
+	last    bool
+	done    bool
+	err     error
+}
+
+// SearchIterator returns an iterator over the documents matching query, in the order of its
+// sort, fetching pageSize documents per request. When the query has no sort, documents come
+// in index order; for indices with more than one shard, supply a sort ending in a unique
+// field so ties cannot be skipped. Iteration stops with the context's error when ctx is done.
+func (c *Client) SearchIterator(ctx context.Context, index string, query map[string]interface{}, pageSize int) *SearchIterator {
+	it := &SearchIterator{
+		client:   c,
+		ctx:      ctx,
+		index:    index,
+		query:    query,
+		pageSize: pageSize,
+	}
+	if pageSize <= 0 {
+		it.err = fmt.Errorf("page size must be positive")
+		it.done = true
+	}
+	return it
+}
+
+// Next advances to the next document, fetching the next page when needed. It returns false
+// once every document has been read or an error occurred; check Err to tell them apart.
+func (it *SearchIterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	if len(it.page) == 0 {
+		if it.last {
+			it.finish(nil)
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.finish(err)
+			return false
+		}
+		if len(it.page) == 0 {
+			it.finish(nil)
+			return false
+		}
+	}
+
+	next := it.page[0]
+	it.page = it.page[1:]
+	hit := Hit{
+		Index:          next.Index,
+		ID:             next.ID,
+		Score:          next.Score,
+		Sort:           next.Sort,
+		MatchedQueries: next.MatchedQueries,
+	}
+	if len(next.Source) > 0 {
+		if err := it.client.parseResponse(bytes.NewReader(next.Source), &hit.Source); err != nil {
+			it.finish(fmt.Errorf("failed to decode document %s: %w", next.ID, err))
+			return false
+		}
+	}
+	it.raw = next.RawHit
+	it.hit = hit
+	it.doc = hitDocument(hit)
+	return true
+}
+
+// iteratorHit is a hit as fetched by SearchIterator, with the source kept as read so that
+// callers such as DumpIndex can write it unchanged
+type iteratorHit struct {
+	RawHit
+	Sort []interface{} `json:"sort,omitempty"`
+}
+
+// Doc returns the current document
+func (it *SearchIterator) Doc() map[string]interface{} {
+	return it.doc
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+// Total returns the number of matching documents, once the first page has been fetched
+func (it *SearchIterator) Total() int {
+	return it.total
+}
+
+// Close stops the iteration and releases its point in time. The iterator closes itself
+// once Next returns false, so Close only matters when stopping early; closing it more than
+// once is a no-op.
+func (it *SearchIterator) Close() error {
+	if it.done {
+		return nil
+	}
+	it.finish(nil)
+	return it.err
+}
+
+// fetch reads the next page, opening the point in time on the first call
+func (it *SearchIterator) fetch() error {
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+
+	if !it.started {
+		pitID, err := it.client.openPointInTime(it.ctx, it.index, defaultIteratorKeepAlive)
+		if err != nil && !pointInTimeUnsupported(err) {
+			return err
+		}
+		it.pitID = pitID
+	}
+
+	body := searchAfterBody(it.query, it.after, it.pageSize)
+	index := it.index
+	if it.pitID != "" {
+		body["pit"] = map[string]interface{}{
+			"id":         it.pitID,
+			"keep_alive": timeValue(defaultIteratorKeepAlive),
+		}
+		index = ""
+	}
+	if !it.started {
+		body["track_total_hits"] = true
+	}
+
+	var response struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []iteratorHit `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := it.client.search(it.ctx, index, body, &response); err != nil {
+		return err
+	}
+
+	if !it.started {
+		it.total = response.Hits.Total.Value
+		it.started = true
+	}
+	hits := response.Hits.Hits
+	it.page = hits
+	if len(hits) < it.pageSize {
+		it.last = true
+	} else {
+		it.after = hits[len(hits)-1].Sort
+	}
+	return nil
+}
+
+// finish ends the iteration with err, closing the point in time
+func (it *SearchIterator) finish(err error) {
+	it.done = true
+	it.page = nil
+	it.raw = RawHit{}
+	it.hit = Hit{}
+	it.doc = nil
+	it.err = err
+
+	if it.pitID == "" {
+		return
+	}
+	// Release the point in time even when the iteration stopped because ctx is done
+	closeErr := it.client.closePointInTime(context.WithoutCancel(it.ctx), it.pitID)
+	it.pitID = ""
+	if closeErr != nil && it.err == nil {
+		it.err = closeErr
+	}
+}
+
+// hitDocument returns the source of hit annotated with _id and _score, like
+// hitsToDocuments, without modifying the hit
+func hitDocument(hit Hit) map[string]interface{} {
+	doc := make(map[string]interface{}, len(hit.Source)+2)
+	for k, v := range hit.Source {
+		doc[k] = v
+	}
+	doc["_id"] = hit.ID
+	doc["_score"] = hit.Score
+	return doc
+}
+
+// pointInTimeUnsupported reports whether opening a point in time failed because the
+// cluster does not support it, e.g. OpenSearch before 2.4
+func pointInTimeUnsupported(err error) bool {
+	var osErr *OpenSearchError
+	if !errors.As(err, &osErr) {
+		return false
+	}
+	return osErr.StatusCode == 400 || osErr.StatusCode == 405
+}
+
+// StreamOptions controls how SearchStream fetches and buffers hits
+type StreamOptions struct {
+	// PageSize is the number of hits fetched per request (default 500)
+	PageSize int
+	// BufferSize is the capacity of the hit channel (default PageSize). Fetching pauses
+	// while the buffer is full, so a slow consumer holds back the search.
+	BufferSize int
+}
+
+// SearchStream streams the hits matching query on the returned channel as pages arrive,
+// read like SearchIterator within a point in time. The hit channel is closed when every hit
+// has been sent or the stream stops; the error channel then yields at most one error, such
+// as a failed page or the context's error when ctx is done, and is closed too:
+//
+//	hits, errc := client.SearchStream(ctx, index, query, opensearch.StreamOptions{})
+//	for hit := range hits {
+//		...
+//	}
+//	if err := <-errc; err != nil {
+//		...
+//	}
+//
+// Cancel ctx to stop early; the point in time is released once the stream stops.
+func (c *Client) SearchStream(ctx context.Context, index string, query map[string]interface{}, opts StreamOptions) (<-chan Hit, <-chan error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultScrollSize
+	}
+	bufferSize := opts.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = pageSize
+	}
+
+	hits := make(chan Hit, bufferSize)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(hits)
+
+		it := c.SearchIterator(ctx, index, query, pageSize)
+		defer it.Close()
+
+		for it.Next() {
+			if err := ctx.Err(); err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case hits <- it.hit:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			errc <- err
+		}
+	}()
+
+	return hits, errc
+}
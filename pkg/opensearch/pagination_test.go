@@ -0,0 +1,436 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yenonn/go-opensearch/pkg/opensearch/internal/fakeos"
+)
+
+func TestSearchAfterBody(t *testing.T) {
+	t.Run("defaults applied without mutating input", func(t *testing.T) {
+		query := MatchQuery("title", "go")
+		body := searchAfterBody(query, nil, 10)
+
+		if body["size"] != 10 {
+			t.Errorf("size = %v, want 10", body["size"])
+		}
+		if !reflect.DeepEqual(body["sort"], []interface{}{"_doc"}) {
+			t.Errorf("sort = %v, want [_doc]", body["sort"])
+		}
+		if _, exists := body["search_after"]; exists {
+			t.Error("search_after should not be set without a cursor")
+		}
+		if _, exists := query["size"]; exists {
+			t.Error("input query should not be mutated")
+		}
+	})
+
+	t.Run("cursor and caller sort preserved", func(t *testing.T) {
+		query := WithSort(MatchAllQuery(), "views", "asc")
+		query = WithFrom(query, 20)
+		body := searchAfterBody(query, []interface{}{150, "doc-3"}, 5)
+
+		if !reflect.DeepEqual(body["search_after"], []interface{}{150, "doc-3"}) {
+			t.Errorf("search_after = %v, want [150 doc-3]", body["search_after"])
+		}
+		if !reflect.DeepEqual(body["sort"], query["sort"]) {
+			t.Errorf("sort = %v, want caller sort %v", body["sort"], query["sort"])
+		}
+		if _, exists := body["from"]; exists {
+			t.Error("from must be dropped when using search_after")
+		}
+	})
+
+	t.Run("missing query defaults to match_all", func(t *testing.T) {
+		body := searchAfterBody(map[string]interface{}{}, nil, 1)
+		if !reflect.DeepEqual(body["query"], MatchAllQuery()["query"]) {
+			t.Errorf("query = %v, want match_all", body["query"])
+		}
+	})
+}
+
+func TestResumableExport(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-resumable-export"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	const total = 25
+	docs := make([]map[string]interface{}, total)
+	for i := 0; i < total; i++ {
+		docs[i] = map[string]interface{}{"_id": fmt.Sprintf("doc-%02d", i), "seq": i}
+	}
+	if err := client.BulkCreate(ctx, indexName, docs); err != nil {
+		t.Fatalf("Failed to seed documents: %v", err)
+	}
+
+	seen := make(map[string]int)
+
+	// Export the first batch, then "crash" keeping only the persisted cursor
+	batch, cursor, err := client.ResumableExport(ctx, indexName, MatchAllQuery(), nil, 10)
+	if err != nil {
+		t.Fatalf("ResumableExport() first batch error = %v", err)
+	}
+	if len(batch) != 10 || cursor == nil {
+		t.Fatalf("First batch = %d docs, cursor %v; want 10 docs and a cursor", len(batch), cursor)
+	}
+	for _, doc := range batch {
+		seen[doc["_id"].(string)]++
+	}
+
+	// Resume from the persisted cursor until exhausted
+	for cursor != nil {
+		batch, cursor, err = client.ResumableExport(ctx, indexName, MatchAllQuery(), cursor, 10)
+		if err != nil {
+			t.Fatalf("ResumableExport() resumed batch error = %v", err)
+		}
+		for _, doc := range batch {
+			seen[doc["_id"].(string)]++
+		}
+	}
+
+	if len(seen) != total {
+		t.Errorf("Exported %d distinct documents, want %d", len(seen), total)
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("Document %s exported %d times, want once", id, count)
+		}
+	}
+}
+
+func TestPageBody(t *testing.T) {
+	query := MatchQuery("title", "go")
+	body := pageBody(query, 50, 25)
+
+	if body["from"] != 50 || body["size"] != 25 {
+		t.Errorf("pageBody() from/size = %v/%v, want 50/25", body["from"], body["size"])
+	}
+	if _, exists := query["from"]; exists {
+		t.Error("input query should not be mutated")
+	}
+}
+
+func TestSearchAllMatching(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-search-all-matching"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	const total = 150
+	docs := make([]map[string]interface{}, total)
+	for i := 0; i < total; i++ {
+		docs[i] = map[string]interface{}{"_id": fmt.Sprintf("doc-%03d", i), "seq": i}
+	}
+	if err := client.BulkCreate(ctx, indexName, docs); err != nil {
+		t.Fatalf("Failed to seed documents: %v", err)
+	}
+
+	results, err := client.SearchAllMatching(ctx, indexName, MatchAllQuery(), 50)
+	if err != nil {
+		t.Fatalf("SearchAllMatching() error = %v", err)
+	}
+
+	seen := make(map[string]int)
+	for _, doc := range results {
+		seen[doc["_id"].(string)]++
+	}
+	if len(results) != total || len(seen) != total {
+		t.Errorf("SearchAllMatching() returned %d results (%d distinct), want %d", len(results), len(seen), total)
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("Document %s returned %d times, want once", id, count)
+		}
+	}
+}
+
+// seedFakeDocuments stores count documents numbered by their "n" field, with IDs that
+// do not sort in the same order as n
+func seedFakeDocuments(server *fakeos.Server, index string, count int) {
+	for i := 0; i < count; i++ {
+		server.PutDocument(index, fmt.Sprintf("doc-%d", count-i), map[string]interface{}{"n": i})
+	}
+}
+
+func TestSearchIterator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("iterates every document in sort order", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "items", 2500)
+
+		it := client.SearchIterator(ctx, "items", WithSort(MatchAllQuery(), "n", "asc"), 100)
+		count := 0
+		for it.Next() {
+			if count == 0 && it.Total() != 2500 {
+				t.Errorf("Total() = %d after the first page, want 2500", it.Total())
+			}
+			if n := it.Doc()["n"]; n != float64(count) {
+				t.Fatalf("document %d has n = %v, want %d", count, n, count)
+			}
+			count++
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("Err() = %v", err)
+		}
+		if count != 2500 {
+			t.Errorf("iterated %d documents, want 2500", count)
+		}
+		if open := server.OpenPointsInTime(); open != 0 {
+			t.Errorf("%d points in time left open", open)
+		}
+	})
+
+	t.Run("descending sort", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "items", 250)
+
+		it := client.SearchIterator(ctx, "items", WithSort(MatchAllQuery(), "n", "desc"), 100)
+		want := 249
+		for it.Next() {
+			if n := it.Doc()["n"]; n != float64(want) {
+				t.Fatalf("got n = %v, want %d", n, want)
+			}
+			want--
+		}
+		if it.Err() != nil || want != -1 {
+			t.Errorf("stopped at n = %d with error %v, want every document", want+1, it.Err())
+		}
+	})
+
+	t.Run("documents changed during iteration", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "items", 2500)
+
+		it := client.SearchIterator(ctx, "items", WithSort(MatchAllQuery(), "n", "asc"), 100)
+		seen := make(map[string]bool)
+		for it.Next() {
+			id := it.Doc()["_id"].(string)
+			if seen[id] {
+				t.Fatalf("document %s returned twice", id)
+			}
+			seen[id] = true
+
+			if len(seen) == 1000 {
+				// Insert documents sorting before and after the cursor, and remove unread ones
+				server.PutDocument("items", "new-early", map[string]interface{}{"n": -1})
+				server.PutDocument("items", "new-late", map[string]interface{}{"n": 5000})
+				for i := 2000; i < 2010; i++ {
+					if err := client.DeleteDocument(ctx, "items", fmt.Sprintf("doc-%d", 2500-i)); err != nil {
+						t.Fatalf("DeleteDocument() error = %v", err)
+					}
+				}
+			}
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("Err() = %v", err)
+		}
+		if len(seen) != 2500 {
+			t.Errorf("iterated %d documents, want the 2500 present when iteration started", len(seen))
+		}
+	})
+
+	t.Run("stops when context is cancelled", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "items", 500)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		it := client.SearchIterator(ctx, "items", WithSort(MatchAllQuery(), "n", "asc"), 100)
+		count := 0
+		for it.Next() {
+			count++
+			if count == 150 {
+				cancel()
+			}
+		}
+		if !errors.Is(it.Err(), context.Canceled) {
+			t.Errorf("Err() = %v, want context.Canceled", it.Err())
+		}
+		if count != 200 {
+			t.Errorf("iterated %d documents, want the 200 already fetched", count)
+		}
+		if open := server.OpenPointsInTime(); open != 0 {
+			t.Errorf("%d points in time left open after cancellation", open)
+		}
+	})
+
+	t.Run("close releases the point in time", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "items", 300)
+
+		it := client.SearchIterator(ctx, "items", nil, 100)
+		if !it.Next() {
+			t.Fatalf("Next() = false, Err() = %v", it.Err())
+		}
+		if err := it.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if err := it.Close(); err != nil {
+			t.Errorf("second Close() error = %v", err)
+		}
+		if it.Next() {
+			t.Error("Next() = true after Close()")
+		}
+		if open := server.OpenPointsInTime(); open != 0 {
+			t.Errorf("%d points in time left open after Close()", open)
+		}
+	})
+
+	t.Run("missing index", func(t *testing.T) {
+		client, _ := newFakeClient(t)
+
+		it := client.SearchIterator(ctx, "nope", nil, 100)
+		if it.Next() {
+			t.Fatal("Next() = true for a missing index")
+		}
+		if !errors.Is(it.Err(), ErrIndexNotFound) {
+			t.Errorf("Err() = %v, want ErrIndexNotFound", it.Err())
+		}
+	})
+
+	t.Run("invalid page size", func(t *testing.T) {
+		client, _ := newFakeClient(t)
+
+		it := client.SearchIterator(ctx, "items", nil, 0)
+		if it.Next() || it.Err() == nil {
+			t.Errorf("Next() with page size 0 should fail, Err() = %v", it.Err())
+		}
+	})
+
+	t.Run("falls back to search_after without point in time support", func(t *testing.T) {
+		stub := &stubTransport{
+			statuses: []int{400},
+			status:   200,
+			body:     `{"hits":{"total":{"value":0,"relation":"eq"},"hits":[]}}`,
+		}
+		client := newStubClient(t, stub)
+
+		it := client.SearchIterator(ctx, "items", nil, 100)
+		if it.Next() {
+			t.Fatal("Next() = true for an empty result")
+		}
+		if err := it.Err(); err != nil {
+			t.Fatalf("Err() = %v", err)
+		}
+		if stub.path != "/items/_search" || strings.Contains(string(stub.sent), `"pit"`) {
+			t.Errorf("searched %s with body %s, want a search of the index without a pit", stub.path, stub.sent)
+		}
+	})
+}
+
+// drainStream reads every hit and the terminal error of a stream, failing the test if the
+// stream does not end
+func drainStream(t *testing.T, hits <-chan Hit, errc <-chan error, onHit func(Hit)) error {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case hit, ok := <-hits:
+			if !ok {
+				err := <-errc
+				if extra, ok := <-errc; ok {
+					t.Errorf("error channel delivered a second error: %v", extra)
+				}
+				return err
+			}
+			if onHit != nil {
+				onHit(hit)
+			}
+		case <-timeout:
+			t.Fatal("stream did not end")
+		}
+	}
+}
+
+func TestSearchStream(t *testing.T) {
+	t.Run("streams every hit", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "items", 1200)
+
+		hits, errc := client.SearchStream(context.Background(), "items", WithSort(MatchAllQuery(), "n", "asc"),
+			StreamOptions{PageSize: 100, BufferSize: 10})
+		count := 0
+		err := drainStream(t, hits, errc, func(hit Hit) {
+			if n := hit.Source["n"]; n != float64(count) {
+				t.Fatalf("hit %d has n = %v, want %d", count, n, count)
+			}
+			count++
+		})
+		if err != nil {
+			t.Fatalf("stream error = %v", err)
+		}
+		if count != 1200 {
+			t.Errorf("streamed %d hits, want 1200", count)
+		}
+		if open := server.OpenPointsInTime(); open != 0 {
+			t.Errorf("%d points in time left open", open)
+		}
+	})
+
+	t.Run("cancelled mid-stream", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "items", 1000)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		hits, errc := client.SearchStream(ctx, "items", nil, StreamOptions{PageSize: 100, BufferSize: 10})
+		count := 0
+		err := drainStream(t, hits, errc, func(Hit) {
+			count++
+			if count == 50 {
+				cancel()
+			}
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("stream error = %v, want context.Canceled", err)
+		}
+		// At most the buffered hits arrive after the cancellation
+		if count >= 1000 {
+			t.Errorf("streamed %d hits, want the stream to stop early", count)
+		}
+		if open := server.OpenPointsInTime(); open != 0 {
+			t.Errorf("%d points in time left open after cancellation", open)
+		}
+	})
+
+	t.Run("server error after the first page", func(t *testing.T) {
+		client, server := newFakeClient(t)
+		seedFakeDocuments(server, "items", 500)
+		searches := 0
+		server.Fail(func(r fakeos.Request) bool {
+			if r.Path != "/_search" {
+				return false
+			}
+			searches++
+			return searches > 1
+		}, 500, "search_phase_execution_exception")
+
+		hits, errc := client.SearchStream(context.Background(), "items", nil, StreamOptions{PageSize: 100})
+		count := 0
+		err := drainStream(t, hits, errc, func(Hit) { count++ })
+
+		var osErr *OpenSearchError
+		if !errors.As(err, &osErr) || osErr.StatusCode != 500 || osErr.Type != "search_phase_execution_exception" {
+			t.Errorf("stream error = %v, want the failed search", err)
+		}
+		if count != 100 {
+			t.Errorf("streamed %d hits, want the 100 of the first page", count)
+		}
+		if open := server.OpenPointsInTime(); open != 0 {
+			t.Errorf("%d points in time left open after the error", open)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package opensearch
+
+import (
+	"context"
+	"time"
+)
+
+// PercolatorField is the field of a percolator index that holds the stored queries
+const PercolatorField = "query"
+
+// CreatePercolatorIndex creates an index for stored queries. Stored queries are parsed
+// against the index mappings, so properties must map every field they use, e.g.
+// {"title": {"type": "text"}}.
+func (c *Client) CreatePercolatorIndex(ctx context.Context, index string, properties map[string]interface{}) (err error) {
+	defer c.observe("CreatePercolatorIndex", time.Now(), &err)
+
+	mapped := make(map[string]interface{}, len(properties)+1)
+	for field, mapping := range properties {
+		mapped[field] = mapping
+	}
+	mapped[PercolatorField] = map[string]interface{}{"type": "percolator"}
+
+	return c.createIndex(ctx, index, map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": mapped,
+		},
+	})
+}
+
+// StoreQuery stores query under id in a percolator index created with CreatePercolatorIndex,
+// replacing any query stored under the same id. The query can be given on its own or as a
+// search body such as the result of MatchQuery.
+func (c *Client) StoreQuery(ctx context.Context, index, id string, query map[string]interface{}) (err error) {
+	defer c.observe("StoreQuery", time.Now(), &err)
+
+	if inner, ok := query["query"].(map[string]interface{}); ok {
+		query = inner
+	}
+	return c.createDocument(ctx, index, id, map[string]interface{}{PercolatorField: query})
+}
+
+// Percolate returns the IDs of the queries stored in index that match document, which is
+// not indexed. Every match is returned: when there are more than fit in one page, the rest
+// are read with search_after.
+func (c *Client) Percolate(ctx context.Context, index string, document map[string]interface{}) (_ []string, err error) {
+	defer c.observe("Percolate", time.Now(), &err)
+
+	query := PercolateQuery(PercolatorField, document)
+	query["_source"] = false
+
+	ids := []string{}
+	var after []interface{}
+	for {
+		var response struct {
+			Hits struct {
+				Hits []struct {
+					ID   string        `json:"_id"`
+					Sort []interface{} `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		if err := c.search(ctx, index, searchAfterBody(query, after, defaultScrollSize), &response); err != nil {
+			return nil, err
+		}
+
+		hits := response.Hits.Hits
+		for _, hit := range hits {
+			ids = append(ids, hit.ID)
+		}
+		// The total is capped at 10000 unless tracked, so only a short page ends the matches
+		if len(hits) < defaultScrollSize {
+			return ids, nil
+		}
+		after = hits[len(hits)-1].Sort
+	}
+}
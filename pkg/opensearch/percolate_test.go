@@ -0,0 +1,156 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestPercolateQuery(t *testing.T) {
+	document := map[string]interface{}{"title": "Go in Action"}
+	want := map[string]interface{}{
+		"query": map[string]interface{}{
+			"percolate": map[string]interface{}{
+				"field":    "query",
+				"document": document,
+			},
+		},
+	}
+	if got := PercolateQuery("query", document); !reflect.DeepEqual(got, want) {
+		t.Errorf("PercolateQuery() = %v, want %v", got, want)
+	}
+}
+
+func TestPercolate_Fake(t *testing.T) {
+	client, server := newFakeClient(t)
+	ctx := context.Background()
+
+	if err := client.CreatePercolatorIndex(ctx, "alerts", map[string]interface{}{"title": map[string]interface{}{"type": "text"}}); err != nil {
+		t.Fatalf("CreatePercolatorIndex() error = %v", err)
+	}
+	if err := client.StoreQuery(ctx, "alerts", "golang", MatchQuery("title", "go")); err != nil {
+		t.Fatalf("StoreQuery() error = %v", err)
+	}
+	if err := client.StoreQuery(ctx, "alerts", "rust", TermClause("title", "rust")); err != nil {
+		t.Fatalf("StoreQuery() error = %v", err)
+	}
+
+	stored, _ := server.Document("alerts", "golang")
+	want := map[string]interface{}{
+		"query": map[string]interface{}{"match": map[string]interface{}{"title": "go"}},
+	}
+	if !reflect.DeepEqual(stored, want) {
+		t.Errorf("stored query = %v, want %v", stored, want)
+	}
+
+	ids, err := client.Percolate(ctx, "alerts", map[string]interface{}{"title": "Go in Action"})
+	if err != nil {
+		t.Fatalf("Percolate() error = %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"golang"}) {
+		t.Errorf("Percolate() = %v, want [golang]", ids)
+	}
+
+	ids, err = client.Percolate(ctx, "alerts", map[string]interface{}{"title": "Python"})
+	if err != nil {
+		t.Fatalf("Percolate() error = %v", err)
+	}
+	if ids == nil || len(ids) != 0 {
+		t.Errorf("Percolate() = %v, want no matches", ids)
+	}
+
+	for _, r := range server.Requests() {
+		if strings.Contains(r.Path, "point_in_time") {
+			t.Errorf("Percolate() sent %s %s, want plain searches only", r.Method, r.Path)
+		}
+	}
+}
+
+func TestPercolate_Paging(t *testing.T) {
+	client, server := newFakeClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < defaultScrollSize+100; i++ {
+		server.PutDocument("alerts", fmt.Sprintf("q-%d", i), map[string]interface{}{
+			"query": map[string]interface{}{"match": map[string]interface{}{"title": "go"}},
+		})
+	}
+
+	before := len(server.Requests())
+	ids, err := client.Percolate(ctx, "alerts", map[string]interface{}{"title": "Go in Action"})
+	if err != nil {
+		t.Fatalf("Percolate() error = %v", err)
+	}
+	if len(ids) != defaultScrollSize+100 {
+		t.Errorf("Percolate() returned %d IDs, want %d", len(ids), defaultScrollSize+100)
+	}
+
+	requests := server.Requests()[before:]
+	if len(requests) != 2 || !bytes.Contains(requests[1].Body, []byte("search_after")) {
+		t.Errorf("Percolate() sent %d requests, want a search and a search_after page", len(requests))
+	}
+}
+
+func TestPercolate_CappedTotal(t *testing.T) {
+	// Full pages reporting the capped total of 10000, then a short page past it
+	page := func(from, size int) string {
+		hits := make([]string, size)
+		for i := range hits {
+			hits[i] = fmt.Sprintf(`{"_id":"q-%d","sort":[%d]}`, from+i, from+i)
+		}
+		return `{"hits":{"total":{"value":10000,"relation":"gte"},"hits":[` + strings.Join(hits, ",") + `]}}`
+	}
+	var bodies []string
+	for from := 0; from < 10000; from += defaultScrollSize {
+		bodies = append(bodies, page(from, defaultScrollSize))
+	}
+	bodies = append(bodies, page(10000, 1))
+	stub := &stubTransport{status: 200, bodies: bodies}
+	client := newStubClient(t, stub)
+
+	ids, err := client.Percolate(context.Background(), "alerts", map[string]interface{}{"title": "Go in Action"})
+	if err != nil {
+		t.Fatalf("Percolate() error = %v", err)
+	}
+	if len(ids) != 10001 || stub.calls != len(bodies) {
+		t.Errorf("Percolate() returned %d IDs in %d requests, want 10001 in %d", len(ids), stub.calls, len(bodies))
+	}
+}
+
+func TestPercolate(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-percolate"
+	ctx := context.Background()
+
+	_, _ = client.DeleteIndexIfExists(ctx, indexName)
+	err := client.CreatePercolatorIndex(ctx, indexName, map[string]interface{}{
+		"title": map[string]interface{}{"type": "text"},
+		"price": map[string]interface{}{"type": "float"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePercolatorIndex() error = %v", err)
+	}
+	defer func() { _ = client.DeleteIndex(ctx, indexName) }()
+
+	if err := client.StoreQuery(ctx, indexName, "cheap-go-books", BoolQuery(
+		[]map[string]interface{}{MatchClause("title", "go"), RangeClause("price", nil, 20)}, nil, nil,
+	)); err != nil {
+		t.Fatalf("StoreQuery() error = %v", err)
+	}
+	if err := client.StoreQuery(ctx, indexName, "rust-books", MatchQuery("title", "rust")); err != nil {
+		t.Fatalf("StoreQuery() error = %v", err)
+	}
+
+	ids, err := client.Percolate(ctx, indexName, map[string]interface{}{"title": "Learning Go", "price": 15})
+	if err != nil {
+		t.Fatalf("Percolate() error = %v", err)
+	}
+	sort.Strings(ids)
+	if !reflect.DeepEqual(ids, []string{"cheap-go-books"}) {
+		t.Errorf("Percolate() = %v, want [cheap-go-books]", ids)
+	}
+}
@@ -0,0 +1,135 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// OpenPointInTime opens a point in time (PIT) on the index: a view of its documents as
+// they are now, unaffected by later writes, for consistent deep pagination with
+// SearchPointInTime. The PIT is kept alive for keepAlive; close it with ClosePointInTime.
+func (c *Client) OpenPointInTime(ctx context.Context, index string, keepAlive time.Duration) (_ string, err error) {
+	defer c.observe("OpenPointInTime", time.Now(), &err)
+
+	return c.openPointInTime(ctx, index, keepAlive)
+}
+
+// openPointInTime opens a point in time on index and returns its ID
+func (c *Client) openPointInTime(ctx context.Context, index string, keepAlive time.Duration) (string, error) {
+	params := url.Values{}
+	if keepAlive > 0 {
+		params.Set("keep_alive", timeValue(keepAlive))
+	}
+
+	res, err := c.perform(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_search/point_in_time", params, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to open point in time: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return "", responseError(res, "index not found", ErrIndexNotFound)
+		}
+		return "", requestError("open point in time", res)
+	}
+
+	var response struct {
+		PitID string `json:"pit_id"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return "", err
+	}
+
+	if response.PitID == "" {
+		return "", fmt.Errorf("open point in time response has no pit_id")
+	}
+
+	return response.PitID, nil
+}
+
+// SearchPointInTime returns one batch of documents matching the query within a point in
+// time, plus the search_after cursor of the last hit. Pass nextAfter back as after for the
+// next batch; a nil after starts from the beginning and a nil nextAfter means every match
+// has been returned. Each search extends the PIT by keepAlive.
+//
+// When the query has no sort, hits are sorted by _doc. For indices with more than one
+// shard, supply a sort ending in a unique field so ties cannot be skipped.
+func (c *Client) SearchPointInTime(ctx context.Context, pitID string, query map[string]interface{}, after []interface{}, batchSize int, keepAlive time.Duration) (docs []map[string]interface{}, nextAfter []interface{}, err error) {
+	defer c.observe("SearchPointInTime", time.Now(), &err)
+
+	if batchSize <= 0 {
+		return nil, nil, fmt.Errorf("batch size must be positive")
+	}
+
+	body := searchAfterBody(query, after, batchSize)
+	pit := map[string]interface{}{"id": pitID}
+	if keepAlive > 0 {
+		pit["keep_alive"] = timeValue(keepAlive)
+	}
+	body["pit"] = pit
+
+	var response SearchResponse
+	if err := c.search(ctx, "", body, &response); err != nil {
+		return nil, nil, err
+	}
+
+	hits := response.Hits.Hits
+	docs = hitsToDocuments(hits)
+	if len(hits) == batchSize {
+		nextAfter = hits[len(hits)-1].Sort
+	}
+
+	return docs, nextAfter, nil
+}
+
+// ClosePointInTime releases a point in time opened with OpenPointInTime
+func (c *Client) ClosePointInTime(ctx context.Context, pitID string) (err error) {
+	defer c.observe("ClosePointInTime", time.Now(), &err)
+
+	return c.closePointInTime(ctx, pitID)
+}
+
+// closePointInTime releases the point in time pitID
+func (c *Client) closePointInTime(ctx context.Context, pitID string) error {
+	body, err := json.Marshal(map[string]interface{}{"pit_id": []string{pitID}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal point in time: %w", err)
+	}
+
+	res, err := c.perform(ctx, http.MethodDelete, "/_search/point_in_time", nil, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to close point in time: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, "point in time not found", nil)
+		}
+		return requestError("close point in time", res)
+	}
+
+	var response struct {
+		Pits []struct {
+			PitID      string `json:"pit_id"`
+			Successful bool   `json:"successful"`
+		} `json:"pits"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return err
+	}
+
+	for _, pit := range response.Pits {
+		if pit.PitID == pitID && !pit.Successful {
+			return fmt.Errorf("failed to close point in time %s", pitID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSearchPointInTime_RequestBody(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{"hits":{"hits":[
+		{"_id":"a","_source":{"seq":1},"sort":[1]},
+		{"_id":"b","_source":{"seq":2},"sort":[2]}
+	]}}`}
+	client := newStubClient(t, stub)
+
+	docs, next, err := client.SearchPointInTime(context.Background(), "pit-1", MatchAllQuery(), []interface{}{0}, 2, time.Minute)
+	if err != nil {
+		t.Fatalf("SearchPointInTime() error = %v", err)
+	}
+	if len(docs) != 2 || !reflect.DeepEqual(next, []interface{}{float64(2)}) {
+		t.Errorf("SearchPointInTime() = %d docs, next %v; want 2 docs and next [2]", len(docs), next)
+	}
+
+	// The PIT names the index, so the search is sent without one
+	if stub.path != "/_search" {
+		t.Errorf("Request path = %s, want /_search", stub.path)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(stub.sent, &body); err != nil {
+		t.Fatalf("Failed to decode request body: %v", err)
+	}
+	wantPit := map[string]interface{}{"id": "pit-1", "keep_alive": "60000ms"}
+	if !reflect.DeepEqual(body["pit"], wantPit) {
+		t.Errorf("pit = %v, want %v", body["pit"], wantPit)
+	}
+	if !reflect.DeepEqual(body["search_after"], []interface{}{float64(0)}) || body["size"] != float64(2) {
+		t.Errorf("Request body = %s, want search_after [0] and size 2", prettyPrint(body))
+	}
+}
+
+func TestPointInTime_Failed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("open on a missing index", func(t *testing.T) {
+		stub := &stubTransport{status: 404, body: `{"error":{"type":"index_not_found_exception","reason":"no such index [books]","index":"books"},"status":404}`}
+		client := newStubClient(t, stub)
+
+		_, err := client.OpenPointInTime(ctx, "books", time.Minute)
+		if !errors.Is(err, ErrIndexNotFound) {
+			t.Errorf("OpenPointInTime() error = %v, want ErrIndexNotFound", err)
+		}
+		if stub.method != http.MethodPost || stub.path != "/books/_search/point_in_time" || stub.query != "keep_alive=60000ms" {
+			t.Errorf("request = %s %s?%s, want POST /books/_search/point_in_time?keep_alive=60000ms", stub.method, stub.path, stub.query)
+		}
+	})
+
+	t.Run("close of a missing point in time", func(t *testing.T) {
+		stub := &stubTransport{status: 404, body: `{"error":{"type":"resource_not_found_exception","reason":"pit is not found"},"status":404}`}
+		client := newStubClient(t, stub)
+
+		err := client.ClosePointInTime(ctx, "pit-1")
+		var osErr *OpenSearchError
+		if !errors.As(err, &osErr) || osErr.StatusCode != 404 || osErr.Type != "resource_not_found_exception" {
+			t.Errorf("ClosePointInTime() error = %#v, want a 404 resource_not_found_exception", err)
+		}
+		if stub.method != http.MethodDelete || stub.path != "/_search/point_in_time" || string(stub.sent) != `{"pit_id":["pit-1"]}` {
+			t.Errorf("request = %s %s %s, want DELETE /_search/point_in_time {\"pit_id\":[\"pit-1\"]}", stub.method, stub.path, stub.sent)
+		}
+	})
+
+	t.Run("close not acknowledged", func(t *testing.T) {
+		stub := &stubTransport{status: 200, body: `{"pits":[{"pit_id":"pit-1","successful":false}]}`}
+		client := newStubClient(t, stub)
+
+		if err := client.ClosePointInTime(ctx, "pit-1"); err == nil {
+			t.Error("ClosePointInTime() expected error but got nil")
+		}
+	})
+}
+
+func TestPointInTime_ErrorBody(t *testing.T) {
+	ctx := context.Background()
+	badRequest := `{"error":{"type":"illegal_argument_exception","reason":"keep alive too large","root_cause":[{"type":"illegal_argument_exception","reason":"keep alive too large"}]},"status":400}`
+
+	t.Run("open with an OpenSearch error", func(t *testing.T) {
+		stub := &stubTransport{status: 400, body: badRequest}
+		client := newStubClient(t, stub)
+
+		_, err := client.OpenPointInTime(ctx, "books", time.Minute)
+		var osErr *OpenSearchError
+		if !errors.As(err, &osErr) || osErr.Type != "illegal_argument_exception" || osErr.Reason != "keep alive too large" || len(osErr.RootCause) != 1 {
+			t.Errorf("OpenPointInTime() error = %#v, want the illegal_argument_exception", err)
+		}
+		if !pointInTimeUnsupported(err) {
+			t.Errorf("pointInTimeUnsupported(%v) = false, want true", err)
+		}
+	})
+
+	t.Run("open with a non-JSON body", func(t *testing.T) {
+		stub := &stubTransport{status: 502, body: "Bad Gateway"}
+		client := newStubClient(t, stub)
+
+		_, err := client.OpenPointInTime(ctx, "books", time.Minute)
+		var osErr *OpenSearchError
+		if !errors.As(err, &osErr) || osErr.StatusCode != 502 || osErr.Reason != "Bad Gateway" {
+			t.Errorf("OpenPointInTime() error = %#v, want a 502 OpenSearchError with the body as reason", err)
+		}
+	})
+
+	t.Run("close with an OpenSearch error", func(t *testing.T) {
+		stub := &stubTransport{status: 400, body: badRequest}
+		client := newStubClient(t, stub)
+
+		err := client.ClosePointInTime(ctx, "pit-1")
+		var osErr *OpenSearchError
+		if !errors.As(err, &osErr) || osErr.Type != "illegal_argument_exception" || osErr.Reason != "keep alive too large" {
+			t.Errorf("ClosePointInTime() error = %#v, want the illegal_argument_exception", err)
+		}
+	})
+
+	t.Run("close with a non-JSON body", func(t *testing.T) {
+		stub := &stubTransport{status: 502, body: "Bad Gateway"}
+		client := newStubClient(t, stub)
+
+		err := client.ClosePointInTime(ctx, "pit-1")
+		var osErr *OpenSearchError
+		if !errors.As(err, &osErr) || osErr.StatusCode != 502 || osErr.Reason != "Bad Gateway" {
+			t.Errorf("ClosePointInTime() error = %#v, want a 502 OpenSearchError with the body as reason", err)
+		}
+	})
+}
+
+func TestPointInTime(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-point-in-time"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	const total = 25
+	seedDocuments(t, client, indexName, total)
+
+	pitID, err := client.OpenPointInTime(ctx, indexName, time.Minute)
+	if err != nil {
+		t.Fatalf("OpenPointInTime() error = %v", err)
+	}
+
+	// Documents written after the PIT was opened are not seen through it
+	if err := client.CreateDocument(ctx, indexName, "late", map[string]interface{}{"seq": total}); err != nil {
+		t.Fatalf("CreateDocument() error = %v", err)
+	}
+
+	seen := make(map[string]int)
+	var after []interface{}
+	for {
+		batch, next, err := client.SearchPointInTime(ctx, pitID, MatchAllQuery(), after, 10, time.Minute)
+		if err != nil {
+			t.Fatalf("SearchPointInTime() error = %v", err)
+		}
+		for _, doc := range batch {
+			seen[doc["_id"].(string)]++
+		}
+		if next == nil {
+			break
+		}
+		after = next
+	}
+
+	if len(seen) != total || seen["late"] != 0 {
+		t.Errorf("Paged through %d distinct documents (late: %d), want %d without the late one", len(seen), seen["late"], total)
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("Document %s returned %d times, want once", id, count)
+		}
+	}
+
+	if err := client.ClosePointInTime(ctx, pitID); err != nil {
+		t.Fatalf("ClosePointInTime() error = %v", err)
+	}
+	if _, _, err := client.SearchPointInTime(ctx, pitID, MatchAllQuery(), nil, 10, 0); err == nil {
+		t.Error("SearchPointInTime() after close expected error but got nil")
+	}
+}
@@ -0,0 +1,239 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchtransport"
+)
+
+const (
+	// defaultDeadNodeBackoffBase and defaultDeadNodeBackoffMax bound how long a failed node is
+	// left out of rotation by default, the same as the opensearch transport's own pool
+	defaultDeadNodeBackoffBase = 60 * time.Second
+	defaultDeadNodeBackoffMax  = 32 * time.Minute
+)
+
+// PoolNode describes a node in the client's connection pool
+type PoolNode struct {
+	URL   string
+	ID    string // set for discovered nodes
+	Name  string // set for discovered nodes
+	Roles []string
+	// Dead is true while the node is left out of rotation after a failed request
+	Dead      bool
+	DeadSince time.Time
+	// Failures counts consecutive failed requests to the node
+	Failures int
+}
+
+// ClusterNodes returns the nodes in the client's connection pool, live nodes first. With node
+// discovery enabled this reflects the nodes found by the last discovery.
+func (c *Client) ClusterNodes(ctx context.Context) (_ []PoolNode, err error) {
+	defer c.observe("ClusterNodes", time.Now(), &err)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	pool := c.pools.current()
+	if pool == nil {
+		return nil, errors.New("connection pool is not available")
+	}
+
+	return pool.nodes(), nil
+}
+
+// DiscoverNodes replaces the connection pool with the HTTP nodes reported by the cluster,
+// skipping dedicated cluster manager nodes
+func (c *Client) DiscoverNodes() (err error) {
+	defer c.observe("DiscoverNodes", time.Now(), &err)
+
+	return c.client.DiscoverNodes()
+}
+
+// poolRef holds the pool currently used by the opensearch transport, which replaces
+// its pool every time nodes are discovered
+type poolRef struct {
+	mu      sync.Mutex
+	pool    *nodePool
+	backoff func(failures int) time.Duration
+}
+
+// newPool is the opensearch transport's ConnectionPoolFunc
+func (r *poolRef) newPool(conns []*opensearchtransport.Connection, _ opensearchtransport.Selector) opensearchtransport.ConnectionPool {
+	pool := &nodePool{live: conns, next: -1, backoff: r.backoff}
+
+	r.mu.Lock()
+	r.pool = pool
+	r.mu.Unlock()
+
+	return pool
+}
+
+// current returns the pool in use
+func (r *poolRef) current() *nodePool {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pool
+}
+
+// nodePool is a round-robin connection pool. A node whose request fails is taken out of
+// rotation for backoff(failures) before it is tried again; a successful request to it
+// restores it straight away.
+type nodePool struct {
+	mu      sync.Mutex
+	live    []*opensearchtransport.Connection
+	dead    []*opensearchtransport.Connection
+	next    int
+	backoff func(failures int) time.Duration
+}
+
+// Next returns the next live connection, or the dead connection with the fewest failures
+// when none are live
+func (p *nodePool) Next() (*opensearchtransport.Connection, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.live) > 0 {
+		p.next = (p.next + 1) % len(p.live)
+		return p.live[p.next], nil
+	}
+	if len(p.dead) > 0 {
+		conn := p.dead[len(p.dead)-1]
+		conn.Lock()
+		p.resurrect(conn)
+		conn.Unlock()
+		return conn, nil
+	}
+
+	return nil, errors.New("no connection available")
+}
+
+// OnSuccess returns a dead connection to rotation and resets its failures
+func (p *nodePool) OnSuccess(conn *opensearchtransport.Connection) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conn.Lock()
+	defer conn.Unlock()
+
+	conn.Failures = 0
+	conn.DeadSince = time.Time{}
+	if conn.IsDead {
+		p.resurrect(conn)
+	}
+
+	return nil
+}
+
+// OnFailure takes the connection out of rotation and schedules it to be tried again
+func (p *nodePool) OnFailure(conn *opensearchtransport.Connection) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	conn.Lock()
+	defer conn.Unlock()
+
+	conn.Failures++
+	if conn.IsDead {
+		return nil
+	}
+
+	conn.IsDead = true
+	conn.DeadSince = time.Now().UTC()
+	p.live = removeConnection(p.live, conn)
+	p.dead = append(p.dead, conn)
+	// Keep the connection with the fewest failures last, so Next tries it first
+	sort.SliceStable(p.dead, func(i, j int) bool {
+		return p.dead[i].Failures > p.dead[j].Failures
+	})
+
+	failures := conn.Failures
+	time.AfterFunc(p.backoff(failures), func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		conn.Lock()
+		defer conn.Unlock()
+
+		if conn.IsDead {
+			p.resurrect(conn)
+		}
+	})
+
+	return nil
+}
+
+// URLs returns the URLs of the live connections
+func (p *nodePool) URLs() []*url.URL {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	urls := make([]*url.URL, 0, len(p.live))
+	for _, conn := range p.live {
+		urls = append(urls, conn.URL)
+	}
+	return urls
+}
+
+// nodes describes every connection in the pool, live ones first
+func (p *nodePool) nodes() []PoolNode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	nodes := make([]PoolNode, 0, len(p.live)+len(p.dead))
+	for _, conns := range [][]*opensearchtransport.Connection{p.live, p.dead} {
+		for _, conn := range conns {
+			conn.Lock()
+			nodes = append(nodes, PoolNode{
+				URL:       conn.URL.String(),
+				ID:        conn.ID,
+				Name:      conn.Name,
+				Roles:     conn.Roles,
+				Dead:      conn.IsDead,
+				DeadSince: conn.DeadSince,
+				Failures:  conn.Failures,
+			})
+			conn.Unlock()
+		}
+	}
+	return nodes
+}
+
+// resurrect moves a dead connection back into rotation, keeping its failure count so a
+// node that keeps failing is left out for longer. p.mu and conn must be locked.
+func (p *nodePool) resurrect(conn *opensearchtransport.Connection) {
+	conn.IsDead = false
+	p.dead = removeConnection(p.dead, conn)
+	p.live = append(p.live, conn)
+}
+
+// removeConnection returns a copy of conns without conn, leaving the slice passed in by
+// the opensearch transport untouched
+func removeConnection(conns []*opensearchtransport.Connection, conn *opensearchtransport.Connection) []*opensearchtransport.Connection {
+	kept := make([]*opensearchtransport.Connection, 0, len(conns))
+	for _, c := range conns {
+		if c != conn {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// defaultDeadNodeBackoff leaves a node out for 60s after its first failure, doubling with
+// each further failure up to 32 minutes
+func defaultDeadNodeBackoff(failures int) time.Duration {
+	backoff := defaultDeadNodeBackoffBase
+	for i := 1; i < failures && backoff < defaultDeadNodeBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > defaultDeadNodeBackoffMax {
+		backoff = defaultDeadNodeBackoffMax
+	}
+	return backoff
+}
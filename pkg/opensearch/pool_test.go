@@ -0,0 +1,210 @@
+package opensearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchtransport"
+)
+
+// newTestPool creates a pool of connections to the given hosts
+func newTestPool(backoff time.Duration, hosts ...string) (*nodePool, []*opensearchtransport.Connection) {
+	conns := make([]*opensearchtransport.Connection, 0, len(hosts))
+	for _, host := range hosts {
+		conns = append(conns, &opensearchtransport.Connection{URL: &url.URL{Scheme: "http", Host: host}})
+	}
+
+	ref := &poolRef{backoff: func(int) time.Duration { return backoff }}
+	ref.newPool(conns, nil)
+	return ref.current(), conns
+}
+
+// nextHosts returns the hosts of the next n connections from the pool
+func nextHosts(t *testing.T, pool *nodePool, n int) []string {
+	t.Helper()
+
+	hosts := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		conn, err := pool.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		hosts = append(hosts, conn.URL.Host)
+	}
+	return hosts
+}
+
+func TestNodePool_RoundRobin(t *testing.T) {
+	pool, _ := newTestPool(time.Minute, "a:9200", "b:9200", "c:9200")
+
+	got := strings.Join(nextHosts(t, pool, 4), ",")
+	if want := "a:9200,b:9200,c:9200,a:9200"; got != want {
+		t.Errorf("Next() order = %s, want %s", got, want)
+	}
+}
+
+func TestNodePool_DeadNode(t *testing.T) {
+	pool, conns := newTestPool(50*time.Millisecond, "a:9200", "b:9200", "c:9200")
+
+	if err := pool.OnFailure(conns[1]); err != nil {
+		t.Fatalf("OnFailure() error = %v", err)
+	}
+
+	for _, host := range nextHosts(t, pool, 4) {
+		if host == "b:9200" {
+			t.Fatal("Next() returned the dead node")
+		}
+	}
+
+	nodes := pool.nodes()
+	dead := nodes[len(nodes)-1]
+	if dead.URL != "http://b:9200" || !dead.Dead || dead.Failures != 1 || dead.DeadSince.IsZero() {
+		t.Errorf("nodes() last entry = %+v, want b:9200 dead after 1 failure", dead)
+	}
+
+	// The node returns to rotation once its backoff has passed
+	deadline := time.Now().Add(time.Second)
+	for len(pool.URLs()) != 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("Dead node was not returned to rotation after its backoff")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if conns[1].IsDead {
+		t.Error("Resurrected node is still marked dead")
+	}
+}
+
+func TestNodePool_OnSuccessRestoresNode(t *testing.T) {
+	pool, conns := newTestPool(time.Minute, "a:9200", "b:9200")
+
+	_ = pool.OnFailure(conns[0])
+	_ = pool.OnFailure(conns[0])
+	if conns[0].Failures != 2 {
+		t.Errorf("Failures = %d, want 2", conns[0].Failures)
+	}
+
+	_ = pool.OnSuccess(conns[0])
+	if conns[0].IsDead || conns[0].Failures != 0 {
+		t.Errorf("After OnSuccess: dead = %t, failures = %d, want live with 0 failures", conns[0].IsDead, conns[0].Failures)
+	}
+	if got := len(pool.URLs()); got != 2 {
+		t.Errorf("URLs() has %d live nodes, want 2", got)
+	}
+}
+
+func TestNodePool_AllDead(t *testing.T) {
+	pool, conns := newTestPool(time.Minute, "a:9200")
+
+	_ = pool.OnFailure(conns[0])
+
+	// With no live node left, the dead one is tried rather than failing outright
+	conn, err := pool.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if conn != conns[0] || conn.IsDead {
+		t.Errorf("Next() = %+v, want the resurrected node", conn)
+	}
+}
+
+func TestDefaultDeadNodeBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: 60 * time.Second},
+		{failures: 2, want: 2 * time.Minute},
+		{failures: 4, want: 8 * time.Minute},
+		{failures: 6, want: 32 * time.Minute},
+		{failures: 20, want: 32 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := defaultDeadNodeBackoff(tt.failures); got != tt.want {
+			t.Errorf("defaultDeadNodeBackoff(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+// nodesInfoStub answers the nodes info request made by node discovery
+type nodesInfoStub struct{}
+
+func (nodesInfoStub) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := `{}`
+	if req.URL.Path == "/_nodes/http" {
+		body = `{"nodes":{
+			"n1":{"name":"data-1","roles":["data","ingest"],"http":{"publish_address":"10.0.0.1:9200"}},
+			"n2":{"name":"data-2","roles":["data","ingest"],"http":{"publish_address":"data-2.internal/10.0.0.2:9200"}},
+			"n3":{"name":"manager-1","roles":["cluster_manager"],"http":{"publish_address":"10.0.0.3:9200"}}
+		}}`
+	}
+
+	return &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// poolURLs returns the sorted URLs of the client's pool
+func poolURLs(t *testing.T, client *Client) []string {
+	t.Helper()
+
+	nodes, err := client.ClusterNodes(context.Background())
+	if err != nil {
+		t.Fatalf("ClusterNodes() error = %v", err)
+	}
+
+	urls := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		urls = append(urls, node.URL)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+func TestClusterNodes_Discovery(t *testing.T) {
+	client, err := newClient(Config{Addresses: []string{"http://seed:9200"}}, nodesInfoStub{})
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	if got := strings.Join(poolURLs(t, client), ","); got != "http://seed:9200" {
+		t.Errorf("ClusterNodes() before discovery = %s, want the configured address", got)
+	}
+
+	if err := client.DiscoverNodes(); err != nil {
+		t.Fatalf("DiscoverNodes() error = %v", err)
+	}
+
+	// The dedicated cluster manager node is not used for requests
+	if got, want := strings.Join(poolURLs(t, client), ","), "http://10.0.0.1:9200,http://data-2.internal:9200"; got != want {
+		t.Errorf("ClusterNodes() after discovery = %s, want %s", got, want)
+	}
+}
+
+func TestClusterNodes_DiscoverOnStart(t *testing.T) {
+	client, err := newClient(Config{
+		Addresses:            []string{"http://seed:9200"},
+		DiscoverNodesOnStart: true,
+	}, nodesInfoStub{})
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(poolURLs(t, client)) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("ClusterNodes() = %v, want the 2 discovered data nodes", poolURLs(t, client))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
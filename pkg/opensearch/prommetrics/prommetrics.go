@@ -0,0 +1,87 @@
+// Package prommetrics exports the request metrics of an opensearch.Client to Prometheus.
+// It is a separate module so that the client itself does not depend on the Prometheus
+// client library.
+package prommetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yenonn/go-opensearch/pkg/opensearch"
+)
+
+const (
+	namespace = "opensearch"
+	subsystem = "client"
+)
+
+// Collector is an opensearch.MetricsCollector and a prometheus.Collector. Register it with
+// a prometheus.Registerer and set it as Config.Metrics to export:
+//
+//   - opensearch_client_requests_total{op, index, status}: requests by status class ("2xx", "5xx", "error")
+//   - opensearch_client_request_duration_seconds{op, index}: request latency
+//   - opensearch_client_bulk_documents and opensearch_client_bulk_bytes: bulk request sizes
+type Collector struct {
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	bulkDocuments prometheus.Histogram
+	bulkBytes     prometheus.Histogram
+}
+
+// NewCollector creates a Collector
+func NewCollector() *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "requests_total",
+			Help:      "Requests sent to OpenSearch by operation, index and status class.",
+		}, []string{"op", "index", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of requests sent to OpenSearch by operation and index.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"op", "index"}),
+		bulkDocuments: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "bulk_documents",
+			Help:      "Number of documents in each bulk request.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		}),
+		bulkBytes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "bulk_bytes",
+			Help:      "Size in bytes of each bulk request body.",
+			Buckets:   prometheus.ExponentialBuckets(1024, 4, 8),
+		}),
+	}
+}
+
+// ObserveRequest records a request sent by the client
+func (c *Collector) ObserveRequest(metrics opensearch.RequestMetrics) {
+	c.requests.WithLabelValues(metrics.Operation, metrics.Index, metrics.StatusClass).Inc()
+	c.duration.WithLabelValues(metrics.Operation, metrics.Index).Observe(metrics.Duration.Seconds())
+
+	if metrics.Operation == opensearch.OperationBulk {
+		c.bulkDocuments.Observe(float64(metrics.BulkDocuments))
+		c.bulkBytes.Observe(float64(metrics.RequestBytes))
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.bulkDocuments.Describe(ch)
+	c.bulkBytes.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.bulkDocuments.Collect(ch)
+	c.bulkBytes.Collect(ch)
+}
@@ -0,0 +1,104 @@
+package prommetrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yenonn/go-opensearch/pkg/opensearch"
+)
+
+// newTestClient creates a client for a stub cluster that answers every request with 200
+func newTestClient(t *testing.T, collector *Collector) *opensearch.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"took":1,"errors":false,"items":[],"hits":{"hits":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := opensearch.NewClient(opensearch.Config{
+		Addresses: []string{server.URL},
+		Metrics:   collector,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestCollector_Requests(t *testing.T) {
+	collector := NewCollector()
+	client := newTestClient(t, collector)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.SearchDocuments(ctx, "my-index", opensearch.MatchAllQuery()); err != nil {
+			t.Fatalf("SearchDocuments() error = %v", err)
+		}
+	}
+
+	if got := testutil.ToFloat64(collector.requests.WithLabelValues("search", "my-index", "2xx")); got != 2 {
+		t.Errorf("requests_total{op=search,index=my-index,status=2xx} = %v, want 2", got)
+	}
+	if got := testutil.CollectAndCount(collector.duration); got != 1 {
+		t.Errorf("request_duration_seconds has %d series, want 1", got)
+	}
+}
+
+func TestCollector_BulkSizes(t *testing.T) {
+	collector := NewCollector()
+	client := newTestClient(t, collector)
+
+	docs := []map[string]interface{}{{"n": 1}, {"n": 2}, {"n": 3}}
+	if err := client.BulkCreate(context.Background(), "my-index", docs); err != nil {
+		t.Fatalf("BulkCreate() error = %v", err)
+	}
+
+	if got := testutil.ToFloat64(collector.requests.WithLabelValues("bulk", "", "2xx")); got != 1 {
+		t.Errorf("requests_total{op=bulk,status=2xx} = %v, want 1", got)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "opensearch_client_bulk_documents" {
+			continue
+		}
+		histogram := family.GetMetric()[0].GetHistogram()
+		if histogram.GetSampleCount() != 1 || histogram.GetSampleSum() != 3 {
+			t.Errorf("bulk_documents count = %d, sum = %v, want one bulk of 3", histogram.GetSampleCount(), histogram.GetSampleSum())
+		}
+		return
+	}
+	t.Error("opensearch_client_bulk_documents was not exported")
+}
+
+func TestCollector_MetricsEndpoint(t *testing.T) {
+	collector := NewCollector()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	client := newTestClient(t, collector)
+
+	if _, err := client.SearchDocuments(context.Background(), "my-index", opensearch.MatchAllQuery()); err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	want := `opensearch_client_requests_total{index="my-index",op="search",status="2xx"} 1`
+	if body := recorder.Body.String(); !strings.Contains(body, want) {
+		t.Errorf("/metrics does not contain %s:\n%s", want, body)
+	}
+}
@@ -0,0 +1,175 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// RemoteSource describes the cluster a remote reindex pulls documents from.
+// The remote host must be listed in the destination cluster's reindex.remote.whitelist.
+type RemoteSource struct {
+	// Host is the remote cluster's URL including scheme and port, e.g. "https://old-es:9200"
+	Host     string
+	Username string
+	Password string
+	// SocketTimeout and ConnectTimeout override the server defaults (30s and 1s) when non-zero
+	SocketTimeout  time.Duration
+	ConnectTimeout time.Duration
+}
+
+// ReindexResult summarises a completed reindex
+type ReindexResult struct {
+	Took             int                      `json:"took"`
+	TimedOut         bool                     `json:"timed_out"`
+	Total            int64                    `json:"total"`
+	Created          int64                    `json:"created"`
+	Updated          int64                    `json:"updated"`
+	Deleted          int64                    `json:"deleted"`
+	Batches          int64                    `json:"batches"`
+	VersionConflicts int64                    `json:"version_conflicts"`
+	Failures         []map[string]interface{} `json:"failures"`
+}
+
+// Reindex copies the documents of sourceIndex matching query (all documents when nil)
+// into destIndex and waits for the copy to finish
+func (c *Client) Reindex(ctx context.Context, sourceIndex, destIndex string, query map[string]interface{}) (_ *ReindexResult, err error) {
+	defer c.observe("Reindex", time.Now(), &err)
+
+	body := reindexBody(nil, sourceIndex, destIndex, query)
+
+	res, err := c.reindex(ctx, body, true)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var result ReindexResult
+	if err := c.parseResponse(res.Body, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Failures) > 0 {
+		return &result, fmt.Errorf("reindex %s into %s: %d failures, first: %v", sourceIndex, destIndex, len(result.Failures), result.Failures[0])
+	}
+
+	return &result, nil
+}
+
+// ReindexAsync starts copying the documents of sourceIndex matching query (all documents
+// when nil) into destIndex without waiting, and returns the ID of the task running it.
+// Follow it with GetTask and stop it with CancelTask.
+func (c *Client) ReindexAsync(ctx context.Context, sourceIndex, destIndex string, query map[string]interface{}) (_ string, err error) {
+	defer c.observe("ReindexAsync", time.Now(), &err)
+
+	body := reindexBody(nil, sourceIndex, destIndex, query)
+
+	res, err := c.reindex(ctx, body, false)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var response TaskResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return "", err
+	}
+
+	return response.Task, nil
+}
+
+// ReindexFromRemote starts copying the documents of sourceIndex on a remote cluster
+// matching query (all documents when nil) into destIndex on this cluster. Remote
+// reindexes are typically long-running, so the copy runs as a server-side task whose
+// ID is returned; follow it with GetTask.
+func (c *Client) ReindexFromRemote(ctx context.Context, remote RemoteSource, sourceIndex, destIndex string, query map[string]interface{}) (_ string, err error) {
+	defer c.observe("ReindexFromRemote", time.Now(), &err)
+
+	if remote.Host == "" {
+		return "", fmt.Errorf("remote host is required")
+	}
+
+	body := reindexBody(&remote, sourceIndex, destIndex, query)
+
+	res, err := c.reindex(ctx, body, false)
+	if err != nil {
+		return "", fmt.Errorf("reindex from %s: %w", remote.Host, err)
+	}
+	defer res.Body.Close()
+
+	var response TaskResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return "", err
+	}
+
+	return response.Task, nil
+}
+
+// reindex executes a reindex request. The caller closes the response body.
+func (c *Client) reindex(ctx context.Context, body map[string]interface{}, waitForCompletion bool) (*opensearchapi.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal reindex request: %w", err)
+	}
+
+	req := opensearchapi.ReindexRequest{
+		Body:              bytes.NewReader(data),
+		WaitForCompletion: &waitForCompletion,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reindex: %w", err)
+	}
+
+	if res.IsError() {
+		defer res.Body.Close()
+		return nil, requestError("reindex", res)
+	}
+
+	return res, nil
+}
+
+// reindexBody builds the body of a reindex request, with a remote source when remote is set
+func reindexBody(remote *RemoteSource, sourceIndex, destIndex string, query map[string]interface{}) map[string]interface{} {
+	source := map[string]interface{}{
+		"index": sourceIndex,
+	}
+	if query != nil {
+		source["query"] = unwrapQuery(query)
+	}
+
+	if remote != nil {
+		r := map[string]interface{}{
+			"host": remote.Host,
+		}
+		if remote.Username != "" {
+			r["username"] = remote.Username
+			r["password"] = remote.Password
+		}
+		if remote.SocketTimeout > 0 {
+			r["socket_timeout"] = timeValue(remote.SocketTimeout)
+		}
+		if remote.ConnectTimeout > 0 {
+			r["connect_timeout"] = timeValue(remote.ConnectTimeout)
+		}
+		source["remote"] = r
+	}
+
+	return map[string]interface{}{
+		"source": source,
+		"dest": map[string]interface{}{
+			"index": destIndex,
+		},
+	}
+}
+
+// timeValue formats a duration as an OpenSearch time value
+func timeValue(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
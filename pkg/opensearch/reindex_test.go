@@ -0,0 +1,216 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	opensearch "github.com/opensearch-project/opensearch-go/v2"
+)
+
+// stubTransport answers every request with a canned response and records the last request body
+type stubTransport struct {
+	status int
+	body   string
+	// statuses, when set, gives the status of each call in turn, falling back to status
+	statuses []int
+	// bodies, when set, gives the body of each call in turn, falling back to body
+	bodies []string
+	// onRequest, when set, runs before each response is returned
+	onRequest func()
+	calls     int
+
+	method string
+	path   string
+	query  string
+	header http.Header
+	ctx    context.Context
+	sent   []byte
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.calls++
+	if s.onRequest != nil {
+		s.onRequest()
+	}
+	s.method = req.Method
+	s.path = req.URL.Path
+	s.query = req.URL.RawQuery
+	s.header = req.Header
+	s.ctx = req.Context()
+	if req.Body != nil {
+		s.sent, _ = io.ReadAll(req.Body)
+	}
+
+	status := s.status
+	if s.calls <= len(s.statuses) {
+		status = s.statuses[s.calls-1]
+	}
+	body := s.body
+	if s.calls <= len(s.bodies) {
+		body = s.bodies[s.calls-1]
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// newStubClient creates a client whose requests are answered by the stub transport
+func newStubClient(t *testing.T, stub *stubTransport) *Client {
+	t.Helper()
+
+	client, err := opensearch.NewClient(opensearch.Config{
+		Addresses: []string{"http://stub:9200"},
+		Transport: stub,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create stub client: %v", err)
+	}
+
+	return &Client{client: client}
+}
+
+func TestReindexFromRemote_RequestBody(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{"task":"node-1:42"}`}
+	client := newStubClient(t, stub)
+
+	remote := RemoteSource{
+		Host:           "https://old-es:9200",
+		Username:       "migrator",
+		Password:       "secret",
+		SocketTimeout:  time.Minute,
+		ConnectTimeout: 10 * time.Second,
+	}
+	taskID, err := client.ReindexFromRemote(context.Background(), remote, "logs", "logs-migrated", MatchQuery("level", "error"))
+	if err != nil {
+		t.Fatalf("ReindexFromRemote() error = %v", err)
+	}
+	if taskID != "node-1:42" {
+		t.Errorf("ReindexFromRemote() task = %q, want node-1:42", taskID)
+	}
+
+	if stub.method != http.MethodPost || stub.path != "/_reindex" {
+		t.Errorf("Request = %s %s, want POST /_reindex", stub.method, stub.path)
+	}
+	if !strings.Contains(stub.query, "wait_for_completion=false") {
+		t.Errorf("Query string = %q, want wait_for_completion=false", stub.query)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(stub.sent, &body); err != nil {
+		t.Fatalf("Request body is not JSON: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"source": map[string]interface{}{
+			"index": "logs",
+			"query": map[string]interface{}{
+				"match": map[string]interface{}{"level": "error"},
+			},
+			"remote": map[string]interface{}{
+				"host":            "https://old-es:9200",
+				"username":        "migrator",
+				"password":        "secret",
+				"socket_timeout":  "60000ms",
+				"connect_timeout": "10000ms",
+			},
+		},
+		"dest": map[string]interface{}{
+			"index": "logs-migrated",
+		},
+	}
+	if prettyPrint(body) != prettyPrint(want) {
+		t.Errorf("Request body = %s, want %s", prettyPrint(body), prettyPrint(want))
+	}
+}
+
+func TestReindexFromRemote_ServerReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{
+			name:   "Host not whitelisted",
+			status: 400,
+			body:   `{"error":{"type":"illegal_argument_exception","reason":"[old-es:9200] not whitelisted in reindex.remote.whitelist"},"status":400}`,
+			want:   "not whitelisted in reindex.remote.whitelist",
+		},
+		{
+			name:   "Remote authentication failure",
+			status: 401,
+			body:   `{"error":{"type":"status_exception","reason":"body={\"error\":\"unauthorized\"}"},"status":401}`,
+			want:   "unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newStubClient(t, &stubTransport{status: tt.status, body: tt.body})
+
+			_, err := client.ReindexFromRemote(context.Background(), RemoteSource{Host: "https://old-es:9200"}, "logs", "logs-migrated", nil)
+			if err == nil {
+				t.Fatal("ReindexFromRemote() expected error but got nil")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReindexFromRemote() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("Missing host", func(t *testing.T) {
+		client := newStubClient(t, &stubTransport{status: 200, body: `{}`})
+		if _, err := client.ReindexFromRemote(context.Background(), RemoteSource{}, "logs", "logs-migrated", nil); err == nil {
+			t.Error("ReindexFromRemote() without host expected error but got nil")
+		}
+	})
+}
+
+func TestGetTask_Stub(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{
+		"completed": true,
+		"task": {"node": "node-1", "id": 42, "action": "indices:data/write/reindex", "status": {"total": 10, "created": 10, "batches": 1}},
+		"response": {"total": 10, "created": 10}
+	}`}
+	client := newStubClient(t, stub)
+
+	status, err := client.GetTask(context.Background(), "node-1:42")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if stub.path != "/_tasks/node-1:42" {
+		t.Errorf("Request path = %s, want /_tasks/node-1:42", stub.path)
+	}
+	if !status.Completed || status.ID != "node-1:42" || status.Status.Created != 10 {
+		t.Errorf("GetTask() = %+v, want completed node-1:42 with 10 created", status)
+	}
+}
+
+func TestReindex(t *testing.T) {
+	client := setupTestClient(t)
+	source := "test-reindex-source"
+	dest := "test-reindex-dest"
+	cleanup := setupTestIndex(t, client, source)
+	defer cleanup()
+	defer func() { _ = client.DeleteIndex(context.Background(), dest) }()
+
+	ctx := context.Background()
+	seedDocuments(t, client, source, 10)
+
+	result, err := client.Reindex(ctx, source, dest, RangeQuery("seq", nil, 3))
+	if err != nil {
+		t.Fatalf("Reindex() error = %v", err)
+	}
+	if result.Created != 4 {
+		t.Errorf("Reindex() created = %d, want 4", result.Created)
+	}
+}
@@ -0,0 +1,232 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+const (
+	// defaultScrollSize is the batch size used by SearchEach
+	defaultScrollSize = 500
+	// defaultScrollKeepAlive is how long the server keeps a scroll context alive between batches
+	defaultScrollKeepAlive = time.Minute
+)
+
+// ScrollCursor iterates over all documents matching a query in batches using the scroll API.
+// Callers must Close the cursor to release the server-side scroll context, unless it was
+// opened with CloseOnExhaust and iterated until Next returned io.EOF. ScrollCursor
+// implements io.Closer and closing it more than once is a no-op.
+type ScrollCursor struct {
+	client         *Client
+	scrollID       string
+	keepAlive      time.Duration
+	closeOnExhaust bool
+	first          []map[string]interface{}
+	done           bool
+}
+
+// ScrollOption configures a ScrollCursor
+type ScrollOption func(*ScrollCursor)
+
+// CloseOnExhaust makes Next clear the server-side scroll context as soon as it reports
+// io.EOF, so a loop that reads the cursor to the end does not leak it without a Close
+func CloseOnExhaust() ScrollOption {
+	return func(s *ScrollCursor) {
+		s.closeOnExhaust = true
+	}
+}
+
+// OpenScroll starts a scroll over the documents matching query, fetching batchSize
+// documents per batch and keeping the scroll context alive for keepAlive between batches
+func (c *Client) OpenScroll(ctx context.Context, index string, query map[string]interface{}, batchSize int, keepAlive time.Duration, opts ...ScrollOption) (_ *ScrollCursor, err error) {
+	defer c.observe("OpenScroll", time.Now(), &err)
+
+	return c.openScroll(ctx, index, query, batchSize, keepAlive, opts...)
+}
+
+// openScroll runs the first search of a scroll and returns a cursor over its results
+func (c *Client) openScroll(ctx context.Context, index string, query map[string]interface{}, batchSize int, keepAlive time.Duration, opts ...ScrollOption) (*ScrollCursor, error) {
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive")
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	req := opensearchapi.SearchRequest{
+		Index:  []string{index},
+		Body:   bytes.NewReader(body),
+		Size:   &batchSize,
+		Scroll: keepAlive,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open scroll: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("scroll search", res)
+	}
+
+	var response SearchResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	cursor := &ScrollCursor{
+		client:    c,
+		scrollID:  response.ScrollID,
+		keepAlive: keepAlive,
+		first:     hitsToDocuments(response.Hits.Hits),
+	}
+	for _, opt := range opts {
+		opt(cursor)
+	}
+
+	return cursor, nil
+}
+
+// Next returns the next batch of documents, or io.EOF once all documents have been returned.
+// With CloseOnExhaust, the scroll context is cleared before io.EOF is returned.
+func (s *ScrollCursor) Next(ctx context.Context) ([]map[string]interface{}, error) {
+	if s.first != nil {
+		docs := s.first
+		s.first = nil
+		if len(docs) > 0 {
+			return docs, nil
+		}
+		s.done = true
+	}
+	if s.done {
+		return nil, s.exhausted(ctx)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"scroll":    s.keepAlive.String(),
+		"scroll_id": s.scrollID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scroll request: %w", err)
+	}
+
+	req := opensearchapi.ScrollRequest{
+		Body: bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, s.client.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to continue scroll: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("scroll", res)
+	}
+
+	var response SearchResponse
+	if err := s.client.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	if response.ScrollID != "" {
+		s.scrollID = response.ScrollID
+	}
+	if len(response.Hits.Hits) == 0 {
+		s.done = true
+		return nil, s.exhausted(ctx)
+	}
+
+	return hitsToDocuments(response.Hits.Hits), nil
+}
+
+// exhausted returns io.EOF, clearing the scroll first when the cursor closes on exhaustion
+func (s *ScrollCursor) exhausted(ctx context.Context) error {
+	if s.closeOnExhaust {
+		if err := s.CloseContext(ctx); err != nil {
+			return err
+		}
+	}
+	return io.EOF
+}
+
+// Close clears the server-side scroll context. It is a no-op once the scroll has been cleared.
+func (s *ScrollCursor) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// CloseContext clears the server-side scroll context using ctx for the request
+func (s *ScrollCursor) CloseContext(ctx context.Context) error {
+	if s.scrollID == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"scroll_id": []string{s.scrollID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal clear scroll request: %w", err)
+	}
+
+	req := opensearchapi.ClearScrollRequest{
+		Body: bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, s.client.client)
+	if err != nil {
+		return fmt.Errorf("failed to clear scroll: %w", err)
+	}
+	defer res.Body.Close()
+
+	s.scrollID = ""
+	s.done = true
+
+	// A scroll that already expired is reported as not found, which is fine
+	if res.IsError() && res.StatusCode != 404 {
+		return requestError("clear scroll", res)
+	}
+
+	return nil
+}
+
+// SearchEach scrolls through every document matching the query and calls fn for each
+// one without buffering the full result set. Iteration stops at the first error
+// returned by fn, which is returned to the caller.
+func (c *Client) SearchEach(ctx context.Context, index string, query map[string]interface{}, fn func(doc map[string]interface{}) error) (err error) {
+	defer c.observe("SearchEach", time.Now(), &err)
+
+	cursor, err := c.openScroll(ctx, index, query, defaultScrollSize, defaultScrollKeepAlive, CloseOnExhaust())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := cursor.CloseContext(ctx); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for {
+		docs, err := cursor.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, doc := range docs {
+			if err := fn(doc); err != nil {
+				return err
+			}
+		}
+	}
+}
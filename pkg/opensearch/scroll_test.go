@@ -0,0 +1,133 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSearchEach(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-search-each"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	const total = 1200
+	seedDocuments(t, client, indexName, total)
+
+	t.Run("Callback sees every document", func(t *testing.T) {
+		count := 0
+		seen := make(map[string]bool)
+		err := client.SearchEach(ctx, indexName, MatchAllQuery(), func(doc map[string]interface{}) error {
+			count++
+			seen[doc["_id"].(string)] = true
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("SearchEach() error = %v", err)
+		}
+		if count != total || len(seen) != total {
+			t.Errorf("SearchEach() visited %d documents (%d distinct), want %d", count, len(seen), total)
+		}
+	})
+
+	t.Run("Callback error stops iteration", func(t *testing.T) {
+		errStop := errors.New("stop")
+		count := 0
+		err := client.SearchEach(ctx, indexName, MatchAllQuery(), func(doc map[string]interface{}) error {
+			count++
+			if count == 10 {
+				return errStop
+			}
+			return nil
+		})
+		if !errors.Is(err, errStop) {
+			t.Errorf("SearchEach() error = %v, want %v", err, errStop)
+		}
+		if count != 10 {
+			t.Errorf("SearchEach() visited %d documents after stop, want 10", count)
+		}
+	})
+}
+
+func TestScrollCursor(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-scroll-cursor"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	seedDocuments(t, client, indexName, 25)
+
+	cursor, err := client.OpenScroll(ctx, indexName, MatchAllQuery(), 10, defaultScrollKeepAlive)
+	if err != nil {
+		t.Fatalf("OpenScroll() error = %v", err)
+	}
+	defer cursor.Close()
+
+	var batches []int
+	for {
+		docs, err := cursor.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		batches = append(batches, len(docs))
+	}
+
+	if len(batches) != 3 || batches[0] != 10 || batches[1] != 10 || batches[2] != 5 {
+		t.Errorf("batch sizes = %v, want [10 10 5]", batches)
+	}
+}
+
+// Ensure ScrollCursor can be used wherever an io.Closer is expected
+var _ io.Closer = (*ScrollCursor)(nil)
+
+func TestScrollCursor_CloseOnExhaust(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-scroll-exhaust"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	seedDocuments(t, client, indexName, 12)
+
+	cursor, err := client.OpenScroll(ctx, indexName, MatchAllQuery(), 5, defaultScrollKeepAlive, CloseOnExhaust())
+	if err != nil {
+		t.Fatalf("OpenScroll() error = %v", err)
+	}
+
+	total := 0
+	for {
+		docs, err := cursor.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		total += len(docs)
+	}
+	if total != 12 {
+		t.Errorf("Scrolled %d documents, want 12", total)
+	}
+
+	if cursor.scrollID != "" {
+		t.Error("Scroll context should be cleared once Next reports exhaustion")
+	}
+	if _, err := cursor.Next(ctx); err != io.EOF {
+		t.Errorf("Next() after exhaustion error = %v, want io.EOF", err)
+	}
+
+	// Close after exhaustion is a no-op, and so is closing twice
+	if err := cursor.Close(); err != nil {
+		t.Errorf("Close() after exhaustion error = %v", err)
+	}
+	if err := cursor.Close(); err != nil {
+		t.Errorf("Second Close() error = %v", err)
+	}
+}
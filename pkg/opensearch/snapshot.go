@@ -0,0 +1,290 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// RestoreOptions controls which indices a snapshot restore brings back and under what names
+type RestoreOptions struct {
+	// Indices to restore, all indices in the snapshot when empty
+	Indices []string
+	// RenamePattern and RenameReplacement rename restored indices, e.g. "(.+)" and "restored-$1",
+	// so a snapshot can be restored alongside the open indices it was taken from
+	RenamePattern     string
+	RenameReplacement string
+	// IncludeGlobalState also restores templates and persistent cluster settings
+	IncludeGlobalState bool
+	// WaitForCompletion blocks until the restore has finished
+	WaitForCompletion bool
+}
+
+// SnapshotStatus describes the progress of a snapshot
+type SnapshotStatus struct {
+	Snapshot     string
+	Repository   string
+	State        string
+	ShardsDone   int
+	ShardsFailed int
+	ShardsTotal  int
+}
+
+// CreateSnapshotRepository registers (or updates) a snapshot repository, e.g. repoType "fs"
+// with a "location" setting inside one of the cluster's path.repo directories
+func (c *Client) CreateSnapshotRepository(ctx context.Context, name string, repoType string, settings map[string]interface{}) (err error) {
+	defer c.observe("CreateSnapshotRepository", time.Now(), &err)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"type":     repoType,
+		"settings": settings,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot repository: %w", err)
+	}
+
+	req := opensearchapi.SnapshotCreateRepositoryRequest{
+		Repository: name,
+		Body:       bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot repository: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return requestError("create snapshot repository", res)
+	}
+
+	return nil
+}
+
+// DeleteSnapshotRepository unregisters a snapshot repository. Snapshots stored in it are left in place.
+func (c *Client) DeleteSnapshotRepository(ctx context.Context, name string) (err error) {
+	defer c.observe("DeleteSnapshotRepository", time.Now(), &err)
+
+	req := opensearchapi.SnapshotDeleteRepositoryRequest{
+		Repository: []string{name},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot repository: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, "snapshot repository not found", nil)
+		}
+		return requestError("delete snapshot repository", res)
+	}
+
+	return nil
+}
+
+// CreateSnapshot takes a snapshot of the given indices (all indices when empty) into a repository.
+// With waitForCompletion the call blocks until the snapshot is done and fails unless every shard
+// was snapshotted successfully; otherwise use GetSnapshotStatus to follow progress.
+func (c *Client) CreateSnapshot(ctx context.Context, repo, snapshot string, indices []string, waitForCompletion bool) (err error) {
+	defer c.observe("CreateSnapshot", time.Now(), &err)
+
+	body := map[string]interface{}{}
+	if len(indices) > 0 {
+		body["indices"] = strings.Join(indices, ",")
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot request: %w", err)
+	}
+
+	req := opensearchapi.SnapshotCreateRequest{
+		Repository:        repo,
+		Snapshot:          snapshot,
+		Body:              bytes.NewReader(data),
+		WaitForCompletion: &waitForCompletion,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return requestError("create snapshot", res)
+	}
+
+	if !waitForCompletion {
+		return nil
+	}
+
+	var response struct {
+		Snapshot struct {
+			State    string `json:"state"`
+			Failures []struct {
+				Index  string `json:"index"`
+				Reason string `json:"reason"`
+			} `json:"failures"`
+		} `json:"snapshot"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return err
+	}
+
+	if response.Snapshot.State != "SUCCESS" {
+		if len(response.Snapshot.Failures) > 0 {
+			failure := response.Snapshot.Failures[0]
+			return fmt.Errorf("snapshot %s finished in state %s: index %s: %s", snapshot, response.Snapshot.State, failure.Index, failure.Reason)
+		}
+		return fmt.Errorf("snapshot %s finished in state %s", snapshot, response.Snapshot.State)
+	}
+
+	return nil
+}
+
+// GetSnapshotStatus returns the state and shard progress of a snapshot
+func (c *Client) GetSnapshotStatus(ctx context.Context, repo, snapshot string) (_ SnapshotStatus, err error) {
+	defer c.observe("GetSnapshotStatus", time.Now(), &err)
+
+	req := opensearchapi.SnapshotStatusRequest{
+		Repository: repo,
+		Snapshot:   []string{snapshot},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return SnapshotStatus{}, fmt.Errorf("failed to get snapshot status: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return SnapshotStatus{}, responseError(res, fmt.Sprintf("snapshot %s not found in repository %s", snapshot, repo), ErrSnapshotNotFound)
+		}
+		return SnapshotStatus{}, requestError("snapshot status", res)
+	}
+
+	var response struct {
+		Snapshots []struct {
+			Snapshot    string `json:"snapshot"`
+			Repository  string `json:"repository"`
+			State       string `json:"state"`
+			ShardsStats struct {
+				Done   int `json:"done"`
+				Failed int `json:"failed"`
+				Total  int `json:"total"`
+			} `json:"shards_stats"`
+		} `json:"snapshots"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return SnapshotStatus{}, err
+	}
+
+	if len(response.Snapshots) == 0 {
+		return SnapshotStatus{}, fmt.Errorf("snapshot %s not found in repository %s: %w", snapshot, repo, ErrSnapshotNotFound)
+	}
+
+	s := response.Snapshots[0]
+	return SnapshotStatus{
+		Snapshot:     s.Snapshot,
+		Repository:   s.Repository,
+		State:        s.State,
+		ShardsDone:   s.ShardsStats.Done,
+		ShardsFailed: s.ShardsStats.Failed,
+		ShardsTotal:  s.ShardsStats.Total,
+	}, nil
+}
+
+// RestoreSnapshot restores indices from a snapshot. Restoring an index that is currently
+// open fails with the server's reason; close or delete it first, or restore under a new
+// name with RenamePattern and RenameReplacement.
+func (c *Client) RestoreSnapshot(ctx context.Context, repo, snapshot string, opts RestoreOptions) (err error) {
+	defer c.observe("RestoreSnapshot", time.Now(), &err)
+
+	body := map[string]interface{}{
+		"include_global_state": opts.IncludeGlobalState,
+	}
+	if len(opts.Indices) > 0 {
+		body["indices"] = strings.Join(opts.Indices, ",")
+	}
+	if opts.RenamePattern != "" {
+		body["rename_pattern"] = opts.RenamePattern
+		body["rename_replacement"] = opts.RenameReplacement
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal restore request: %w", err)
+	}
+
+	req := opensearchapi.SnapshotRestoreRequest{
+		Repository:        repo,
+		Snapshot:          snapshot,
+		Body:              bytes.NewReader(data),
+		WaitForCompletion: &opts.WaitForCompletion,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, fmt.Sprintf("snapshot %s not found in repository %s", snapshot, repo), ErrSnapshotNotFound)
+		}
+		return fmt.Errorf("restore %s/%s: %w", repo, snapshot, requestError("restore snapshot", res))
+	}
+
+	if !opts.WaitForCompletion {
+		return nil
+	}
+
+	var response struct {
+		Snapshot struct {
+			Shards ShardsInfo `json:"shards"`
+		} `json:"snapshot"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return err
+	}
+
+	if response.Snapshot.Shards.Failed > 0 {
+		return fmt.Errorf("restore %s/%s: %d of %d shards failed", repo, snapshot, response.Snapshot.Shards.Failed, response.Snapshot.Shards.Total)
+	}
+
+	return nil
+}
+
+// DeleteSnapshot deletes a snapshot from a repository
+func (c *Client) DeleteSnapshot(ctx context.Context, repo, snapshot string) (err error) {
+	defer c.observe("DeleteSnapshot", time.Now(), &err)
+
+	req := opensearchapi.SnapshotDeleteRequest{
+		Repository: repo,
+		Snapshot:   []string{snapshot},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, fmt.Sprintf("snapshot %s not found in repository %s", snapshot, repo), ErrSnapshotNotFound)
+		}
+		return requestError("delete snapshot", res)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// setupTestRepository registers an fs snapshot repository at OPENSEARCH_SNAPSHOT_PATH,
+// which must be listed in the cluster's path.repo setting
+func setupTestRepository(t *testing.T, client *Client, name string) func() {
+	t.Helper()
+
+	location := os.Getenv("OPENSEARCH_SNAPSHOT_PATH")
+	if location == "" {
+		t.Skip("OPENSEARCH_SNAPSHOT_PATH not set, skipping snapshot test")
+	}
+
+	ctx := context.Background()
+	err := client.CreateSnapshotRepository(ctx, name, "fs", map[string]interface{}{
+		"location": location,
+	})
+	if err != nil {
+		t.Fatalf("CreateSnapshotRepository() error = %v", err)
+	}
+
+	return func() {
+		_ = client.DeleteSnapshotRepository(ctx, name)
+	}
+}
+
+func TestSnapshotAndRestore(t *testing.T) {
+	client := setupTestClient(t)
+	repo := "test-snapshot-repo"
+	snapshot := "test-snapshot-1"
+	indexName := "test-snapshot-index"
+	restoredName := "restored-test-snapshot-index"
+
+	cleanupRepo := setupTestRepository(t, client, repo)
+	defer cleanupRepo()
+	cleanup := setupShardedIndex(t, client, indexName, 1)
+	defer cleanup()
+	defer func() { _ = client.DeleteIndex(context.Background(), restoredName) }()
+
+	ctx := context.Background()
+	seedDocuments(t, client, indexName, 8)
+
+	_ = client.DeleteSnapshot(ctx, repo, snapshot)
+	if err := client.CreateSnapshot(ctx, repo, snapshot, []string{indexName}, true); err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+	defer func() { _ = client.DeleteSnapshot(ctx, repo, snapshot) }()
+
+	t.Run("Status", func(t *testing.T) {
+		status, err := client.GetSnapshotStatus(ctx, repo, snapshot)
+		if err != nil {
+			t.Fatalf("GetSnapshotStatus() error = %v", err)
+		}
+		if status.State != "SUCCESS" {
+			t.Errorf("State = %s, want SUCCESS", status.State)
+		}
+		if status.ShardsTotal != 1 || status.ShardsDone != 1 {
+			t.Errorf("Shards = %d/%d done, want 1/1", status.ShardsDone, status.ShardsTotal)
+		}
+	})
+
+	t.Run("Restore onto open index fails", func(t *testing.T) {
+		err := client.RestoreSnapshot(ctx, repo, snapshot, RestoreOptions{
+			Indices:           []string{indexName},
+			WaitForCompletion: true,
+		})
+		if err == nil {
+			t.Fatal("RestoreSnapshot() onto open index expected error but got nil")
+		}
+		if !strings.Contains(err.Error(), "open index") {
+			t.Errorf("RestoreSnapshot() error = %v, want the server's reason", err)
+		}
+	})
+
+	t.Run("Restore with rename", func(t *testing.T) {
+		err := client.RestoreSnapshot(ctx, repo, snapshot, RestoreOptions{
+			Indices:           []string{indexName},
+			RenamePattern:     "(.+)",
+			RenameReplacement: "restored-$1",
+			WaitForCompletion: true,
+		})
+		if err != nil {
+			t.Fatalf("RestoreSnapshot() error = %v", err)
+		}
+
+		results, err := client.SearchDocuments(ctx, restoredName, WithSize(MatchAllQuery(), 100))
+		if err != nil {
+			t.Fatalf("Failed to search restored index: %v", err)
+		}
+		if len(results) != 8 {
+			t.Errorf("Restored index has %d documents, want 8", len(results))
+		}
+	})
+
+	t.Run("Restore after delete", func(t *testing.T) {
+		if err := client.DeleteIndex(ctx, indexName); err != nil {
+			t.Fatalf("DeleteIndex() error = %v", err)
+		}
+
+		err := client.RestoreSnapshot(ctx, repo, snapshot, RestoreOptions{
+			Indices:           []string{indexName},
+			WaitForCompletion: true,
+		})
+		if err != nil {
+			t.Fatalf("RestoreSnapshot() error = %v", err)
+		}
+
+		results, err := client.SearchDocuments(ctx, indexName, WithSize(MatchAllQuery(), 100))
+		if err != nil {
+			t.Fatalf("Failed to search restored index: %v", err)
+		}
+		if len(results) != 8 {
+			t.Errorf("Restored index has %d documents, want 8", len(results))
+		}
+	})
+
+	t.Run("Delete snapshot", func(t *testing.T) {
+		if err := client.DeleteSnapshot(ctx, repo, snapshot); err != nil {
+			t.Fatalf("DeleteSnapshot() error = %v", err)
+		}
+		if _, err := client.GetSnapshotStatus(ctx, repo, snapshot); !errors.Is(err, ErrSnapshotNotFound) {
+			t.Errorf("GetSnapshotStatus() after delete error = %v, want ErrSnapshotNotFound", err)
+		}
+	})
+}
+
+func TestSnapshotNotFound(t *testing.T) {
+	ctx := context.Background()
+	missing := `{"error":{"type":"snapshot_missing_exception","reason":"[backups:nightly] is missing"},"status":404}`
+
+	tests := []struct {
+		name string
+		stub *stubTransport
+		call func(*Client) error
+	}{
+		{
+			name: "status 404",
+			stub: &stubTransport{status: 404, body: missing},
+			call: func(c *Client) error {
+				_, err := c.GetSnapshotStatus(ctx, "backups", "nightly")
+				return err
+			},
+		},
+		{
+			name: "status without snapshots",
+			stub: &stubTransport{status: 200, body: `{"snapshots":[]}`},
+			call: func(c *Client) error {
+				_, err := c.GetSnapshotStatus(ctx, "backups", "nightly")
+				return err
+			},
+		},
+		{
+			name: "restore",
+			stub: &stubTransport{status: 404, body: missing},
+			call: func(c *Client) error {
+				return c.RestoreSnapshot(ctx, "backups", "nightly", RestoreOptions{})
+			},
+		},
+		{
+			name: "delete",
+			stub: &stubTransport{status: 404, body: missing},
+			call: func(c *Client) error {
+				return c.DeleteSnapshot(ctx, "backups", "nightly")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(newStubClient(t, tt.stub))
+			if !errors.Is(err, ErrSnapshotNotFound) || !strings.Contains(err.Error(), "nightly") {
+				t.Errorf("error = %v, want ErrSnapshotNotFound naming the snapshot", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// TaskCounters holds the progress counters reported by reindex and by-query tasks
+type TaskCounters struct {
+	Total            int64 `json:"total"`
+	Created          int64 `json:"created"`
+	Updated          int64 `json:"updated"`
+	Deleted          int64 `json:"deleted"`
+	Batches          int64 `json:"batches"`
+	VersionConflicts int64 `json:"version_conflicts"`
+}
+
+// TaskStatus describes a server-side task
+type TaskStatus struct {
+	ID          string
+	Action      string
+	Description string
+	Completed   bool
+	Cancellable bool
+	RunningTime time.Duration
+	Status      TaskCounters
+	// Error is the failure reason of a completed task that failed
+	Error string
+	// Response is the raw result of a completed task
+	Response json.RawMessage
+}
+
+// taskInfo is the description of a task as returned by the tasks APIs
+type taskInfo struct {
+	Node               string       `json:"node"`
+	ID                 int64        `json:"id"`
+	Action             string       `json:"action"`
+	Description        string       `json:"description"`
+	Cancellable        bool         `json:"cancellable"`
+	RunningTimeInNanos int64        `json:"running_time_in_nanos"`
+	Status             TaskCounters `json:"status"`
+}
+
+// toStatus converts the task description into a TaskStatus
+func (t taskInfo) toStatus() TaskStatus {
+	return TaskStatus{
+		ID:          fmt.Sprintf("%s:%d", t.Node, t.ID),
+		Action:      t.Action,
+		Description: t.Description,
+		Cancellable: t.Cancellable,
+		RunningTime: time.Duration(t.RunningTimeInNanos),
+		Status:      t.Status,
+	}
+}
+
+// ListTasks returns the tasks currently running in the cluster. actions filters
+// by action name and accepts wildcards, e.g. "*reindex"; all tasks when empty.
+func (c *Client) ListTasks(ctx context.Context, actions string) (_ []TaskStatus, err error) {
+	defer c.observe("ListTasks", time.Now(), &err)
+
+	detailed := true
+	req := opensearchapi.TasksListRequest{
+		Detailed: &detailed,
+		GroupBy:  "none",
+	}
+	if actions != "" {
+		req.Actions = []string{actions}
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, requestError("list tasks", res)
+	}
+
+	var response struct {
+		Tasks []taskInfo `json:"tasks"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]TaskStatus, 0, len(response.Tasks))
+	for _, task := range response.Tasks {
+		tasks = append(tasks, task.toStatus())
+	}
+
+	return tasks, nil
+}
+
+// GetTask returns the status of a task by its "node:id" identifier
+func (c *Client) GetTask(ctx context.Context, taskID string) (_ *TaskStatus, err error) {
+	defer c.observe("GetTask", time.Now(), &err)
+
+	req := opensearchapi.TasksGetRequest{
+		TaskID: taskID,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "task not found", nil)
+		}
+		return nil, requestError("get task", res)
+	}
+
+	var response struct {
+		Completed bool     `json:"completed"`
+		Task      taskInfo `json:"task"`
+		Error     *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+		Response json.RawMessage `json:"response"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	status := response.Task.toStatus()
+	status.Completed = response.Completed
+	status.Response = response.Response
+	if response.Error != nil {
+		status.Error = response.Error.Reason
+	}
+
+	return &status, nil
+}
+
+// CancelTask requests cancellation of a running task. Only tasks reported as
+// Cancellable can be cancelled; cancellation completes asynchronously.
+func (c *Client) CancelTask(ctx context.Context, taskID string) (err error) {
+	defer c.observe("CancelTask", time.Now(), &err)
+
+	req := opensearchapi.TasksCancelRequest{
+		TaskID: taskID,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to cancel task: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, "task not found", nil)
+		}
+		return requestError("cancel task", res)
+	}
+
+	var response struct {
+		NodeFailures []struct {
+			Reason string `json:"reason"`
+		} `json:"node_failures"`
+		TaskFailures []struct {
+			Reason struct {
+				Reason string `json:"reason"`
+			} `json:"reason"`
+		} `json:"task_failures"`
+	}
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return err
+	}
+
+	if len(response.TaskFailures) > 0 {
+		return fmt.Errorf("failed to cancel task %s: %s", taskID, response.TaskFailures[0].Reason.Reason)
+	}
+	if len(response.NodeFailures) > 0 {
+		return fmt.Errorf("failed to cancel task %s: %s", taskID, response.NodeFailures[0].Reason)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package opensearch
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// waitForTask polls a task until it completes or the timeout passes
+func waitForTask(t *testing.T, client *Client, taskID string, timeout time.Duration) *TaskStatus {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := client.GetTask(context.Background(), taskID)
+		if err != nil {
+			t.Fatalf("GetTask() error = %v", err)
+		}
+		if status.Completed {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Task %s did not complete within %s", taskID, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestAsyncReindexTask(t *testing.T) {
+	client := setupTestClient(t)
+	source := "test-task-source"
+	dest := "test-task-dest"
+	cleanup := setupTestIndex(t, client, source)
+	defer cleanup()
+	defer func() { _ = client.DeleteIndex(context.Background(), dest) }()
+
+	ctx := context.Background()
+	seedDocuments(t, client, source, 30)
+
+	taskID, err := client.ReindexAsync(ctx, source, dest, nil)
+	if err != nil {
+		t.Fatalf("ReindexAsync() error = %v", err)
+	}
+
+	status := waitForTask(t, client, taskID, 30*time.Second)
+	if status.Error != "" {
+		t.Fatalf("Reindex task failed: %s", status.Error)
+	}
+	if status.Status.Created != 30 {
+		t.Errorf("Reindex task created = %d, want 30", status.Status.Created)
+	}
+	if status.Action != "indices:data/write/reindex" {
+		t.Errorf("Task action = %s, want indices:data/write/reindex", status.Action)
+	}
+}
+
+func TestAsyncDeleteByQueryTask(t *testing.T) {
+	client := setupTestClient(t)
+	indexName := "test-task-delete-by-query"
+	cleanup := setupTestIndex(t, client, indexName)
+	defer cleanup()
+
+	ctx := context.Background()
+	seedDocuments(t, client, indexName, 10)
+
+	taskID, err := client.DeleteByQueryAsync(ctx, indexName, RangeQuery("seq", 5, nil))
+	if err != nil {
+		t.Fatalf("DeleteByQueryAsync() error = %v", err)
+	}
+
+	status := waitForTask(t, client, taskID, 30*time.Second)
+	if status.Status.Deleted != 5 {
+		t.Errorf("Delete by query task deleted = %d, want 5", status.Status.Deleted)
+	}
+}
+
+func TestListAndCancelTasks(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	tasks, err := client.ListTasks(ctx, "cluster:monitor/tasks/lists*")
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	// The list request itself is running while it is served
+	if len(tasks) == 0 {
+		t.Fatal("ListTasks() returned no tasks, expected at least the list request itself")
+	}
+	if tasks[0].ID == "" || tasks[0].Action == "" {
+		t.Errorf("ListTasks() entry missing ID or action: %+v", tasks[0])
+	}
+
+	if err := client.CancelTask(ctx, "missing-node:1"); err == nil {
+		t.Error("CancelTask() on unknown task expected error but got nil")
+	}
+}
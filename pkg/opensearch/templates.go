@@ -0,0 +1,168 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// IndexTemplate represents a composable index template
+type IndexTemplate struct {
+	IndexPatterns []string               `json:"index_patterns"`
+	ComposedOf    []string               `json:"composed_of,omitempty"`
+	Template      map[string]interface{} `json:"template,omitempty"`
+	Priority      int                    `json:"priority,omitempty"`
+	Version       int                    `json:"version,omitempty"`
+}
+
+// componentTemplatesResponse represents the response from a GET _component_template request
+type componentTemplatesResponse struct {
+	ComponentTemplates []struct {
+		Name              string                 `json:"name"`
+		ComponentTemplate map[string]interface{} `json:"component_template"`
+	} `json:"component_templates"`
+}
+
+// PutComponentTemplate creates or replaces a reusable component template.
+// The body typically holds a "template" object with settings, mappings and aliases.
+func (c *Client) PutComponentTemplate(ctx context.Context, name string, body map[string]interface{}) (err error) {
+	defer c.observe("PutComponentTemplate", time.Now(), &err)
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal component template: %w", err)
+	}
+
+	req := opensearchapi.ClusterPutComponentTemplateRequest{
+		Name: name,
+		Body: bytes.NewReader(bodyBytes),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to put component template: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return requestError("put component template", res)
+	}
+
+	return nil
+}
+
+// GetComponentTemplate returns the definition of a component template
+func (c *Client) GetComponentTemplate(ctx context.Context, name string) (_ map[string]interface{}, err error) {
+	defer c.observe("GetComponentTemplate", time.Now(), &err)
+
+	req := opensearchapi.ClusterGetComponentTemplateRequest{
+		Name: []string{name},
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get component template: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return nil, responseError(res, "component template not found", nil)
+		}
+		return nil, requestError("get component template", res)
+	}
+
+	var response componentTemplatesResponse
+	if err := c.parseResponse(res.Body, &response); err != nil {
+		return nil, err
+	}
+
+	for _, template := range response.ComponentTemplates {
+		if template.Name == name {
+			return template.ComponentTemplate, nil
+		}
+	}
+
+	return nil, responseError(res, "component template not found", nil)
+}
+
+// DeleteComponentTemplate deletes a component template
+func (c *Client) DeleteComponentTemplate(ctx context.Context, name string) (err error) {
+	defer c.observe("DeleteComponentTemplate", time.Now(), &err)
+
+	req := opensearchapi.ClusterDeleteComponentTemplateRequest{
+		Name: name,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to delete component template: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, "component template not found", nil)
+		}
+		return requestError("delete component template", res)
+	}
+
+	return nil
+}
+
+// PutIndexTemplate creates or replaces a composable index template. Component
+// templates listed in ComposedOf must already exist; otherwise the server's
+// validation reason is returned in the error.
+func (c *Client) PutIndexTemplate(ctx context.Context, name string, template IndexTemplate) (err error) {
+	defer c.observe("PutIndexTemplate", time.Now(), &err)
+
+	body, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index template: %w", err)
+	}
+
+	req := opensearchapi.IndicesPutIndexTemplateRequest{
+		Name: name,
+		Body: bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to put index template: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return requestError("put index template", res)
+	}
+
+	return nil
+}
+
+// DeleteIndexTemplate deletes a composable index template
+func (c *Client) DeleteIndexTemplate(ctx context.Context, name string) (err error) {
+	defer c.observe("DeleteIndexTemplate", time.Now(), &err)
+
+	req := opensearchapi.IndicesDeleteIndexTemplateRequest{
+		Name: name,
+	}
+
+	res, err := req.Do(ctx, c.client)
+	if err != nil {
+		return fmt.Errorf("failed to delete index template: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			return responseError(res, "index template not found", nil)
+		}
+		return requestError("delete index template", res)
+	}
+
+	return nil
+}
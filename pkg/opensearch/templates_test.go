@@ -0,0 +1,113 @@
+package opensearch
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+func TestComponentTemplates(t *testing.T) {
+	client := setupTestClient(t)
+	ctx := context.Background()
+
+	timestamps := "test-component-timestamps"
+	tenant := "test-component-tenant"
+	templateName := "test-composed-template"
+	indexName := "test-composed-index"
+
+	defer func() {
+		_ = client.DeleteIndex(ctx, indexName)
+		_ = client.DeleteIndexTemplate(ctx, templateName)
+		_ = client.DeleteComponentTemplate(ctx, timestamps)
+		_ = client.DeleteComponentTemplate(ctx, tenant)
+	}()
+
+	components := map[string]map[string]interface{}{
+		timestamps: {
+			"template": map[string]interface{}{
+				"mappings": map[string]interface{}{
+					"properties": map[string]interface{}{
+						"created_at": map[string]interface{}{"type": "date"},
+					},
+				},
+			},
+		},
+		tenant: {
+			"template": map[string]interface{}{
+				"mappings": map[string]interface{}{
+					"properties": map[string]interface{}{
+						"tenant_id": map[string]interface{}{"type": "keyword"},
+					},
+				},
+			},
+		},
+	}
+	for name, body := range components {
+		if err := client.PutComponentTemplate(ctx, name, body); err != nil {
+			t.Fatalf("PutComponentTemplate(%s) error = %v", name, err)
+		}
+	}
+
+	t.Run("Get component template", func(t *testing.T) {
+		template, err := client.GetComponentTemplate(ctx, timestamps)
+		if err != nil {
+			t.Fatalf("GetComponentTemplate() error = %v", err)
+		}
+		if _, ok := template["template"]; !ok {
+			t.Errorf("Component template missing 'template' key: %v", prettyPrint(template))
+		}
+	})
+
+	t.Run("Unknown component is rejected with reason", func(t *testing.T) {
+		err := client.PutIndexTemplate(ctx, templateName+"-invalid", IndexTemplate{
+			IndexPatterns: []string{"test-never-matches-*"},
+			ComposedOf:    []string{"test-component-does-not-exist"},
+		})
+		if err == nil {
+			_ = client.DeleteIndexTemplate(ctx, templateName+"-invalid")
+			t.Fatal("PutIndexTemplate() expected error for unknown component but got nil")
+		}
+		if !strings.Contains(err.Error(), "test-component-does-not-exist") {
+			t.Errorf("Error should carry the server reason, got: %v", err)
+		}
+	})
+
+	t.Run("Index mapping contains fields from both components", func(t *testing.T) {
+		err := client.PutIndexTemplate(ctx, templateName, IndexTemplate{
+			IndexPatterns: []string{indexName},
+			ComposedOf:    []string{timestamps, tenant},
+			Priority:      100,
+		})
+		if err != nil {
+			t.Fatalf("PutIndexTemplate() error = %v", err)
+		}
+
+		if err := client.CreateIndex(ctx, indexName, nil); err != nil {
+			t.Fatalf("CreateIndex() error = %v", err)
+		}
+
+		res, err := opensearchapi.IndicesGetMappingRequest{Index: []string{indexName}}.Do(ctx, client.GetClient())
+		if err != nil {
+			t.Fatalf("Failed to get mapping: %v", err)
+		}
+		defer res.Body.Close()
+
+		var mappings map[string]struct {
+			Mappings struct {
+				Properties map[string]interface{} `json:"properties"`
+			} `json:"mappings"`
+		}
+		if err := client.parseResponse(res.Body, &mappings); err != nil {
+			t.Fatalf("Failed to parse mapping: %v", err)
+		}
+
+		properties := mappings[indexName].Mappings.Properties
+		for _, field := range []string{"created_at", "tenant_id"} {
+			if _, ok := properties[field]; !ok {
+				t.Errorf("Index mapping missing field %s from component template: %v", field, prettyPrint(properties))
+			}
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Tracer starts a span around each HTTP request the client sends, including each retry.
+// The context it returns is used for the request, so the span is the parent of anything
+// started below it, and it is a child of whatever span is in the caller's context.
+// See the oteltrace package for an OpenTelemetry implementation.
+type Tracer interface {
+	StartSpan(ctx context.Context, start SpanStart) (context.Context, func(end SpanEnd))
+}
+
+// SpanStart describes a request as it is sent
+type SpanStart struct {
+	// Operation is one of the Operation constants, as in RequestMetrics
+	Operation string
+	Index     string
+	// DocumentID is set for requests that address a single document
+	DocumentID string
+	Method     string
+	Path       string
+}
+
+// SpanEnd describes the outcome of a request
+type SpanEnd struct {
+	// Status is the response status, or 0 when no response was received
+	Status int
+	// Hits is the total hit count of a search response, or -1 for other requests
+	Hits int64
+	// Err is set when no response was received
+	Err error
+}
+
+// tracingTransport wraps each request attempt in a span
+type tracingTransport struct {
+	next   http.RoundTripper
+	tracer Tracer
+}
+
+// newTracingTransport wraps next so that requests are traced by tracer
+func newTracingTransport(next http.RoundTripper, tracer Tracer) *tracingTransport {
+	return &tracingTransport{next: next, tracer: tracer}
+}
+
+// RoundTrip sends the request within a span. Search responses are read to record
+// their hit count, and handed on from memory.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := SpanStart{
+		DocumentID: requestDocumentID(req.URL.Path),
+		Method:     req.Method,
+		Path:       req.URL.Path,
+	}
+	start.Operation, start.Index = classifyRequest(req.Method, req.URL.Path)
+
+	ctx, end := t.tracer.StartSpan(req.Context(), start)
+	res, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		end(SpanEnd{Hits: -1, Err: err})
+		return nil, err
+	}
+
+	result := SpanEnd{Status: res.StatusCode, Hits: -1}
+	if start.Operation == OperationSearch && res.StatusCode < 300 {
+		body, readErr := io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		if readErr != nil {
+			result.Err = fmt.Errorf("failed to read search response: %w", readErr)
+		} else {
+			result.Hits = searchHitCount(body)
+		}
+	}
+	end(result)
+
+	return res, nil
+}
+
+// requestDocumentID returns the document ID in a single-document request path, e.g.
+// "1" in /my-index/_doc/1
+func requestDocumentID(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		switch segments[i] {
+		case "_doc", "_create", "_update", "_source", "_explain":
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// searchHitCount returns hits.total of a search or count response, or -1 if it has none
+func searchHitCount(body []byte) int64 {
+	var response struct {
+		Count *int64 `json:"count"`
+		Hits  struct {
+			Total *struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return -1
+	}
+
+	switch {
+	case response.Hits.Total != nil:
+		return response.Hits.Total.Value
+	case response.Count != nil:
+		return *response.Count
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,152 @@
+package opensearch
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// tracedSpan is a span recorded by recordingTracer
+type tracedSpan struct {
+	start  SpanStart
+	end    SpanEnd
+	parent string
+}
+
+type spanKey struct{}
+
+// recordingTracer keeps every span it starts, and names each span after its operation
+// in the context so that child spans can find their parent
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*tracedSpan
+}
+
+func (r *recordingTracer) StartSpan(ctx context.Context, start SpanStart) (context.Context, func(SpanEnd)) {
+	span := &tracedSpan{start: start}
+	span.parent, _ = ctx.Value(spanKey{}).(string)
+
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, start.Operation), func(end SpanEnd) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		span.end = end
+	}
+}
+
+func (r *recordingTracer) last(t *testing.T) *tracedSpan {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.spans) == 0 {
+		t.Fatal("No span was started")
+	}
+	return r.spans[len(r.spans)-1]
+}
+
+func TestRequestDocumentID(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/my-index/_doc/1", want: "1"},
+		{path: "/my-index/_create/abc", want: "abc"},
+		{path: "/my-index/_update/2", want: "2"},
+		{path: "/my-index/_source/3", want: "3"},
+		{path: "/my-index/_doc", want: ""},
+		{path: "/my-index/_search", want: ""},
+		{path: "/_bulk", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := requestDocumentID(tt.path); got != tt.want {
+			t.Errorf("requestDocumentID(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSearchHitCount(t *testing.T) {
+	tests := []struct {
+		body string
+		want int64
+	}{
+		{body: `{"hits":{"total":{"value":42,"relation":"eq"},"hits":[]}}`, want: 42},
+		{body: `{"count":5}`, want: 5},
+		{body: `{"hits":{"hits":[]}}`, want: -1},
+		{body: `not json`, want: -1},
+	}
+
+	for _, tt := range tests {
+		if got := searchHitCount([]byte(tt.body)); got != tt.want {
+			t.Errorf("searchHitCount(%s) = %d, want %d", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	stub := &stubTransport{status: 200, body: `{"_id":"1","found":true,"_source":{"title":"one"},"hits":{"total":{"value":3},"hits":[{"_id":"1","_source":{"title":"one"}}]}}`}
+	client, err := newClient(Config{
+		Addresses:    []string{"http://stub:9200"},
+		DisableRetry: true,
+		Tracer:       tracer,
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+	ctx := context.WithValue(context.Background(), spanKey{}, "handler")
+
+	results, err := client.SearchDocuments(ctx, "my-index", MatchAllQuery())
+	if err != nil {
+		t.Fatalf("SearchDocuments() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("SearchDocuments() returned %d results after tracing read the body, want 1", len(results))
+	}
+	search := tracer.last(t)
+	if search.start.Operation != OperationSearch || search.start.Index != "my-index" || search.parent != "handler" {
+		t.Errorf("Search span start = %+v under %q, want a search of my-index under handler", search.start, search.parent)
+	}
+	if search.end.Status != 200 || search.end.Hits != 3 || search.end.Err != nil {
+		t.Errorf("Search span end = %+v, want 200 with 3 hits", search.end)
+	}
+
+	if _, err := client.GetDocument(ctx, "my-index", "1"); err != nil {
+		t.Fatalf("GetDocument() error = %v", err)
+	}
+	get := tracer.last(t)
+	if get.start.Operation != OperationGet || get.start.DocumentID != "1" || get.end.Hits != -1 {
+		t.Errorf("Get span = %+v, want a get of document 1 without hits", get)
+	}
+
+	stub.status = 404
+	_ = client.DeleteDocument(ctx, "my-index", "missing")
+	deleted := tracer.last(t)
+	// The span's context reaches the transport
+	traced, _ := stub.ctx.Value(spanKey{}).(string)
+	if deleted.end.Status != 404 || traced != OperationDelete {
+		t.Errorf("Delete span end = %+v with %q in the request context, want 404 within the delete span", deleted.end, traced)
+	}
+}
+
+func TestTracer_TransportError(t *testing.T) {
+	tracer := &recordingTracer{}
+	client, err := NewClient(Config{
+		Addresses:    []string{"http://127.0.0.1:1"},
+		DisableRetry: true,
+		Tracer:       tracer,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, err := client.GetDocument(context.Background(), "my-index", "1"); err == nil {
+		t.Fatal("GetDocument() succeeded despite the transport error")
+	}
+	if end := tracer.last(t).end; end.Err == nil || end.Status != 0 {
+		t.Errorf("Span end = %+v, want the transport error", end)
+	}
+}
@@ -0,0 +1,337 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchtransport"
+)
+
+// retryTransport waits between the retries made by the opensearch transport. The
+// opensearch transport sleeps between attempts without looking at the request's
+// context, so instead this transport, which sees each attempt, does the waiting
+// after a failed attempt that will be retried, and aborts it when the context is done.
+// Its retry decision mirrors the opensearch transport's: network errors other than
+// timeouts and responses with a status in retryOnStatus, up to maxRetries retries.
+// A retried response with a Retry-After header is waited on for as long as it asks,
+// up to maxRetryAfter, instead of the backoff.
+type retryTransport struct {
+	next          http.RoundTripper
+	maxRetries    int
+	retryOnStatus map[int]bool
+	backoff       func(attempt int) time.Duration
+	maxRetryAfter time.Duration
+
+	mu       sync.Mutex
+	attempts map[*http.Request]int
+}
+
+// newRetryTransport wraps next with context-aware retry backoff
+func newRetryTransport(next http.RoundTripper, maxRetries int, retryOnStatus []int, backoff func(attempt int) time.Duration, maxRetryAfter time.Duration) *retryTransport {
+	statuses := make(map[int]bool, len(retryOnStatus))
+	for _, status := range retryOnStatus {
+		statuses[status] = true
+	}
+
+	return &retryTransport{
+		next:          next,
+		maxRetries:    maxRetries,
+		retryOnStatus: statuses,
+		backoff:       backoff,
+		maxRetryAfter: maxRetryAfter,
+		attempts:      make(map[*http.Request]int),
+	}
+}
+
+// RoundTrip sends one attempt of a request and, when it will be retried, waits out the backoff
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if err := ctx.Err(); err != nil {
+		t.forget(req)
+		return nil, err
+	}
+
+	res, err := t.next.RoundTrip(req)
+
+	attempt := t.record(req)
+	if attempt > t.maxRetries || !t.retryable(res, err) {
+		t.forget(req)
+		return res, err
+	}
+
+	wait := t.backoff(attempt)
+	if retryAfter, ok := retryAfterDelay(res, time.Now()); ok {
+		wait = min(retryAfter, t.maxRetryAfter)
+	}
+	if wait <= 0 {
+		return res, err
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return res, err
+	case <-ctx.Done():
+		// A context error is not retryable, so the opensearch transport stops here
+		t.forget(req)
+		if res != nil {
+			res.Body.Close()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// retryAfterDelay returns the wait asked for by the Retry-After header of res, given in
+// seconds or as an HTTP date, and whether there is one
+func retryAfterDelay(res *http.Response, now time.Time) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(res.Header.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// retryable reports whether the opensearch transport retries after this outcome
+func (t *retryTransport) retryable(res *http.Response, err error) bool {
+	if err != nil {
+		if err == io.EOF {
+			return true
+		}
+		if netErr, ok := err.(net.Error); ok && !netErr.Timeout() {
+			return true
+		}
+		return false
+	}
+	return t.retryOnStatus[res.StatusCode]
+}
+
+// record counts an attempt of the request and returns how many attempts have been made
+func (t *retryTransport) record(req *http.Request) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.attempts[req]++
+	return t.attempts[req]
+}
+
+// forget drops the attempt count of a request that will not be retried again
+func (t *retryTransport) forget(req *http.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.attempts, req)
+}
+
+// defaultRetryBackoff doubles the wait with every attempt, from 100ms up to 5s
+func defaultRetryBackoff(attempt int) time.Duration {
+	wait := defaultRetryBackoffBase
+	for i := 1; i < attempt && wait < defaultRetryBackoffMax; i++ {
+		wait *= 2
+	}
+	if wait > defaultRetryBackoffMax {
+		wait = defaultRetryBackoffMax
+	}
+	return wait
+}
+
+// requestTimeoutKey is the context key of a per-call request timeout override
+type requestTimeoutKey struct{}
+
+// WithRequestTimeout returns a context whose requests use timeout instead of
+// Config.RequestTimeout, e.g. for a force merge that is known to take long.
+// A timeout of 0 disables the client-level timeout for those requests.
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, timeout)
+}
+
+// timeoutTransport bounds each request attempt by a timeout
+type timeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+// newTimeoutTransport wraps next so that each attempt is bounded by timeout
+func newTimeoutTransport(next http.RoundTripper, timeout time.Duration) *timeoutTransport {
+	return &timeoutTransport{next: next, timeout: timeout}
+}
+
+// RoundTrip sends the request with the timeout applied to its context. The timeout
+// keeps running until the response body is closed.
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := t.timeout
+	if override, ok := req.Context().Value(requestTimeoutKey{}).(time.Duration); ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	res, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// defaultDeadlineTransport gives requests without a deadline a default timeout. Unlike
+// timeoutTransport, it wraps the opensearch transport, so the timeout covers every attempt.
+type defaultDeadlineTransport struct {
+	next    opensearchtransport.Interface
+	timeout time.Duration
+}
+
+// newDefaultDeadlineTransport wraps next so that requests without a deadline get timeout
+func newDefaultDeadlineTransport(next opensearchtransport.Interface, timeout time.Duration) *defaultDeadlineTransport {
+	return &defaultDeadlineTransport{next: next, timeout: timeout}
+}
+
+// Perform sends the request, with the default timeout if its context has no deadline. The
+// timeout keeps running until the response body is closed.
+func (t *defaultDeadlineTransport) Perform(req *http.Request) (*http.Response, error) {
+	if _, ok := req.Context().Deadline(); ok {
+		return t.next.Perform(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	res, err := t.next.Perform(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// DiscoverNodes reloads the node list of the wrapped transport
+func (t *defaultDeadlineTransport) DiscoverNodes() error {
+	discoverable, ok := t.next.(opensearchtransport.Discoverable)
+	if !ok {
+		return fmt.Errorf("transport does not support node discovery")
+	}
+	return discoverable.DiscoverNodes()
+}
+
+// Metrics returns the metrics of the wrapped transport
+func (t *defaultDeadlineTransport) Metrics() (opensearchtransport.Metrics, error) {
+	measurable, ok := t.next.(opensearchtransport.Measurable)
+	if !ok {
+		return opensearchtransport.Metrics{}, fmt.Errorf("transport does not support metrics")
+	}
+	return measurable.Metrics()
+}
+
+// cancelOnClose releases a request's timeout context when its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the context
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// tokenTransport authenticates each request attempt with a bearer token. The token is
+// only added to the copy of the request that is sent, so it never appears on the request
+// seen by the opensearch transport or its logger.
+type tokenTransport struct {
+	next  http.RoundTripper
+	token func(ctx context.Context) (string, error)
+}
+
+// newTokenTransport wraps next so that requests carry the token returned by token
+func newTokenTransport(next http.RoundTripper, token func(ctx context.Context) (string, error)) *tokenTransport {
+	return &tokenTransport{next: next, token: token}
+}
+
+// RoundTrip sends a copy of the request with the Authorization header set
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.token(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("failed to get bearer token: %w", err)
+	}
+
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", "Bearer "+token)
+
+	return t.next.RoundTrip(authorized)
+}
+
+// requestHeadersKey is the context key of the headers added with WithHeader
+type requestHeadersKey struct{}
+
+// WithHeader returns a context whose requests carry the header key: value, e.g. a
+// correlation ID. Calls can be chained to add several headers; a later value for the same
+// key replaces an earlier one, as well as any Config.Headers value for it.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	headers := http.Header{}
+	if parent, ok := ctx.Value(requestHeadersKey{}).(http.Header); ok {
+		headers = parent.Clone()
+	}
+	headers.Set(key, value)
+	return context.WithValue(ctx, requestHeadersKey{}, headers)
+}
+
+// headerTransport adds the client's default headers and those of the request's context to
+// each request attempt. Like the bearer token, they are only set on the copy of the request
+// that is sent, so they never appear in the opensearch transport's logs.
+type headerTransport struct {
+	next    http.RoundTripper
+	headers http.Header
+}
+
+// newHeaderTransport wraps next so that requests carry the given default headers
+func newHeaderTransport(next http.RoundTripper, headers map[string]string) *headerTransport {
+	defaults := make(http.Header, len(headers))
+	for key, value := range headers {
+		defaults.Set(key, value)
+	}
+	return &headerTransport{next: next, headers: defaults}
+}
+
+// RoundTrip sends a copy of the request with the headers set
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	perRequest, _ := req.Context().Value(requestHeadersKey{}).(http.Header)
+	if len(t.headers) == 0 && len(perRequest) == 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	withHeaders := req.Clone(req.Context())
+	for _, headers := range []http.Header{t.headers, perRequest} {
+		for key, values := range headers {
+			withHeaders.Header[key] = values
+		}
+	}
+
+	return t.next.RoundTrip(withHeaders)
+}
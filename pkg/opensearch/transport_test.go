@@ -0,0 +1,521 @@
+package opensearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	t.Run("Retries retryable statuses with backoff", func(t *testing.T) {
+		stub := &stubTransport{statuses: []int{503, 502}, status: 200, body: `{}`}
+		var backoffs []int
+		client, err := newClient(Config{
+			Addresses: []string{"http://stub:9200"},
+			RetryBackoff: func(attempt int) time.Duration {
+				backoffs = append(backoffs, attempt)
+				return time.Millisecond
+			},
+		}, stub)
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+
+		if err := client.Ping(context.Background()); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+		if stub.calls != 3 {
+			t.Errorf("Expected 3 attempts, got %d", stub.calls)
+		}
+		if len(backoffs) != 2 || backoffs[0] != 1 || backoffs[1] != 2 {
+			t.Errorf("Backoff called with attempts %v, want [1 2]", backoffs)
+		}
+	})
+
+	t.Run("Gives up after MaxRetries", func(t *testing.T) {
+		stub := &stubTransport{status: 503, body: `{}`}
+		var backoffs int
+		client, err := newClient(Config{
+			Addresses:  []string{"http://stub:9200"},
+			MaxRetries: 2,
+			RetryBackoff: func(attempt int) time.Duration {
+				backoffs++
+				return time.Millisecond
+			},
+		}, stub)
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+
+		if err := client.Ping(context.Background()); err == nil {
+			t.Fatal("Ping() expected error but got nil")
+		}
+		if stub.calls != 3 {
+			t.Errorf("Expected 1 attempt and 2 retries, got %d attempts", stub.calls)
+		}
+		if backoffs != 2 {
+			t.Errorf("Expected 2 backoffs, got %d", backoffs)
+		}
+	})
+
+	t.Run("Status not in RetryOnStatus is not retried", func(t *testing.T) {
+		stub := &stubTransport{status: 500, body: `{}`}
+		client, err := newClient(Config{
+			Addresses:     []string{"http://stub:9200"},
+			RetryOnStatus: []int{503},
+		}, stub)
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+
+		_ = client.Ping(context.Background())
+		if stub.calls != 1 {
+			t.Errorf("Expected 1 attempt, got %d", stub.calls)
+		}
+	})
+
+	t.Run("DisableRetry", func(t *testing.T) {
+		stub := &stubTransport{status: 503, body: `{}`}
+		client, err := newClient(Config{
+			Addresses:    []string{"http://stub:9200"},
+			DisableRetry: true,
+		}, stub)
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+
+		_ = client.Ping(context.Background())
+		if stub.calls != 1 {
+			t.Errorf("Expected 1 attempt, got %d", stub.calls)
+		}
+	})
+
+	t.Run("Cancelled context stops retrying immediately", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		stub := &stubTransport{status: 503, body: `{}`}
+		stub.onRequest = cancel
+		client, err := newClient(Config{
+			Addresses: []string{"http://stub:9200"},
+			RetryBackoff: func(attempt int) time.Duration {
+				return time.Hour
+			},
+		}, stub)
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+
+		start := time.Now()
+		err = client.Ping(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Ping() error = %v, want context.Canceled", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Ping() took %s, want the backoff to be cut short", elapsed)
+		}
+		if stub.calls != 1 {
+			t.Errorf("Expected 1 attempt, got %d", stub.calls)
+		}
+	})
+}
+
+func TestRetryTransport_RetryAfter(t *testing.T) {
+	// throttled answers 429 with the given Retry-After the first time, then 200
+	throttled := func(retryAfter string, calls *int) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			*calls++
+			res := &http.Response{
+				StatusCode: 200,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{}`)),
+				Request:    req,
+			}
+			if *calls == 1 {
+				res.StatusCode = 429
+				res.Header.Set("Retry-After", retryAfter)
+			}
+			return res, nil
+		})
+	}
+	noBackoff := func(int) time.Duration { return 0 }
+
+	t.Run("Waits as long as asked", func(t *testing.T) {
+		calls := 0
+		client, err := newClient(Config{
+			Addresses:    []string{"http://stub:9200"},
+			RetryBackoff: noBackoff,
+		}, throttled("1", &calls))
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+
+		start := time.Now()
+		if _, err := client.BulkCreateChunked(context.Background(), "logs", []map[string]interface{}{{"msg": "hello"}}, 10); err != nil {
+			t.Fatalf("BulkCreateChunked() error = %v", err)
+		}
+		elapsed := time.Since(start)
+		if calls != 2 {
+			t.Errorf("Expected 2 attempts, got %d", calls)
+		}
+		if elapsed < time.Second || elapsed > 2*time.Second {
+			t.Errorf("Request took %v, want about the 1s asked for by Retry-After", elapsed)
+		}
+	})
+
+	t.Run("Capped by MaxRetryAfter", func(t *testing.T) {
+		calls := 0
+		client, err := newClient(Config{
+			Addresses:     []string{"http://stub:9200"},
+			RetryBackoff:  noBackoff,
+			MaxRetryAfter: 50 * time.Millisecond,
+		}, throttled("120", &calls))
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+
+		start := time.Now()
+		if err := client.Ping(context.Background()); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+			t.Errorf("Request took %v, want about the 50ms MaxRetryAfter", elapsed)
+		}
+	})
+
+	t.Run("Capped by the context deadline", func(t *testing.T) {
+		calls := 0
+		client, err := newClient(Config{
+			Addresses:    []string{"http://stub:9200"},
+			RetryBackoff: noBackoff,
+		}, throttled("120", &calls))
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		if err := client.Ping(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Ping() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Request took %v, want it to stop at the deadline", elapsed)
+		}
+		if calls != 1 {
+			t.Errorf("Expected 1 attempt, got %d", calls)
+		}
+	})
+}
+
+func TestRetryAfterDelay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "Seconds", header: "3", want: 3 * time.Second, wantOK: true},
+		{name: "HTTP date", header: "Mon, 01 Jan 2024 12:00:05 GMT", want: 5 * time.Second, wantOK: true},
+		{name: "Date in the past", header: "Mon, 01 Jan 2024 11:00:00 GMT", want: 0, wantOK: true},
+		{name: "Missing", header: "", wantOK: false},
+		{name: "Negative", header: "-1", wantOK: false},
+		{name: "Invalid", header: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{Header: http.Header{}}
+			if tt.header != "" {
+				res.Header.Set("Retry-After", tt.header)
+			}
+			got, ok := retryAfterDelay(res, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfterDelay(%q) = %v, %v, want %v, %v", tt.header, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDefaultRetryBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 100 * time.Millisecond},
+		{attempt: 2, want: 200 * time.Millisecond},
+		{attempt: 3, want: 400 * time.Millisecond},
+		{attempt: 10, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := defaultRetryBackoff(tt.attempt); got != tt.want {
+			t.Errorf("defaultRetryBackoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+// newSlowServer starts a server that answers after delay, or gives up when the client goes away
+func newSlowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"took":1,"hits":{"total":{"value":0},"hits":[]}}`))
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	// Cleanups run last-in first-out, so pending handlers are released before Close waits on them
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(done) })
+
+	return server
+}
+
+func TestRequestTimeout(t *testing.T) {
+	server := newSlowServer(t, 2*time.Second)
+
+	client, err := NewClient(Config{
+		Addresses:      []string{server.URL},
+		RequestTimeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	start := time.Now()
+	_, err = client.SearchDocuments(context.Background(), "slow-index", MatchAllQuery())
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SearchDocuments() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("SearchDocuments() took %s, want it bounded by the 100ms timeout", elapsed)
+	}
+}
+
+func TestRequestTimeout_ContextDeadline(t *testing.T) {
+	server := newSlowServer(t, 2*time.Second)
+
+	client, err := NewClient(Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.SearchDocuments(ctx, "slow-index", MatchAllQuery())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SearchDocuments() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("SearchDocuments() took %s, want it bounded by the context deadline", elapsed)
+	}
+}
+
+func TestDefaultOperationTimeout(t *testing.T) {
+	server := newSlowServer(t, 300*time.Millisecond)
+
+	client, err := NewClient(Config{
+		Addresses:               []string{server.URL},
+		DefaultOperationTimeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	t.Run("Applies without a deadline", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.SearchDocuments(context.Background(), "slow-index", MatchAllQuery())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("SearchDocuments() error = %v, want context.DeadlineExceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+			t.Errorf("SearchDocuments() took %s, want it bounded by the 100ms default", elapsed)
+		}
+	})
+
+	t.Run("Caller deadline is left alone", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := client.SearchDocuments(ctx, "slow-index", MatchAllQuery()); err != nil {
+			t.Errorf("SearchDocuments() with a longer caller deadline error = %v", err)
+		}
+	})
+
+	t.Run("Covers retries", func(t *testing.T) {
+		stub := &stubTransport{status: 503, body: `{}`}
+		client, err := newClient(Config{
+			Addresses:               []string{"http://stub:9200"},
+			MaxRetries:              100,
+			RetryBackoff:            func(int) time.Duration { return 20 * time.Millisecond },
+			DefaultOperationTimeout: 100 * time.Millisecond,
+		}, stub)
+		if err != nil {
+			t.Fatalf("newClient() error = %v", err)
+		}
+
+		if err := client.Ping(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Ping() error = %v, want context.DeadlineExceeded", err)
+		}
+		if stub.calls > 10 {
+			t.Errorf("Request was attempted %d times, want the retries cut off by the default timeout", stub.calls)
+		}
+	})
+}
+
+func TestWithRequestTimeout(t *testing.T) {
+	server := newSlowServer(t, 300*time.Millisecond)
+
+	client, err := NewClient(Config{
+		Addresses:      []string{server.URL},
+		RequestTimeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ctx := WithRequestTimeout(context.Background(), 5*time.Second)
+	if _, err := client.SearchDocuments(ctx, "slow-index", MatchAllQuery()); err != nil {
+		t.Errorf("SearchDocuments() with longer per-call timeout error = %v", err)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{}`}
+	client, err := newClient(Config{
+		Addresses:   []string{"http://gateway:9200"},
+		BearerToken: "static-token",
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if got := stub.header.Get("Authorization"); got != "Bearer static-token" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer static-token")
+	}
+}
+
+func TestTokenProvider(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{}`}
+	calls := 0
+	client, err := newClient(Config{
+		Addresses: []string{"http://gateway:9200"},
+		TokenProvider: func(ctx context.Context) (string, error) {
+			calls++
+			return fmt.Sprintf("token-%d", calls), nil
+		},
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		if err := client.Ping(context.Background()); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+		if got, want := stub.header.Get("Authorization"), fmt.Sprintf("Bearer token-%d", i); got != want {
+			t.Errorf("Request %d: Authorization = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestTokenProvider_Error(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{}`}
+	errExpired := errors.New("refresh token expired")
+	client, err := newClient(Config{
+		Addresses:    []string{"http://gateway:9200"},
+		DisableRetry: true,
+		TokenProvider: func(ctx context.Context) (string, error) {
+			return "", errExpired
+		},
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	if err := client.Ping(context.Background()); !errors.Is(err, errExpired) {
+		t.Errorf("Ping() error = %v, want %v", err, errExpired)
+	}
+	if stub.calls != 0 {
+		t.Errorf("Request sent %d times without a token, want 0", stub.calls)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	stub := &stubTransport{status: 200, body: `{}`}
+	client, err := newClient(Config{
+		Addresses: []string{"http://gateway:9200"},
+		Headers:   map[string]string{"X-Tenant": "team-a", "X-Source": "default"},
+	}, stub)
+	if err != nil {
+		t.Fatalf("newClient() error = %v", err)
+	}
+
+	t.Run("Static", func(t *testing.T) {
+		if err := client.Ping(context.Background()); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+		if got := stub.header.Get("X-Tenant"); got != "team-a" {
+			t.Errorf("X-Tenant = %q, want %q", got, "team-a")
+		}
+		if got := stub.header.Get("X-Request-ID"); got != "" {
+			t.Errorf("X-Request-ID = %q without WithHeader, want none", got)
+		}
+	})
+
+	t.Run("Per context", func(t *testing.T) {
+		parent := WithHeader(context.Background(), "X-Request-ID", "req-1")
+		ctx := WithHeader(parent, "X-Source", "batch-job")
+
+		if err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+		want := map[string]string{"X-Tenant": "team-a", "X-Request-ID": "req-1", "X-Source": "batch-job"}
+		for key, value := range want {
+			if got := stub.header.Get(key); got != value {
+				t.Errorf("%s = %q, want %q", key, got, value)
+			}
+		}
+
+		// Adding a header to a derived context leaves the parent's headers as they were
+		if err := client.Ping(parent); err != nil {
+			t.Fatalf("Ping() error = %v", err)
+		}
+		if got := stub.header.Get("X-Source"); got != "default" {
+			t.Errorf("X-Source with the parent context = %q, want %q", got, "default")
+		}
+	})
+}
+
+func TestBearerToken_WithBasicAuth(t *testing.T) {
+	_, err := NewClient(Config{
+		Addresses:   []string{"http://gateway:9200"},
+		Username:    "admin",
+		Password:    "admin",
+		BearerToken: "token",
+	})
+	if err == nil {
+		t.Error("NewClient() error = nil, want an error for combined authentication methods")
+	}
+}